@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+
+	// 内嵌时区数据库，避免精简镜像中缺少 /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+// migrate 显式执行数据库迁移，配合 database.auto_migrate: false 使用
+func main() {
+	// 加载配置
+	cfg, err := config.Load("configs/config.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 与服务保持一致，时区只作用于日志时间
+	location, err := time.LoadLocation(cfg.Server.TimeZone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "无效的时区配置 %q: %v\n", cfg.Server.TimeZone, err)
+		os.Exit(1)
+	}
+
+	// 初始化日志
+	if err := logger.Init(&cfg.Log, location); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化日志失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	db, err := repository.Open(&cfg.Database)
+	if err != nil {
+		logger.Fatal("连接数据库失败", zap.Error(err))
+	}
+
+	logger.Info("开始执行数据库迁移")
+	if err := repository.Migrate(db); err != nil {
+		logger.Fatal("数据库迁移失败", zap.Error(err))
+	}
+	logger.Info("数据库迁移完成")
+}
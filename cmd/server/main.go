@@ -1,13 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/cuihe500/astro/internal/container"
 	"github.com/cuihe500/astro/internal/handler"
-	"github.com/cuihe500/astro/internal/k8s"
 	"github.com/cuihe500/astro/internal/middleware"
-	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/internal/service"
 	"github.com/cuihe500/astro/pkg/config"
 	"github.com/cuihe500/astro/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -16,6 +18,8 @@ import (
 	"go.uber.org/zap"
 
 	_ "github.com/cuihe500/astro/docs"
+	// 内嵌时区数据库，避免精简镜像中缺少 /usr/share/zoneinfo
+	_ "time/tzdata"
 )
 
 // @title Astro API
@@ -38,8 +42,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 时区只作用于日志时间，不修改 time.Local，避免数据库连接（loc=Local）读写的时间发生偏移
+	location, err := time.LoadLocation(cfg.Server.TimeZone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "无效的时区配置 %q: %v\n", cfg.Server.TimeZone, err)
+		os.Exit(1)
+	}
+
 	// 初始化日志
-	if err := logger.Init(&cfg.Log); err != nil {
+	if err := logger.Init(&cfg.Log, location); err != nil {
 		fmt.Fprintf(os.Stderr, "初始化日志失败: %v\n", err)
 		os.Exit(1)
 	}
@@ -47,43 +58,99 @@ func main() {
 
 	logger.Info("Astro 服务启动中...")
 
-	// 初始化数据库
-	if err := repository.Init(&cfg.Database); err != nil {
-		logger.Fatal("初始化数据库失败", zap.Error(err))
+	// 初始化依赖（数据库、K8s 客户端）
+	c, err := container.New(cfg)
+	if err != nil {
+		logger.Fatal("初始化依赖失败", zap.Error(err))
 	}
-
-	// 初始化 K8s 客户端
-	if err := k8s.Init(cfg.Kubernetes.Kubeconfig); err != nil {
-		logger.Fatal("初始化 K8s 客户端失败", zap.Error(err))
+	if cfg.Mode == config.ModeDemo {
+		logger.Warn("以演示模式启动，使用内存数据库与模拟 K8s 集群，重启后数据丢失")
+	} else if c.K8sDegraded {
+		logger.Warn("K8s 集群不可达，以降级模式启动，应用相关接口暂不可用")
+	} else {
+		logger.Info("数据库与 K8s 客户端初始化成功")
 	}
-	logger.Info("K8s 客户端初始化成功")
 
 	// 设置运行模式
 	gin.SetMode(cfg.Server.Mode)
 
-	// 创建 Gin 引擎
-	r := gin.Default()
+	// 创建 Gin 引擎，访问日志带上请求 ID
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.AccessLog(), gin.Recovery())
+	// 未配置时不信任任何代理，避免客户端伪造 X-Forwarded-For
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("设置受信任代理失败", zap.Error(err))
+	}
+	r.Use(middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+	r.Use(middleware.Gzip(cfg.Server.Gzip))
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	// Swagger 文档
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// 就绪检查：数据库与 K8s 均可用时才接收流量
+	r.GET("/ready", func(ctx *gin.Context) {
+		if err := c.Ready(ctx.Request.Context()); err != nil {
+			ctx.JSON(503, gin.H{"status": "not_ready", "error": err.Error()})
+			return
+		}
+		ctx.JSON(200, gin.H{"status": "ready"})
+	})
+
+	// 版本信息
+	r.GET("/version", handler.GetVersion)
+
+	// Swagger 文档，生产环境（release 模式）默认关闭
+	if *cfg.Server.EnableSwagger {
+		swagger := r.Group("/swagger")
+		if auth := cfg.Server.SwaggerAuth; auth.Username != "" {
+			swagger.Use(gin.BasicAuth(gin.Accounts{auth.Username: auth.Password}))
+		}
+		swagger.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	// 审计日志（异步写入，全局唯一实例）
+	auditSvc := service.NewAuditService(c)
+
+	// 后台定期检查应用容器重启次数（未开启重启告警时不运行）
+	go service.NewAppService(c).RunRestartChecks(context.Background())
+
+	// 监控指标（K8s 熔断器状态、审计日志丢弃数）
+	r.GET("/metrics", handler.Metrics(c.Breaker, auditSvc))
 
 	// API 路由
 	api := r.Group("/api/v1")
+	api.Use(middleware.Audit(auditSvc))
 
 	// 公开路由（无需认证）
-	handler.RegisterUserRoutes(api)
+	handler.RegisterUserRoutes(api, c)
 
 	// 需要认证的路由
 	authApi := api.Group("")
-	authApi.Use(middleware.Auth())
+	authApi.Use(middleware.Auth(c))
 	{
+		// 用户管理路由
+		handler.RegisterUserAuthRoutes(authApi, c)
+
+		// 访问令牌路由
+		handler.RegisterTokenRoutes(authApi, c)
+
+		// 镜像仓库路由
+		handler.RegisterRegistryRoutes(authApi, middleware.RequireScope)
+
 		// 应用管理路由
-		handler.RegisterAppRoutes(authApi)
+		handler.RegisterAppRoutes(authApi, c, middleware.RequireScope)
+
+		// 首页统计路由
+		handler.RegisterDashboardRoutes(authApi, c, middleware.RequireScope)
+	}
+
+	// 管理员路由
+	adminApi := authApi.Group("")
+	adminApi.Use(middleware.Admin(c))
+	{
+		handler.RegisterAdminRoutes(adminApi, c, auditSvc)
 	}
 
 	// 启动服务
@@ -15,9 +15,306 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/login": {
+        "/admin/audit": {
+            "get": {
+                "description": "管理员分页查询审计日志，按时间倒序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理员"
+                ],
+                "summary": "查询审计日志",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "动作，如 app.create",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failure"
+                        ],
+                        "type": "string",
+                        "description": "结果",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（RFC3339）",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339）",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页条数，最大 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/handler.PageData"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "items": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/model.AuditLog"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/admin/dashboard/stats": {
+            "get": {
+                "description": "管理员查看所有用户按状态分组的应用数及副本总数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理员"
+                ],
+                "summary": "获取全平台应用统计",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.DashboardStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/admin/impersonate/{id}": {
+            "post": {
+                "description": "以目标用户身份签发 1 小时有效的 Token，供技术支持查看用户的应用。Token 的 act 声明记录实际操作的管理员，\n审计日志同时记录被模拟的用户与管理员；模拟登录不能修改账号信息或创建访问令牌，不能模拟管理员。\n与所有管理接口一样只接受登录凭证，访问令牌调用返回无权限",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理员"
+                ],
+                "summary": "模拟用户登录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限或目标为管理员",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/admin/namespaces/{ns}/usage": {
+            "get": {
+                "description": "管理员查看 Astro 创建的命名空间内的应用数、Pod 数、容器 requests/limits 之和及实时用量。\n实时用量来自 metrics-server，未安装或不可用时 usage 为空并在 metrics_error 中说明原因",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理员"
+                ],
+                "summary": "获取命名空间资源用量",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "命名空间",
+                        "name": "ns",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.NamespaceUsage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "命名空间不存在或不由 Astro 管理",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/admin/users/import": {
             "post": {
-                "description": "用户登录获取 Token",
+                "description": "管理员批量创建用户，每行的校验规则与注册一致，合法的行在同一事务中创建。\n用户名或邮箱已存在的行跳过并返回原因，校验失败的行记为 error，均不影响其他行；\n未提供密码时生成随机临时密码，仅在本次响应中返回",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,23 +322,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户"
+                    "管理员"
                 ],
-                "summary": "用户登录",
+                "summary": "批量导入用户",
                 "parameters": [
                     {
-                        "description": "登录信息",
+                        "description": "用户列表，最多 500 个",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
+                            "$ref": "#/definitions/handler.ImportUsersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "登录成功",
+                        "description": "成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -51,7 +348,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.LoginResponse"
+                                            "$ref": "#/definitions/handler.ImportUsersResponse"
                                         }
                                     }
                                 }
@@ -61,21 +358,44 @@ const docTemplate = `{
                     "400": {
                         "description": "参数错误",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ValidationErrorData"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "401": {
-                        "description": "认证失败",
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
                         "schema": {
                             "$ref": "#/definitions/handler.Response"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
             }
         },
-        "/register": {
-            "post": {
-                "description": "创建新用户账号",
+        "/admin/users/{id}/team": {
+            "put": {
+                "description": "管理员设置用户所属团队，team 为空表示移出团队。命名空间策略为 per-team 时，\n同一团队的用户共用命名空间 astro-team-\u003c团队名\u003e（团队名转小写、非字母数字替换为 -，并截断到合法长度）；\n只影响之后新建的应用，已有应用仍留在创建时的命名空间",
                 "consumes": [
                     "application/json"
                 ],
@@ -83,25 +403,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户"
+                    "管理员"
                 ],
-                "summary": "用户注册",
+                "summary": "设置用户所属团队",
                 "parameters": [
                     {
-                        "description": "注册信息",
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "团队",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterRequest"
+                            "$ref": "#/definitions/handler.SetUserTeamRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "注册成功",
+                        "description": "成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -110,58 +449,4765 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
-                    "500": {
-                        "description": "服务器错误",
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "无权限",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
                         "schema": {
                             "$ref": "#/definitions/handler.Response"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
             }
-        }
-    },
-    "definitions": {
-        "handler.LoginRequest": {
-            "type": "object",
+        },
+        "/apps": {
+            "get": {
+                "description": "获取当前用户的所有应用，支持按应用名模糊搜索",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "应用名关键字",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页条数，最大 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/handler.PageData"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "items": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/service.AppListItem"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "创建一个新的容器应用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "创建应用",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "幂等键（不超过 128 字符），24 小时内重复请求返回首次创建的应用",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "应用信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误，data.errors 为逐字段的错误",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ValidationErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/batch/restart": {
+            "post": {
+                "description": "批量滚动重启应用，不等待就绪，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "批量重启应用",
+                "parameters": [
+                    {
+                        "description": "应用 ID 列表（最多 50 个）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchAppsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "各应用的操作结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.BatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/batch/start": {
+            "post": {
+                "description": "批量启动应用，不等待就绪，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "批量启动应用",
+                "parameters": [
+                    {
+                        "description": "应用 ID 列表（最多 50 个）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchAppsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "各应用的操作结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.BatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/batch/stop": {
+            "post": {
+                "description": "批量停止应用，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "批量停止应用",
+                "parameters": [
+                    {
+                        "description": "应用 ID 列表（最多 50 个）",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchAppsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "各应用的操作结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.BatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/check-name": {
+            "get": {
+                "description": "创建前检查应用名格式及是否已被占用，不会创建任何资源；重复范围与创建时一致（默认为当前用户）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "检查应用名是否可用",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "应用名",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.CheckAppNameResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}": {
+            "get": {
+                "description": "获取指定应用的规格与 K8s 实时状态，K8s 不可达时返回上次同步的数据并标记 status_stale",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.AppDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除指定的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "删除应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "按 JSON Merge Patch 语义只修改请求中出现的字段，支持 replicas、image、image_pull_policy、env、metadata，其他字段返回参数错误。\n在当前配置上应用补丁并校验通过后，只同步发生变化的字段：镜像、拉取策略与环境变量合并为一次滚动更新，副本数变化与调整副本数接口相同；\nmetadata 按键合并，值为 null 的键被删除。返回更新后的应用。\nstrategy=recreate 时删除工作负载后按新配置重建：旧 Pod 全部删除后才创建新 Pod，期间应用不可用；\n有状态应用的持久卷声明一并删除，数据不可恢复。重建不要求请求体中有修改，定时任务应用不支持",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "部分更新应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "in-place",
+                            "recreate"
+                        ],
+                        "type": "string",
+                        "default": "in-place",
+                        "description": "更新方式：in-place 原地滚动更新，recreate 删除后重建",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "description": "需要修改的字段",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PatchAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.App"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误、未知字段或超出副本数范围",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ValidationErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/describe": {
+            "get": {
+                "description": "一次返回应用规格、实时状态（Pod、重启次数）、最近的 K8s 事件和日志末尾，类似 kubectl describe 加日志；\n各部分并发获取，失败的部分在 errors 中给出原因",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用诊断信息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "日志行数，不超过配置上限",
+                        "name": "lines",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.AppDescription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/env": {
+            "get": {
+                "description": "获取应用容器的环境变量（按写入顺序）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取环境变量",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.EnvVar"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "整体替换应用容器的环境变量并保存到应用记录。Deployment 与 StatefulSet 会滚动更新所有 Pod，定时任务从下一次调度开始生效。\n变量名需为合法的环境变量名且不重复，KUBERNETES_、ASTRO_ 为保留前缀；返回更新后的环境变量与发布状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "设置环境变量",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "环境变量，最多 100 个",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetAppEnvRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.AppEnv"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ValidationErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/logs": {
+            "get": {
+                "description": "获取指定应用的容器日志。容器重启过时先返回重启前的日志，再以 ` + "`" + `--- pod restarted ---` + "`" + ` 分隔行接上当前日志",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用日志",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "日志行数，不超过配置上限（默认 10000）",
+                        "name": "lines",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "聚合所有 Pod 的日志，每行以 [Pod 名] 开头，不同 Pod 之间不保证时间顺序；lines 为总行数",
+                        "name": "all_pods",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.AppLogsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/logs/follow": {
+            "get": {
+                "description": "升级为 WebSocket 连接，先推送第一个 Pod 日志的末尾 lines 行，之后每条文本消息为一行新日志。\n容器重启或 Pod 被替换后自动重连，先推送 ` + "`" + `--- pod restarted ---` + "`" + ` 分隔行，再从新容器的第一行开始推送；\n连续重连失败超过上限或客户端断开时关闭连接",
+                "tags": [
+                    "应用"
+                ],
+                "summary": "实时跟踪应用日志",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "初始推送的日志行数，不超过配置上限（默认 10000）",
+                        "name": "lines",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换协议，后续消息为日志行",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/manifests": {
+            "get": {
+                "description": "获取应用当前在集群中的 Deployment/Service 等资源（YAML），已去除 managedFields 和 status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用资源清单",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.AppManifestsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/pods": {
+            "get": {
+                "description": "列出应用当前的所有 Pod 及容器状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用 Pod 列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/k8s.PodInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/pods/{pod}": {
+            "get": {
+                "description": "获取指定 Pod 的状态、所在节点与 IP，Pod 不属于该应用时返回资源不存在",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用的单个 Pod",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pod 名称",
+                        "name": "pod",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/k8s.PodDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用或 Pod 不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除指定 Pod 并由工作负载重新创建，用于恢复卡住的单个副本而无需重启整个应用；Pod 不属于该应用时返回资源不存在",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "删除应用的单个 Pod",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pod 名称",
+                        "name": "pod",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用或 Pod 不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/restart": {
+            "post": {
+                "description": "重启指定的应用。rolling（默认）逐个替换 Pod，多副本时不中断服务，单副本仍会短暂中断；\nrecreate 立即删除所有 Pod 后重建，所有新 Pod 就绪前应用完全不可用，适合需要彻底重启的场景",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "重启应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "rolling",
+                            "recreate"
+                        ],
+                        "type": "string",
+                        "default": "rolling",
+                        "description": "重启方式",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否等待应用就绪后再返回",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 60,
+                        "description": "等待超时时间（秒），最大 600",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重启成功，等待模式下返回应用详情",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/resume": {
+            "post": {
+                "description": "恢复已挂起的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "恢复应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/revisions": {
+            "get": {
+                "description": "列出应用 Deployment 保留的历史版本（按版本号倒序）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "获取应用历史版本",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/k8s.Revision"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/rollback": {
+            "post": {
+                "description": "将应用回滚到指定历史版本，不指定版本时回滚到上一个版本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "回滚应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "回滚参数",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RollbackAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "回滚成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/k8s.AppStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/rollout/pause": {
+            "post": {
+                "description": "暂停 Deployment 的滚动更新（同 kubectl rollout pause），新旧版本 Pod 保持当前比例，便于检查新版本",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "暂停发布",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "暂停成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "非 Deployment 应用",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/rollout/resume": {
+            "post": {
+                "description": "继续已暂停的滚动更新（同 kubectl rollout resume）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "继续发布",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "继续成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "非 Deployment 应用",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/scale": {
+            "post": {
+                "description": "调整副本数并记为期望副本数，停止后再启动会恢复到该值；0 等同于停止",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "调整应用副本数",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "副本数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ScaleAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "调整成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误或超出副本数范围",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/scale-to-zero": {
+            "post": {
+                "description": "将应用副本数调整为 0 并标记为缩容到零状态，保留期望副本数，之后可通过唤醒接口恢复；定时任务应用不支持",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "缩容到零",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "缩容成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "定时任务应用不支持",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/start": {
+            "post": {
+                "description": "启动指定的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "启动应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否等待应用就绪后再返回",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 60,
+                        "description": "等待超时时间（秒），最大 600",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "启动成功，等待模式下返回应用详情",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/stop": {
+            "post": {
+                "description": "停止指定的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "停止应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "停止成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/suspend": {
+            "post": {
+                "description": "挂起应用，挂起期间平台不再同步应用状态，可手动调整集群资源",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "挂起应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "挂起成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/wake": {
+            "post": {
+                "description": "将已停止或缩容到零的应用恢复到停止前的期望副本数，状态置为 starting，配额检查与启动相同；\n应用未处于停止状态时返回参数错误。返回唤醒后的应用，等待模式下返回包含实时状态的应用详情",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用"
+                ],
+                "summary": "唤醒应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否等待应用就绪后再返回",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 60,
+                        "description": "等待超时时间（秒），最大 600",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "唤醒成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.App"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "应用未处于停止状态",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/apps/{id}/watch": {
+            "get": {
+                "description": "升级为 WebSocket 连接，应用 Deployment/Pod 变化时推送最新状态（JSON），应用被删除或客户端断开时关闭连接",
+                "tags": [
+                    "应用"
+                ],
+                "summary": "实时监听应用状态",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换协议，后续消息为应用状态",
+                        "schema": {
+                            "$ref": "#/definitions/k8s.AppStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/auth/introspect": {
+            "get": {
+                "description": "返回当前请求所用 Token 的用户、角色、签发与过期时间及权限范围，便于客户端安排续期",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "查看当前凭证",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.IntrospectResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权或 Token 无效",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/dashboard/stats": {
+            "get": {
+                "description": "返回当前用户按状态分组的应用数及副本总数，统计前会同步一批状态过期的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "首页"
+                ],
+                "summary": "获取应用统计",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.DashboardStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "使用用户名或邮箱登录获取 Token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户登录",
+                "parameters": [
+                    {
+                        "description": "登录信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "认证失败",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "以 Prometheus 文本格式输出 K8s 熔断器状态与审计日志丢弃数（路径不带 /api/v1 前缀）。\nastro_k8s_breaker_state 按 state 标签（closed/open/half-open）输出，当前状态为 1；另有连续失败次数与累计打开次数。\nastro_audit_dropped_total 为审计日志队列已满时累计丢弃的记录数",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "监控指标",
+                "responses": {
+                    "200": {
+                        "description": "指标",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "创建新用户账号",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "注册信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "注册成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/registry/test": {
+            "post": {
+                "description": "访问仓库 /v2/ 接口验证连通性与认证，凭证仅用于本次检查，不会保存；超时 10 秒。\n不允许访问回环、私有、链路本地等内部地址；Token 认证时只向与仓库同一主机的 https 认证地址发送凭证；\n无法访问时只返回通用错误，不返回上游状态码或网络错误。访问令牌需要 apps:write 权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "镜像仓库"
+                ],
+                "summary": "检查镜像仓库凭证",
+                "parameters": [
+                    {
+                        "description": "仓库地址与凭证",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TestRegistryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "检查通过",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "访问令牌权限不足",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/stacks": {
+            "get": {
+                "description": "分页获取当前用户的应用组",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "获取应用组列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页条数，最大 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/handler.PageData"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "items": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/model.Stack"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "按清单一次创建多个应用并作为一组管理。创建前校验整个清单、应用名与配额，\n之后按顺序创建，任一应用创建失败时删除已创建的应用，不会留下部分应用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "创建应用组",
+                "parameters": [
+                    {
+                        "description": "应用组清单",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateStackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.StackDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误，data.errors 为逐字段的错误",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ValidationErrorData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/stacks/{id}": {
+            "get": {
+                "description": "获取应用组及其包含的应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "获取应用组详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用组ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.StackDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用组不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除应用组及其包含的所有应用；某个应用删除失败时保留应用组，可重试",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "删除应用组",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用组ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用组不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/stacks/{id}/apps": {
+            "get": {
+                "description": "获取应用组包含的应用，状态在后台异步同步，集群不可达时标记 status_stale",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "获取应用组内的应用",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用组ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.AppListItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用组不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/stacks/{id}/start": {
+            "post": {
+                "description": "启动应用组内的所有应用，不等待就绪；逐个返回结果，单个应用失败不影响其他应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "启动应用组",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用组ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "各应用的执行结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.BatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用组不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/stacks/{id}/stop": {
+            "post": {
+                "description": "停止应用组内的所有应用；逐个返回结果，单个应用失败不影响其他应用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "应用组"
+                ],
+                "summary": "停止应用组",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用组ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "各应用的执行结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.BatchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "应用组不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/tokens": {
+            "get": {
+                "description": "获取当前用户的访问令牌（不含明文）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问令牌"
+                ],
+                "summary": "获取访问令牌列表",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PersonalAccessToken"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "创建长期有效的个人访问令牌，供 CI 等自动化场景使用；明文令牌仅返回一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问令牌"
+                ],
+                "summary": "创建访问令牌",
+                "parameters": [
+                    {
+                        "description": "令牌信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.CreateTokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "不能使用访问令牌创建令牌",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "description": "吊销当前用户的访问令牌，立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问令牌"
+                ],
+                "summary": "吊销访问令牌",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "令牌ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "吊销成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "不能使用访问令牌吊销令牌",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "令牌不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/users/email": {
+            "post": {
+                "description": "修改当前登录用户的邮箱",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户"
+                ],
+                "summary": "修改邮箱",
+                "parameters": [
+                    {
+                        "description": "新邮箱",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "不能使用访问令牌修改",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ]
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "返回当前服务的版本号、Git 提交、构建时间和 Go 版本（路径不带 /api/v1 前缀）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "获取版本信息",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/version.Info"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AppLogsResponse": {
+            "type": "object",
+            "properties": {
+                "logs": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AppManifestsResponse": {
+            "type": "object",
+            "properties": {
+                "manifests": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.BatchAppsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "应用 ID 列表，重复的 ID 只处理一次",
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "handler.CheckAppNameResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "名称合法且未被占用",
+                    "type": "boolean",
+                    "example": true
+                },
+                "reason": {
+                    "description": "不可用的原因",
+                    "type": "string",
+                    "example": "应用已存在"
+                },
+                "valid": {
+                    "description": "名称格式是否合法",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.CreateAppRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "pre_stop_command"
+            ],
+            "properties": {
+                "backoff_limit": {
+                    "description": "定时任务单次执行的失败重试次数，超过后该次任务标记为失败，仅 cronjob 可用，留空使用 K8s 默认值（6）",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 3
+                },
+                "create_service_account": {
+                    "description": "ServiceAccount 不存在时自动创建（不附带任何权限），已存在时不做修改",
+                    "type": "boolean",
+                    "example": false
+                },
+                "deployment_annotations": {
+                    "description": "Deployment 注解，供 ArgoCD/Flux、成本分摊等外部工具识别",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "dns_config": {
+                    "description": "自定义 DNS 配置，与 dns_policy 生成的配置合并",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.DNSConfigRequest"
+                        }
+                    ]
+                },
+                "dns_policy": {
+                    "description": "Pod DNS 策略，留空使用 K8s 默认值（ClusterFirst）；None 时完全使用 dns_config，需至少一个 nameserver",
+                    "type": "string",
+                    "enum": [
+                        "ClusterFirst",
+                        "ClusterFirstWithHostNet",
+                        "Default",
+                        "None"
+                    ],
+                    "example": "ClusterFirst"
+                },
+                "env": {
+                    "description": "容器环境变量，创建后可通过环境变量接口修改",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                },
+                "headless": {
+                    "description": "创建 Headless Service（ClusterIP: None），需同时设置 port，statefulset 始终使用 Headless Service；\n\u003cname\u003e.\u003cnamespace\u003e.svc.cluster.local 将直接解析为所有就绪 Pod 的 IP，适合客户端自行负载均衡或集群发现",
+                    "type": "boolean",
+                    "example": false
+                },
+                "hostname": {
+                    "description": "Pod 主机名与子域名（DNS 标签），留空时主机名为 Pod 名；有状态应用由 K8s 固定为 Pod 名，不可设置",
+                    "type": "string",
+                    "example": "legacy-host"
+                },
+                "image": {
+                    "description": "与 source 二选一",
+                    "type": "string",
+                    "example": "nginx:latest"
+                },
+                "image_pull_policy": {
+                    "description": "镜像拉取策略，留空使用平台默认值；需要重启后拉取同名 tag 的新镜像时使用 Always",
+                    "type": "string",
+                    "enum": [
+                        "Always",
+                        "IfNotPresent",
+                        "Never"
+                    ],
+                    "example": "IfNotPresent"
+                },
+                "kind": {
+                    "description": "工作负载类型，statefulset 为每个副本提供稳定标识与独立存储，cronjob 定时运行且不创建 Service",
+                    "type": "string",
+                    "enum": [
+                        "deployment",
+                        "statefulset",
+                        "cronjob"
+                    ],
+                    "example": "deployment"
+                },
+                "metadata": {
+                    "description": "自定义元数据（JSON 对象），如描述、团队、链接，仅平台内展示，不会写入 K8s",
+                    "type": "object"
+                },
+                "min_ready_seconds": {
+                    "description": "新 Pod 持续就绪多少秒后才视为可用，滚动更新在此之后才替换下一个旧 Pod，可减少发布期间流量抖动；\n需小于 progress_deadline_seconds，定时任务不可用，留空使用平台默认值",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0,
+                    "example": 10
+                },
+                "name": {
+                    "type": "string",
+                    "example": "my-nginx"
+                },
+                "port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "pre_stop_command": {
+                    "description": "容器停止前执行的命令，如 [\"sh\", \"-c\", \"sleep 10\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "progress_deadline_seconds": {
+                    "description": "滚动更新超时秒数，超时视为发布失败，留空使用平台默认值",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 600
+                },
+                "replicas": {
+                    "description": "留空使用平台默认值，范围由平台配置（默认 0~10）",
+                    "type": "integer",
+                    "example": 2
+                },
+                "restart_policy": {
+                    "description": "Pod 重启策略：Deployment 只能为 Always（K8s 限制，可不填）；cronjob 可选 OnFailure（默认，失败时原地重启容器）或 Never（失败时创建新 Pod 重试）",
+                    "type": "string",
+                    "enum": [
+                        "Always",
+                        "OnFailure",
+                        "Never"
+                    ],
+                    "example": "OnFailure"
+                },
+                "revision_history_limit": {
+                    "description": "保留的历史版本数，用于回滚，留空使用平台默认值",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "schedule": {
+                    "description": "kind 为 cronjob 时必填，标准 5 段 cron 表达式",
+                    "type": "string",
+                    "example": "*/5 * * * *"
+                },
+                "security_context": {
+                    "description": "容器安全上下文，未设置的字段使用平台默认值",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.SecurityContextRequest"
+                        }
+                    ]
+                },
+                "service_account_name": {
+                    "description": "Pod 使用的 ServiceAccount，用于访问 K8s API 或云厂商 IAM（IRSA/Workload Identity），留空使用命名空间的 default",
+                    "type": "string",
+                    "example": "my-app"
+                },
+                "service_annotations": {
+                    "description": "Service 注解，如云厂商负载均衡配置",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "description": "从 Git 仓库构建镜像后部署，与 image 二选一，需平台启用源码构建；构建期间应用状态为 building，失败为 build_failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.SourceRequest"
+                        }
+                    ]
+                },
+                "spread": {
+                    "description": "副本打散：node 尽量分布到不同节点，zone 尽量分布到不同可用区，留空不限制；定时任务不可用",
+                    "type": "string",
+                    "enum": [
+                        "node",
+                        "zone"
+                    ],
+                    "example": "node"
+                },
+                "storage": {
+                    "description": "有状态应用的持久卷配置，kind 为 statefulset 时必填",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.StorageRequest"
+                        }
+                    ]
+                },
+                "subdomain": {
+                    "type": "string",
+                    "example": "legacy"
+                },
+                "termination_grace_period_seconds": {
+                    "description": "优雅退出等待秒数，留空使用 K8s 默认值（30）",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1,
+                    "example": 60
+                },
+                "working_dir": {
+                    "description": "容器工作目录（绝对路径），留空使用镜像中的 WORKDIR",
+                    "type": "string",
+                    "maxLength": 256,
+                    "example": "/app"
+                }
+            }
+        },
+        "handler.CreateStackRequest": {
+            "type": "object",
+            "required": [
+                "apps",
+                "name"
+            ],
+            "properties": {
+                "apps": {
+                    "description": "应用列表，字段与创建应用相同（不支持 source）；字符串字段中的 ${svc:\u003c应用名\u003e} 会替换为该应用 Service 的集群内域名，\n支持 env 与 service_annotations/deployment_annotations 的值、pre_stop_command 与 metadata",
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.CreateAppRequest"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "shop"
+                }
+            }
+        },
+        "handler.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "description": "0 表示永不过期",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 90
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "gitlab-ci"
+                },
+                "scopes": {
+                    "description": "为空表示不限制",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "apps:read"
+                    ]
+                }
+            }
+        },
+        "handler.CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "info": {
+                    "$ref": "#/definitions/model.PersonalAccessToken"
+                },
+                "token": {
+                    "description": "明文令牌，仅在创建时返回一次",
+                    "type": "string",
+                    "example": "astro_pat_3f9a..."
+                }
+            }
+        },
+        "handler.DNSConfigRequest": {
+            "type": "object",
+            "properties": {
+                "nameservers": {
+                    "description": "DNS 服务器 IP，最多 3 个",
+                    "type": "array",
+                    "maxItems": 3,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "10.0.0.10"
+                    ]
+                },
+                "options": {
+                    "description": "resolver 选项，如 {\"ndots\": \"2\"}，值为空表示无值选项",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "searches": {
+                    "description": "搜索域，最多 32 个",
+                    "type": "array",
+                    "maxItems": 32,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "corp.example.com"
+                    ]
+                }
+            }
+        },
+        "handler.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段路径，与请求 JSON 一致，如 dns_config.nameservers[0]；请求体整体错误时为空",
+                    "type": "string",
+                    "example": "storage.size"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "不能为空"
+                }
+            }
+        },
+        "handler.ImportUserItem": {
+            "type": "object",
+            "required": [
+                "email",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "description": "临时密码，为空时随机生成",
+                    "type": "string",
+                    "example": "Password123"
+                },
+                "role": {
+                    "description": "默认 user",
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "admin"
+                    ],
+                    "example": "user"
+                },
+                "username": {
+                    "description": "不能包含 @，以便登录时区分邮箱",
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "handler.ImportUsersRequest": {
+            "type": "object",
+            "required": [
+                "users"
+            ],
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.ImportUserItem"
+                    }
+                }
+            }
+        },
+        "handler.ImportUsersResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "results": {
+                    "description": "按请求顺序排列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ImportUserResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.IntrospectResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "管理员模拟登录时为实际操作的管理员 ID",
+                    "type": "integer"
+                },
+                "auth_type": {
+                    "description": "jwt（登录 Token）/pat（个人访问令牌）",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "为空表示永不过期",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "剩余有效秒数",
+                    "type": "integer"
+                },
+                "issued_at": {
+                    "description": "早期签发的 Token 可能为空",
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "访问令牌的权限范围，为空表示不限制",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "remember": {
+                    "description": "记住我，为 true 时签发长有效期 Token",
+                    "type": "boolean",
+                    "example": false
+                },
+                "username": {
+                    "description": "用户名或邮箱",
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "handler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Token 过期时间，客户端可据此提前刷新",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "expires_in": {
+                    "description": "Token 剩余有效秒数",
+                    "type": "integer",
+                    "example": 86400
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "handler.PageData": {
+            "type": "object",
+            "properties": {
+                "items": {},
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.PatchAppRequest": {
+            "type": "object",
+            "properties": {
+                "env": {
+                    "description": "整体替换环境变量，null 表示清空",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                },
+                "image": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "nginx:1.27"
+                },
+                "image_pull_policy": {
+                    "type": "string",
+                    "enum": [
+                        "Always",
+                        "IfNotPresent",
+                        "Never"
+                    ],
+                    "example": "Always"
+                },
+                "metadata": {
+                    "description": "按键合并到现有元数据，值为 null 的键被删除，整体为 null 表示清空",
+                    "type": "object"
+                },
+                "replicas": {
+                    "description": "0 表示停止，范围由平台配置",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                }
+            }
+        },
+        "handler.RegisterRequest": {
+            "type": "object",
             "required": [
+                "email",
                 "password",
                 "username"
             ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "description": "不能包含 @，以便登录时区分邮箱",
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "handler.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID 仅错误响应返回，反馈问题时提供该 ID 即可在服务端日志中定位",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RollbackAppRequest": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "description": "目标版本，0 或不传表示上一个版本",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                }
+            }
+        },
+        "handler.ScaleAppRequest": {
+            "type": "object",
+            "required": [
+                "replicas"
+            ],
+            "properties": {
+                "replicas": {
+                    "description": "0 表示停止，范围由平台配置",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                }
+            }
+        },
+        "handler.SecurityContextRequest": {
+            "type": "object",
+            "properties": {
+                "drop_capabilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ALL"
+                    ]
+                },
+                "read_only_root_filesystem": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "run_as_non_root": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "run_as_user": {
+                    "type": "integer",
+                    "maximum": 2147483647,
+                    "minimum": 0,
+                    "example": 1000
+                }
+            }
+        },
+        "handler.SetAppEnvRequest": {
+            "type": "object",
+            "properties": {
+                "env": {
+                    "description": "为空表示清空所有环境变量",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                }
+            }
+        },
+        "handler.SetUserTeamRequest": {
+            "type": "object",
+            "properties": {
+                "team": {
+                    "description": "为空表示移出团队",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Platform"
+                }
+            }
+        },
+        "handler.SourceRequest": {
+            "type": "object",
+            "required": [
+                "git_url"
+            ],
+            "properties": {
+                "dockerfile": {
+                    "description": "相对仓库根目录的路径，留空为 Dockerfile",
+                    "type": "string",
+                    "example": "Dockerfile"
+                },
+                "git_url": {
+                    "description": "仅支持 https 公开仓库",
+                    "type": "string",
+                    "example": "https://github.com/acme/web.git"
+                },
+                "ref": {
+                    "description": "分支名或完整 ref（如 refs/tags/v1.0），留空为默认分支",
+                    "type": "string",
+                    "example": "main"
+                }
+            }
+        },
+        "handler.StorageRequest": {
+            "type": "object",
+            "required": [
+                "size"
+            ],
+            "properties": {
+                "mount_path": {
+                    "description": "留空为 /data",
+                    "type": "string",
+                    "example": "/data"
+                },
+                "size": {
+                    "type": "string",
+                    "example": "10Gi"
+                },
+                "storage_class": {
+                    "description": "留空使用集群默认存储类",
+                    "type": "string",
+                    "example": "standard"
+                }
+            }
+        },
+        "handler.TestRegistryRequest": {
+            "type": "object",
+            "required": [
+                "registry"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "registry": {
+                    "type": "string",
+                    "example": "registry.example.com"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "robot"
+                }
+            }
+        },
+        "handler.UpdateEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "new@example.com"
+                }
+            }
+        },
+        "handler.ValidationErrorData": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.FieldError"
+                    }
+                }
+            }
+        },
+        "k8s.AppStatus": {
+            "type": "object",
+            "properties": {
+                "pods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/k8s.PodInfo"
+                    }
+                },
+                "ready_replicas": {
+                    "type": "integer"
+                },
+                "replicas": {
+                    "type": "integer"
+                },
+                "restart_count": {
+                    "description": "所有 Pod 容器重启次数之和",
+                    "type": "integer"
+                },
+                "rollout_paused": {
+                    "description": "Deployment 滚动更新已暂停",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "pending/running/stopped/starting/restarting/rollout_failed/unknown",
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.BuildStatus": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "job": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "失败时为构建日志末尾",
+                    "type": "string"
+                },
+                "phase": {
+                    "description": "running/succeeded/failed",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.ContainerStatus": {
+            "type": "object",
+            "properties": {
+                "exit_code": {
+                    "description": "已终止时的退出码",
+                    "type": "integer"
+                },
+                "last_termination": {
+                    "description": "LastTermination 上一次终止的信息，CrashLoopBackOff 时当前状态为 waiting，崩溃原因在这里",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.ContainerTermination"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "当前状态的详细信息，已终止时为容器的终止消息",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "如 CrashLoopBackOff、OOMKilled",
+                    "type": "string"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "running/waiting/terminated/unknown",
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.ContainerTermination": {
+            "type": "object",
+            "properties": {
+                "exit_code": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "终止消息，FallbackToLogsOnError 策略下可能为日志末尾",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "如 Error、OOMKilled、Completed",
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.Event": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "object": {
+                    "description": "事件关联的对象，如 Pod/my-app-7d9c-abcde",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "如 BackOff、FailedScheduling",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Normal/Warning",
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.PodDetail": {
+            "type": "object",
+            "properties": {
+                "container_statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/k8s.ContainerStatus"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "node_name": {
+                    "type": "string"
+                },
+                "ordinal": {
+                    "description": "StatefulSet 副本序号",
+                    "type": "integer"
+                },
+                "pod_ip": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "restart_count": {
+                    "description": "Pod 内所有容器重启次数之和",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.PodInfo": {
+            "type": "object",
+            "properties": {
+                "container_statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/k8s.ContainerStatus"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ordinal": {
+                    "description": "StatefulSet 副本序号",
+                    "type": "integer"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "restart_count": {
+                    "description": "Pod 内所有容器重启次数之和",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "k8s.ResourceAmounts": {
+            "type": "object",
+            "properties": {
+                "cpu_millis": {
+                    "description": "CPU，单位毫核",
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "description": "内存，单位字节",
+                    "type": "integer"
+                }
+            }
+        },
+        "k8s.Revision": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.App": {
+            "type": "object",
+            "properties": {
+                "backoff_limit": {
+                    "description": "cronjob 单次任务失败重试次数",
+                    "type": "integer"
+                },
+                "build_job": {
+                    "description": "最近一次构建的 Job 名",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deployment_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "desired_replicas": {
+                    "description": "用户期望的副本数，停止后保留，启动时据此恢复",
+                    "type": "integer"
+                },
+                "dns_nameservers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dns_options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "dns_policy": {
+                    "description": "Pod DNS 策略与自定义 DNS 配置，为空使用 K8s 默认值",
+                    "type": "string"
+                },
+                "dns_searches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "env": {
+                    "description": "Env 容器环境变量，按顺序写入容器，后面的变量可通过 $(NAME) 引用前面的变量",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                },
+                "headless": {
+                    "description": "Service 是否为 Headless（ClusterIP: None）",
+                    "type": "boolean"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "image_pull_policy": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "deployment/cronjob",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "description": "最近一次从 K8s 同步状态的时间，供前端展示状态新鲜度",
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata 用户自定义元数据（JSON 对象），仅用于平台内组织展示，不写入 K8s",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "唯一范围由 app.name_scope 决定，软删除后可复用，因此不建唯一索引",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pre_stop_command": {
+                    "description": "容器停止前执行的命令",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "replicas": {
+                    "description": "当前副本数，停止后为 0",
+                    "type": "integer"
+                },
+                "restart_policy": {
+                    "description": "cronjob 的 Pod 重启策略 OnFailure/Never",
+                    "type": "string"
+                },
+                "scaled_to_zero": {
+                    "description": "已缩容到零，保留期望副本数，可通过唤醒接口恢复",
+                    "type": "boolean"
+                },
+                "schedule": {
+                    "description": "cronjob 的 cron 表达式",
+                    "type": "string"
+                },
+                "service_account_name": {
+                    "description": "Pod 使用的 ServiceAccount，为空使用命名空间的 default",
+                    "type": "string"
+                },
+                "service_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "source_dockerfile": {
+                    "type": "string"
+                },
+                "source_git_url": {
+                    "description": "源码构建信息，从 Git 仓库构建的应用才有值",
+                    "type": "string"
+                },
+                "source_ref": {
+                    "type": "string"
+                },
+                "spread": {
+                    "description": "副本打散维度 node/zone",
+                    "type": "string"
+                },
+                "stack_id": {
+                    "description": "所属应用组，单独创建的应用为空",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "storage_class": {
+                    "type": "string"
+                },
+                "storage_mount_path": {
+                    "type": "string"
+                },
+                "storage_size": {
+                    "description": "statefulset 每个副本的持久卷配置",
+                    "type": "string"
+                },
+                "subdomain": {
+                    "type": "string"
+                },
+                "suspended": {
+                    "description": "挂起后平台不再同步状态",
+                    "type": "boolean"
+                },
+                "termination_grace_period_seconds": {
+                    "description": "TerminationGracePeriodSeconds 优雅退出等待秒数，为空使用 K8s 默认值（30）",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "working_dir": {
+                    "description": "容器工作目录与 Pod 主机名、子域名，为空使用镜像或 K8s 默认值",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "description": "模拟登录时实际操作的管理员",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "success/failure",
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.EnvVar": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "LOG_LEVEL"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "info"
+                }
+            }
+        },
+        "model.PersonalAccessToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "为空表示永不过期",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "令牌前几位，便于用户辨认",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "为空表示不限制",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Stack": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "同一用户内唯一",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "成员应用所在的命名空间，${svc:\u003c应用名\u003e} 据此解析",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_apps": {
+                    "description": "配额覆盖，0 表示使用全局配置",
+                    "type": "integer"
+                },
+                "max_replicas": {
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "user/admin",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AppDescription": {
+            "type": "object",
+            "properties": {
+                "app": {
+                    "$ref": "#/definitions/model.App"
+                },
+                "errors": {
+                    "description": "获取失败的部分，键为 live/events/logs",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/k8s.Event"
+                    }
+                },
+                "live": {
+                    "$ref": "#/definitions/k8s.AppStatus"
+                },
+                "logs": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AppDetail": {
+            "type": "object",
+            "properties": {
+                "backoff_limit": {
+                    "description": "cronjob 单次任务失败重试次数",
+                    "type": "integer"
+                },
+                "build": {
+                    "description": "最近一次源码构建的状态，构建 Job 已过期清理时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.BuildStatus"
+                        }
+                    ]
+                },
+                "build_job": {
+                    "description": "最近一次构建的 Job 名",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deployment_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "desired_replicas": {
+                    "description": "用户期望的副本数，停止后保留，启动时据此恢复",
+                    "type": "integer"
+                },
+                "dns_nameservers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dns_options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "dns_policy": {
+                    "description": "Pod DNS 策略与自定义 DNS 配置，为空使用 K8s 默认值",
+                    "type": "string"
+                },
+                "dns_searches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "env": {
+                    "description": "Env 容器环境变量，按顺序写入容器，后面的变量可通过 $(NAME) 引用前面的变量",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                },
+                "headless": {
+                    "description": "Service 是否为 Headless（ClusterIP: None）",
+                    "type": "boolean"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "image_pull_policy": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "deployment/cronjob",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "description": "最近一次从 K8s 同步状态的时间，供前端展示状态新鲜度",
+                    "type": "string"
+                },
+                "live": {
+                    "description": "实时状态（Pod、就绪副本数等），K8s 不可达时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.AppStatus"
+                        }
+                    ]
+                },
+                "metadata": {
+                    "description": "Metadata 用户自定义元数据（JSON 对象），仅用于平台内组织展示，不写入 K8s",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "唯一范围由 app.name_scope 决定，软删除后可复用，因此不建唯一索引",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pre_stop_command": {
+                    "description": "容器停止前执行的命令",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "replicas": {
+                    "description": "当前副本数，停止后为 0",
+                    "type": "integer"
+                },
+                "restart_policy": {
+                    "description": "cronjob 的 Pod 重启策略 OnFailure/Never",
+                    "type": "string"
+                },
+                "scaled_to_zero": {
+                    "description": "已缩容到零，保留期望副本数，可通过唤醒接口恢复",
+                    "type": "boolean"
+                },
+                "schedule": {
+                    "description": "cronjob 的 cron 表达式",
+                    "type": "string"
+                },
+                "service_account_name": {
+                    "description": "Pod 使用的 ServiceAccount，为空使用命名空间的 default",
+                    "type": "string"
+                },
+                "service_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "source_dockerfile": {
+                    "type": "string"
+                },
+                "source_git_url": {
+                    "description": "源码构建信息，从 Git 仓库构建的应用才有值",
+                    "type": "string"
+                },
+                "source_ref": {
+                    "type": "string"
+                },
+                "spread": {
+                    "description": "副本打散维度 node/zone",
+                    "type": "string"
+                },
+                "stack_id": {
+                    "description": "所属应用组，单独创建的应用为空",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_stale": {
+                    "description": "为 true 表示未能获取实时状态，status 字段为上次同步结果",
+                    "type": "boolean"
+                },
+                "storage_class": {
+                    "type": "string"
+                },
+                "storage_mount_path": {
+                    "type": "string"
+                },
+                "storage_size": {
+                    "description": "statefulset 每个副本的持久卷配置",
+                    "type": "string"
+                },
+                "subdomain": {
+                    "type": "string"
+                },
+                "suspended": {
+                    "description": "挂起后平台不再同步状态",
+                    "type": "boolean"
+                },
+                "termination_grace_period_seconds": {
+                    "description": "TerminationGracePeriodSeconds 优雅退出等待秒数，为空使用 K8s 默认值（30）",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "working_dir": {
+                    "description": "容器工作目录与 Pod 主机名、子域名，为空使用镜像或 K8s 默认值",
+                    "type": "string"
+                }
+            }
+        },
+        "service.AppEnv": {
+            "type": "object",
+            "properties": {
+                "env": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
                 },
-                "username": {
-                    "type": "string",
-                    "example": "johndoe"
+                "status": {
+                    "description": "更新后的实时状态，集群暂不可达时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.AppStatus"
+                        }
+                    ]
                 }
             }
         },
-        "handler.LoginResponse": {
+        "service.AppListItem": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                "backoff_limit": {
+                    "description": "cronjob 单次任务失败重试次数",
+                    "type": "integer"
+                },
+                "build_job": {
+                    "description": "最近一次构建的 Job 名",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deployment_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "desired_replicas": {
+                    "description": "用户期望的副本数，停止后保留，启动时据此恢复",
+                    "type": "integer"
+                },
+                "dns_nameservers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dns_options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "dns_policy": {
+                    "description": "Pod DNS 策略与自定义 DNS 配置，为空使用 K8s 默认值",
+                    "type": "string"
+                },
+                "dns_searches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "env": {
+                    "description": "Env 容器环境变量，按顺序写入容器，后面的变量可通过 $(NAME) 引用前面的变量",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnvVar"
+                    }
+                },
+                "headless": {
+                    "description": "Service 是否为 Headless（ClusterIP: None）",
+                    "type": "boolean"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "image_pull_policy": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "deployment/cronjob",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "description": "最近一次从 K8s 同步状态的时间，供前端展示状态新鲜度",
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata 用户自定义元数据（JSON 对象），仅用于平台内组织展示，不写入 K8s",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "唯一范围由 app.name_scope 决定，软删除后可复用，因此不建唯一索引",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pre_stop_command": {
+                    "description": "容器停止前执行的命令",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "replicas": {
+                    "description": "当前副本数，停止后为 0",
+                    "type": "integer"
+                },
+                "restart_policy": {
+                    "description": "cronjob 的 Pod 重启策略 OnFailure/Never",
+                    "type": "string"
+                },
+                "scaled_to_zero": {
+                    "description": "已缩容到零，保留期望副本数，可通过唤醒接口恢复",
+                    "type": "boolean"
+                },
+                "schedule": {
+                    "description": "cronjob 的 cron 表达式",
+                    "type": "string"
+                },
+                "service_account_name": {
+                    "description": "Pod 使用的 ServiceAccount，为空使用命名空间的 default",
+                    "type": "string"
+                },
+                "service_annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "source_dockerfile": {
+                    "type": "string"
+                },
+                "source_git_url": {
+                    "description": "源码构建信息，从 Git 仓库构建的应用才有值",
+                    "type": "string"
+                },
+                "source_ref": {
+                    "type": "string"
+                },
+                "spread": {
+                    "description": "副本打散维度 node/zone",
+                    "type": "string"
+                },
+                "stack_id": {
+                    "description": "所属应用组，单独创建的应用为空",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_stale": {
+                    "description": "为 true 表示集群暂不可达，status 为上次同步结果",
+                    "type": "boolean"
+                },
+                "storage_class": {
+                    "type": "string"
+                },
+                "storage_mount_path": {
+                    "type": "string"
+                },
+                "storage_size": {
+                    "description": "statefulset 每个副本的持久卷配置",
+                    "type": "string"
+                },
+                "subdomain": {
+                    "type": "string"
+                },
+                "suspended": {
+                    "description": "挂起后平台不再同步状态",
+                    "type": "boolean"
+                },
+                "termination_grace_period_seconds": {
+                    "description": "TerminationGracePeriodSeconds 优雅退出等待秒数，为空使用 K8s 默认值（30）",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "working_dir": {
+                    "description": "容器工作目录与 Pod 主机名、子域名，为空使用镜像或 K8s 默认值",
+                    "type": "string"
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "service.BatchResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "失败时的错误码，成功为 0",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "失败原因",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "service.DashboardStats": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "description": "各状态的应用数，如 running/stopped/pending",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "status_stale": {
+                    "description": "为 true 表示集群暂不可达，状态为上次同步结果",
+                    "type": "boolean"
+                },
+                "total_apps": {
+                    "type": "integer"
+                },
+                "total_replicas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.ImportUserResult": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
                 "email": {
                     "type": "string",
                     "example": "john@example.com"
                 },
-                "password": {
+                "index": {
+                    "description": "在请求 users 中的下标",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reason": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "用户名已存在"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "skipped",
+                        "error"
+                    ],
+                    "example": "created"
+                },
+                "temp_password": {
+                    "description": "仅在未提供密码时返回，只显示这一次",
+                    "type": "string",
+                    "example": "xK3#pQ9!mZ2$wR7a"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
                 },
                 "username": {
                     "type": "string",
@@ -169,15 +5215,102 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.Response": {
+        "service.NamespaceUsage": {
             "type": "object",
             "properties": {
-                "code": {
+                "apps": {
+                    "description": "平台记录中位于该命名空间的应用数",
                     "type": "integer"
                 },
-                "data": {},
-                "message": {
+                "limits": {
+                    "description": "未结束 Pod 的容器 limits 之和，未设置 limit 的容器不计入",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.ResourceAmounts"
+                        }
+                    ]
+                },
+                "metrics_error": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pods": {
+                    "description": "未结束的 Pod 数",
+                    "type": "integer"
+                },
+                "requests": {
+                    "description": "未结束 Pod 的容器 requests 之和",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.ResourceAmounts"
+                        }
+                    ]
+                },
+                "running_pods": {
+                    "description": "Running 状态的 Pod 数",
+                    "type": "integer"
+                },
+                "usage": {
+                    "description": "Usage 实时用量，来自 metrics-server；集群未安装或不可用时为空，原因见 MetricsError",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/k8s.ResourceAmounts"
+                        }
+                    ]
+                }
+            }
+        },
+        "service.StackDetail": {
+            "type": "object",
+            "properties": {
+                "apps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.App"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "同一用户内唯一",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "成员应用所在的命名空间，${svc:\u003c应用名\u003e} 据此解析",
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "version.Info": {
+            "type": "object",
+            "properties": {
+                "build_date": {
+                    "type": "string",
+                    "example": "2025-12-11T00:00:00Z"
+                },
+                "git_commit": {
+                    "type": "string",
+                    "example": "e0bbfe3"
+                },
+                "go_version": {
+                    "type": "string",
+                    "example": "go1.25.0"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.0.0"
                 }
             }
         }
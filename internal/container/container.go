@@ -0,0 +1,107 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"gorm.io/gorm"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Container 依赖容器，在 main 中构建一次后注入到 handler/service/middleware
+type Container struct {
+	Config  *config.Config
+	DB      *gorm.DB
+	Adapter k8s.AppAdapter
+	// Breaker K8s 熔断器，已包装进 Adapter，这里暴露供读接口判断是否降级
+	Breaker *k8s.CircuitBreaker
+	// K8sDegraded 启动时集群不可达但配置允许降级启动
+	K8sDegraded bool
+
+	k8sClient kubernetes.Interface
+	// demo 演示模式，K8s 为内存模拟集群
+	demo bool
+}
+
+// K8s 熔断默认参数：连续失败 5 次后 30 秒内不再请求集群
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// connectivityTimeout 启动与就绪检查时探测依赖的超时时间
+const connectivityTimeout = 10 * time.Second
+
+// New 根据配置依次初始化数据库与 K8s 适配器，并确认集群可达
+// 集群不可达时返回错误，开启 kubernetes.allow_degraded 时改为标记 K8sDegraded 后继续启动
+// 演示模式下使用内存模拟集群，不连接真实 K8s
+func New(cfg *config.Config) (*Container, error) {
+	db, err := repository.NewDB(&cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("初始化数据库失败: %w", err)
+	}
+
+	demo := cfg.Mode == config.ModeDemo
+	var client kubernetes.Interface
+	degraded := false
+	if demo {
+		client = fake.NewSimpleClientset()
+	} else {
+		client, err = k8s.NewClient(cfg.Kubernetes.Kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("初始化 K8s 客户端失败: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connectivityTimeout)
+		defer cancel()
+		if err := k8s.CheckConnectivity(ctx, client); err != nil {
+			if !cfg.Kubernetes.AllowDegraded {
+				return nil, err
+			}
+			degraded = true
+		}
+	}
+
+	threshold := cfg.Kubernetes.BreakerThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	cooldown, err := time.ParseDuration(cfg.Kubernetes.BreakerCooldown)
+	if err != nil || cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	breaker := k8s.NewCircuitBreaker(threshold, cooldown)
+
+	return &Container{
+		Config:      cfg,
+		DB:          db,
+		Adapter:     k8s.NewBreakerAdapter(k8s.NewClientGoAdapter(client, cfg.Kubernetes.DefaultLabels), breaker),
+		Breaker:     breaker,
+		K8sDegraded: degraded,
+		k8sClient:   client,
+		demo:        demo,
+	}, nil
+}
+
+// Ready 检查数据库与 K8s 集群当前是否可用，供就绪探针使用
+func (c *Container) Ready(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, connectivityTimeout)
+	defer cancel()
+
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("数据库不可用: %w", err)
+	}
+	if c.demo {
+		return nil
+	}
+	return k8s.CheckConnectivity(ctx, c.k8sClient)
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// AdminHandler 管理员处理器
+type AdminHandler struct {
+	auditSvc *service.AuditService
+	userSvc  *service.UserService
+}
+
+// NewAdminHandler 创建管理员处理器
+func NewAdminHandler(c *container.Container, auditSvc *service.AuditService) *AdminHandler {
+	return &AdminHandler{
+		auditSvc: auditSvc,
+		userSvc:  service.NewUserService(c),
+	}
+}
+
+// GetAuditLogs 查询审计日志
+// @Summary 查询审计日志
+// @Description 管理员分页查询审计日志，按时间倒序
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param user_id query int false "用户ID"
+// @Param action query string false "动作，如 app.create"
+// @Param result query string false "结果" Enums(success, failure)
+// @Param start query string false "开始时间（RFC3339）"
+// @Param end query string false "结束时间（RFC3339）"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页条数，最大 100" default(20)
+// @Success 200 {object} Response{data=PageData{items=[]model.AuditLog}} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限"
+// @Router /admin/audit [get]
+func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	filter := repository.AuditFilter{
+		Action: c.Query("action"),
+		Result: c.Query("result"),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			BadRequest(c, "无效的 user_id 参数")
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+	if start := c.Query("start"); start != "" {
+		if filter.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			BadRequest(c, "无效的 start 参数，需为 RFC3339 格式")
+			return
+		}
+	}
+	if end := c.Query("end"); end != "" {
+		if filter.End, err = time.Parse(time.RFC3339, end); err != nil {
+			BadRequest(c, "无效的 end 参数，需为 RFC3339 格式")
+			return
+		}
+	}
+
+	logs, total, err := h.auditSvc.List(filter, page, pageSize)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessPaged(c, logs, total, page, pageSize)
+}
+
+// Impersonate 模拟用户登录
+// @Summary 模拟用户登录
+// @Description 以目标用户身份签发 1 小时有效的 Token，供技术支持查看用户的应用。Token 的 act 声明记录实际操作的管理员，
+// @Description 审计日志同时记录被模拟的用户与管理员；模拟登录不能修改账号信息或创建访问令牌，不能模拟管理员。
+// @Description 与所有管理接口一样只接受登录凭证，访问令牌调用返回无权限
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} Response{data=LoginResponse} "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限或目标为管理员"
+// @Failure 404 {object} Response "用户不存在"
+// @Router /admin/impersonate/{id} [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的用户ID")
+		return
+	}
+	// 访问令牌（即使是只读令牌）不能换取目标用户的完整权限 Token
+	if c.GetString(ContextKeyAuthType) == AuthTypePAT {
+		Forbidden(c, "访问令牌不能用于模拟登录")
+		return
+	}
+	// 模拟登录的 Token 不能再发起模拟
+	if c.GetUint(ContextKeyActorID) > 0 {
+		Forbidden(c, "模拟登录不能再次模拟其他用户")
+		return
+	}
+
+	token, expiresAt, user, err := h.userSvc.Impersonate(c.GetUint("user_id"), uint(userID))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, LoginResponse{
+		Token:     token,
+		UUID:      user.UUID,
+		ExpiresAt: expiresAt,
+		ExpiresIn: int64(time.Until(expiresAt).Seconds()),
+	})
+}
+
+// ImportUserItem 批量导入的单个用户，校验规则与注册一致
+type ImportUserItem struct {
+	Username string `json:"username" binding:"required,excludes=@" example:"johndoe"` // 不能包含 @，以便登录时区分邮箱
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
+	Role     string `json:"role" binding:"omitempty,oneof=user admin" example:"user"` // 默认 user
+	Password string `json:"password" example:"Password123"`                           // 临时密码，为空时随机生成
+}
+
+// ImportUsersRequest 批量导入用户请求
+type ImportUsersRequest struct {
+	Users []ImportUserItem `json:"users" binding:"required,min=1,max=500"`
+}
+
+// ImportUsersResponse 批量导入用户响应
+type ImportUsersResponse struct {
+	Created int                        `json:"created" example:"2"`
+	Skipped int                        `json:"skipped" example:"1"`
+	Failed  int                        `json:"failed" example:"0"`
+	Results []service.ImportUserResult `json:"results"` // 按请求顺序排列
+}
+
+// ImportUsers 批量导入用户
+// @Summary 批量导入用户
+// @Description 管理员批量创建用户，每行的校验规则与注册一致，合法的行在同一事务中创建。
+// @Description 用户名或邮箱已存在的行跳过并返回原因，校验失败的行记为 error，均不影响其他行；
+// @Description 未提供密码时生成随机临时密码，仅在本次响应中返回
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body ImportUsersRequest true "用户列表，最多 500 个"
+// @Success 200 {object} Response{data=ImportUsersResponse} "成功"
+// @Failure 400 {object} Response{data=ValidationErrorData} "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限"
+// @Router /admin/users/import [post]
+func (h *AdminHandler) ImportUsers(c *gin.Context) {
+	// 可以创建管理员账号，只允许管理员本人以登录凭证操作
+	if c.GetString(ContextKeyAuthType) == AuthTypePAT {
+		Forbidden(c, "访问令牌不能用于导入用户")
+		return
+	}
+	if c.GetUint(ContextKeyActorID) > 0 {
+		Forbidden(c, "模拟登录不能导入用户")
+		return
+	}
+
+	var req ImportUsersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// 逐行校验，不合法的行直接记为错误，不阻止其他行导入
+	var resp ImportUsersResponse
+	rows := make([]service.ImportUserRow, 0, len(req.Users))
+	for i, item := range req.Users {
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			resp.Results = append(resp.Results, service.ImportUserResult{
+				Index:    i,
+				Username: item.Username,
+				Email:    item.Email,
+				Status:   service.ImportError,
+				Reason:   bindErrors(err).Error(),
+			})
+			continue
+		}
+		rows = append(rows, service.ImportUserRow{
+			Index:    i,
+			Username: item.Username,
+			Email:    item.Email,
+			Role:     item.Role,
+			Password: item.Password,
+		})
+	}
+
+	results, err := h.userSvc.ImportUsers(rows)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	resp.Results = append(resp.Results, results...)
+	sort.Slice(resp.Results, func(i, j int) bool { return resp.Results[i].Index < resp.Results[j].Index })
+	for _, r := range resp.Results {
+		switch r.Status {
+		case service.ImportCreated:
+			resp.Created++
+		case service.ImportSkipped:
+			resp.Skipped++
+		default:
+			resp.Failed++
+		}
+	}
+
+	Success(c, resp)
+}
+
+// SetUserTeamRequest 设置用户团队请求
+type SetUserTeamRequest struct {
+	Team string `json:"team" binding:"max=64" example:"Platform"` // 为空表示移出团队
+}
+
+// SetUserTeam 设置用户所属团队
+// @Summary 设置用户所属团队
+// @Description 管理员设置用户所属团队，team 为空表示移出团队。命名空间策略为 per-team 时，
+// @Description 同一团队的用户共用命名空间 astro-team-<团队名>（团队名转小写、非字母数字替换为 -，并截断到合法长度）；
+// @Description 只影响之后新建的应用，已有应用仍留在创建时的命名空间
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Param request body SetUserTeamRequest true "团队"
+// @Success 200 {object} Response{data=model.User} "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限"
+// @Failure 404 {object} Response "用户不存在"
+// @Router /admin/users/{id}/team [put]
+func (h *AdminHandler) SetUserTeam(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的用户ID")
+		return
+	}
+	var req SetUserTeamRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userSvc.SetTeam(uint(userID), req.Team)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	Success(c, user)
+}
+
+// RegisterAdminRoutes 注册管理员路由，调用方需挂载认证与管理员权限中间件
+func RegisterAdminRoutes(r *gin.RouterGroup, c *container.Container, auditSvc *service.AuditService) {
+	h := NewAdminHandler(c, auditSvc)
+	dashboard := NewDashboardHandler(c)
+	admin := r.Group("/admin")
+	{
+		admin.GET("/audit", h.GetAuditLogs)
+		admin.GET("/dashboard/stats", dashboard.GetClusterStats)
+		admin.GET("/namespaces/:ns/usage", dashboard.GetNamespaceUsage)
+		admin.POST("/impersonate/:id", h.Impersonate)
+		admin.POST("/users/import", h.ImportUsers)
+		admin.PUT("/users/:id/team", h.SetUserTeam)
+	}
+}
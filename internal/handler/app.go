@@ -2,30 +2,266 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/model"
 	"github.com/cuihe500/astro/internal/service"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// defaultMaxLogLines 未配置时单次查询日志的最大行数
+const defaultMaxLogLines = 10000
+
 // AppHandler 应用处理器
 type AppHandler struct {
-	svc *service.AppService
+	svc         *service.AppService
+	maxLogLines int64
 }
 
 // NewAppHandler 创建应用处理器
-func NewAppHandler() *AppHandler {
+func NewAppHandler(c *container.Container) *AppHandler {
+	maxLogLines := c.Config.Server.MaxLogLines
+	if maxLogLines <= 0 {
+		maxLogLines = defaultMaxLogLines
+	}
 	return &AppHandler{
-		svc: service.NewAppService(),
+		svc:         service.NewAppService(c),
+		maxLogLines: maxLogLines,
 	}
 }
 
 // CreateAppRequest 创建应用请求
 type CreateAppRequest struct {
 	Name     string `json:"name" binding:"required" example:"my-nginx"`
-	Image    string `json:"image" binding:"required" example:"nginx:latest"`
-	Replicas int    `json:"replicas" binding:"required,min=0,max=10" example:"2"`
-	Port     int    `json:"port" example:"80"`
+	Image    string `json:"image" example:"nginx:latest"`                                                       // 与 source 二选一
+	Kind     string `json:"kind" binding:"omitempty,oneof=deployment statefulset cronjob" example:"deployment"` // 工作负载类型，statefulset 为每个副本提供稳定标识与独立存储，cronjob 定时运行且不创建 Service
+	Schedule string `json:"schedule" example:"*/5 * * * *"`                                                     // kind 为 cronjob 时必填，标准 5 段 cron 表达式
+	// Pod 重启策略：Deployment 只能为 Always（K8s 限制，可不填）；cronjob 可选 OnFailure（默认，失败时原地重启容器）或 Never（失败时创建新 Pod 重试）
+	RestartPolicy string `json:"restart_policy" binding:"omitempty,oneof=Always OnFailure Never" example:"OnFailure"`
+	// 定时任务单次执行的失败重试次数，超过后该次任务标记为失败，仅 cronjob 可用，留空使用 K8s 默认值（6）
+	BackoffLimit *int32 `json:"backoff_limit" binding:"omitempty,min=0,max=100" example:"3"`
+	Replicas     *int   `json:"replicas" example:"2"` // 留空使用平台默认值，范围由平台配置（默认 0~10）
+	Port         int    `json:"port" example:"80"`
+	// 有状态应用的持久卷配置，kind 为 statefulset 时必填
+	Storage *StorageRequest `json:"storage"`
+	// 创建 Headless Service（ClusterIP: None），需同时设置 port，statefulset 始终使用 Headless Service；
+	// <name>.<namespace>.svc.cluster.local 将直接解析为所有就绪 Pod 的 IP，适合客户端自行负载均衡或集群发现
+	Headless bool `json:"headless" example:"false"`
+	// 副本打散：node 尽量分布到不同节点，zone 尽量分布到不同可用区，留空不限制；定时任务不可用
+	Spread string `json:"spread" binding:"omitempty,oneof=node zone" example:"node"`
+	// 镜像拉取策略，留空使用平台默认值；需要重启后拉取同名 tag 的新镜像时使用 Always
+	ImagePullPolicy string `json:"image_pull_policy" binding:"omitempty,oneof=Always IfNotPresent Never" example:"IfNotPresent"`
+	// Service 注解，如云厂商负载均衡配置
+	ServiceAnnotations map[string]string `json:"service_annotations"`
+	// Deployment 注解，供 ArgoCD/Flux、成本分摊等外部工具识别
+	DeploymentAnnotations map[string]string `json:"deployment_annotations"`
+	// 容器安全上下文，未设置的字段使用平台默认值
+	SecurityContext *SecurityContextRequest `json:"security_context"`
+	// 保留的历史版本数，用于回滚，留空使用平台默认值
+	RevisionHistoryLimit *int32 `json:"revision_history_limit" binding:"omitempty,min=0" example:"10"`
+	// 滚动更新超时秒数，超时视为发布失败，留空使用平台默认值
+	ProgressDeadlineSeconds *int32 `json:"progress_deadline_seconds" binding:"omitempty,min=1" example:"600"`
+	// 新 Pod 持续就绪多少秒后才视为可用，滚动更新在此之后才替换下一个旧 Pod，可减少发布期间流量抖动；
+	// 需小于 progress_deadline_seconds，定时任务不可用，留空使用平台默认值
+	MinReadySeconds *int32 `json:"min_ready_seconds" binding:"omitempty,min=0,max=3600" example:"10"`
+	// 优雅退出等待秒数，留空使用 K8s 默认值（30）
+	TerminationGracePeriodSeconds *int64 `json:"termination_grace_period_seconds" binding:"omitempty,min=1,max=3600" example:"60"`
+	// 容器停止前执行的命令，如 ["sh", "-c", "sleep 10"]
+	PreStopCommand []string `json:"pre_stop_command" binding:"omitempty,dive,required"`
+	// 容器环境变量，创建后可通过环境变量接口修改
+	Env []model.EnvVar `json:"env" binding:"max=100"`
+	// 容器工作目录（绝对路径），留空使用镜像中的 WORKDIR
+	WorkingDir string `json:"working_dir" binding:"omitempty,max=256" example:"/app"`
+	// Pod 主机名与子域名（DNS 标签），留空时主机名为 Pod 名；有状态应用由 K8s 固定为 Pod 名，不可设置
+	Hostname  string `json:"hostname" example:"legacy-host"`
+	Subdomain string `json:"subdomain" example:"legacy"`
+	// Pod 使用的 ServiceAccount，用于访问 K8s API 或云厂商 IAM（IRSA/Workload Identity），留空使用命名空间的 default
+	ServiceAccountName string `json:"service_account_name" example:"my-app"`
+	// ServiceAccount 不存在时自动创建（不附带任何权限），已存在时不做修改
+	CreateServiceAccount bool `json:"create_service_account" example:"false"`
+	// Pod DNS 策略，留空使用 K8s 默认值（ClusterFirst）；None 时完全使用 dns_config，需至少一个 nameserver
+	DNSPolicy string `json:"dns_policy" binding:"omitempty,oneof=ClusterFirst ClusterFirstWithHostNet Default None" example:"ClusterFirst"`
+	// 自定义 DNS 配置，与 dns_policy 生成的配置合并
+	DNSConfig *DNSConfigRequest `json:"dns_config"`
+	// 从 Git 仓库构建镜像后部署，与 image 二选一，需平台启用源码构建；构建期间应用状态为 building，失败为 build_failed
+	Source *SourceRequest `json:"source"`
+	// 自定义元数据（JSON 对象），如描述、团队、链接，仅平台内展示，不会写入 K8s
+	Metadata json.RawMessage `json:"metadata" swaggertype:"object"`
+}
+
+// toServiceRequest 转换为 service 层的创建请求，UserID 与幂等键由调用方设置
+func (r *CreateAppRequest) toServiceRequest() service.CreateAppRequest {
+	return service.CreateAppRequest{
+		Name:                          r.Name,
+		Image:                         r.Image,
+		Kind:                          r.Kind,
+		Schedule:                      r.Schedule,
+		RestartPolicy:                 r.RestartPolicy,
+		BackoffLimit:                  r.BackoffLimit,
+		Replicas:                      r.Replicas,
+		Port:                          r.Port,
+		Headless:                      r.Headless,
+		Spread:                        r.Spread,
+		Storage:                       r.Storage.toOption(),
+		ServiceAnnotations:            r.ServiceAnnotations,
+		DeploymentAnnotations:         r.DeploymentAnnotations,
+		SecurityContext:               r.SecurityContext.toOverride(),
+		ImagePullPolicy:               r.ImagePullPolicy,
+		RevisionHistoryLimit:          r.RevisionHistoryLimit,
+		ProgressDeadlineSeconds:       r.ProgressDeadlineSeconds,
+		MinReadySeconds:               r.MinReadySeconds,
+		TerminationGracePeriodSeconds: r.TerminationGracePeriodSeconds,
+		PreStopCommand:                r.PreStopCommand,
+		Env:                           r.Env,
+		WorkingDir:                    r.WorkingDir,
+		Hostname:                      r.Hostname,
+		Subdomain:                     r.Subdomain,
+		ServiceAccountName:            r.ServiceAccountName,
+		CreateServiceAccount:          r.CreateServiceAccount,
+		DNSPolicy:                     r.DNSPolicy,
+		DNS:                           r.DNSConfig.toOption(),
+		Source:                        r.Source.toOption(),
+		Metadata:                      r.Metadata,
+	}
+}
+
+// SourceRequest 源码构建配置
+type SourceRequest struct {
+	GitURL     string `json:"git_url" binding:"required,url" example:"https://github.com/acme/web.git"` // 仅支持 https 公开仓库
+	Ref        string `json:"ref" example:"main"`                                                       // 分支名或完整 ref（如 refs/tags/v1.0），留空为默认分支
+	Dockerfile string `json:"dockerfile" example:"Dockerfile"`                                          // 相对仓库根目录的路径，留空为 Dockerfile
+}
+
+// validate 校验源码构建配置
+func (r *SourceRequest) validate(errs *FieldErrors) {
+	if !strings.HasPrefix(r.GitURL, "https://") {
+		errs.Add("source.git_url", "仅支持 https 地址: %s", r.GitURL)
+	}
+	if r.Dockerfile != "" && (path.IsAbs(r.Dockerfile) || strings.HasPrefix(path.Clean(r.Dockerfile), "..")) {
+		errs.Add("source.dockerfile", "必须为仓库内的相对路径: %s", r.Dockerfile)
+	}
+}
+
+// toOption 转换为 service 层的源码构建选项
+func (r *SourceRequest) toOption() *service.SourceOption {
+	if r == nil {
+		return nil
+	}
+	return &service.SourceOption{
+		GitURL:     r.GitURL,
+		Ref:        r.Ref,
+		Dockerfile: r.Dockerfile,
+	}
+}
+
+// DNSConfigRequest Pod 自定义 DNS 配置
+type DNSConfigRequest struct {
+	Nameservers []string          `json:"nameservers" binding:"max=3" example:"10.0.0.10"`      // DNS 服务器 IP，最多 3 个
+	Searches    []string          `json:"searches" binding:"max=32" example:"corp.example.com"` // 搜索域，最多 32 个
+	Options     map[string]string `json:"options"`                                              // resolver 选项，如 {"ndots": "2"}，值为空表示无值选项
+}
+
+// validate 校验 DNS 配置，policy 为 None 时必须提供 nameserver
+func (r *DNSConfigRequest) validate(errs *FieldErrors, policy string) {
+	if policy == "None" && (r == nil || len(r.Nameservers) == 0) {
+		errs.Add("dns_config.nameservers", "dns_policy 为 None 时不能为空")
+	}
+	if r == nil {
+		return
+	}
+	for i, ns := range r.Nameservers {
+		if net.ParseIP(ns) == nil {
+			errs.Add(fmt.Sprintf("dns_config.nameservers[%d]", i), "无效的 DNS 服务器地址: %s", ns)
+		}
+	}
+	for i, search := range r.Searches {
+		if msgs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, ".")); len(msgs) > 0 {
+			errs.Add(fmt.Sprintf("dns_config.searches[%d]", i), "无效的搜索域 %q: %s", search, strings.Join(msgs, "; "))
+		}
+	}
+	if _, ok := r.Options[""]; ok {
+		errs.Add("dns_config.options", "选项名不能为空")
+	}
+}
+
+// toOption 转换为 service 层的 DNS 配置
+func (r *DNSConfigRequest) toOption() *service.DNSOption {
+	if r == nil {
+		return nil
+	}
+	return &service.DNSOption{
+		Nameservers: r.Nameservers,
+		Searches:    r.Searches,
+		Options:     r.Options,
+	}
+}
+
+// StorageRequest 有状态应用持久卷，每个副本独立一份，PVC 名为 data-<name>-<序号>
+type StorageRequest struct {
+	Size         string `json:"size" binding:"required" example:"10Gi"`
+	StorageClass string `json:"storage_class" example:"standard"` // 留空使用集群默认存储类
+	MountPath    string `json:"mount_path" example:"/data"`       // 留空为 /data
+}
+
+// validate 校验持久卷配置
+func (r *StorageRequest) validate(errs *FieldErrors) {
+	if r == nil {
+		errs.Add("storage", "有状态应用必须设置")
+		return
+	}
+	if size, err := resource.ParseQuantity(r.Size); err != nil || size.Sign() <= 0 {
+		errs.Add("storage.size", "无效的容量: %s", r.Size)
+	}
+	if r.MountPath != "" && !path.IsAbs(r.MountPath) {
+		errs.Add("storage.mount_path", "必须为绝对路径: %s", r.MountPath)
+	}
+}
+
+// toOption 转换为 service 层的持久卷选项
+func (r *StorageRequest) toOption() *service.StorageOption {
+	if r == nil {
+		return nil
+	}
+	return &service.StorageOption{
+		Size:         r.Size,
+		StorageClass: r.StorageClass,
+		MountPath:    r.MountPath,
+	}
+}
+
+// SecurityContextRequest 容器安全上下文
+type SecurityContextRequest struct {
+	RunAsNonRoot           *bool    `json:"run_as_non_root" example:"true"`
+	RunAsUser              *int64   `json:"run_as_user" binding:"omitempty,min=0,max=2147483647" example:"1000"`
+	ReadOnlyRootFilesystem *bool    `json:"read_only_root_filesystem" example:"true"`
+	DropCapabilities       []string `json:"drop_capabilities" example:"ALL"`
+}
+
+// toOverride 转换为 service 层的安全上下文覆盖
+func (r *SecurityContextRequest) toOverride() *service.SecurityContextOverride {
+	if r == nil {
+		return nil
+	}
+	return &service.SecurityContextOverride{
+		RunAsNonRoot:           r.RunAsNonRoot,
+		RunAsUser:              r.RunAsUser,
+		ReadOnlyRootFilesystem: r.ReadOnlyRootFilesystem,
+		DropCapabilities:       r.DropCapabilities,
+	}
 }
 
 // AppLogsResponse 日志响应
@@ -33,6 +269,42 @@ type AppLogsResponse struct {
 	Logs string `json:"logs"`
 }
 
+// AppManifestsResponse 资源清单响应
+type AppManifestsResponse struct {
+	Manifests string `json:"manifests"`
+}
+
+// CheckAppNameResponse 应用名检查结果
+type CheckAppNameResponse struct {
+	Available bool   `json:"available" example:"true"`         // 名称合法且未被占用
+	Valid     bool   `json:"valid" example:"true"`             // 名称格式是否合法
+	Reason    string `json:"reason,omitempty" example:"应用已存在"` // 不可用的原因
+}
+
+// PatchAppRequest 部分更新应用请求（JSON Merge Patch），只包含需要修改的字段
+type PatchAppRequest struct {
+	Replicas        *int            `json:"replicas" binding:"omitempty,min=0" example:"3"` // 0 表示停止，范围由平台配置
+	Image           *string         `json:"image" binding:"omitempty,min=1" example:"nginx:1.27"`
+	ImagePullPolicy *string         `json:"image_pull_policy" binding:"omitempty,oneof=Always IfNotPresent Never" example:"Always"`
+	Env             *[]model.EnvVar `json:"env" binding:"omitempty,max=100"` // 整体替换环境变量，null 表示清空
+	Metadata        json.RawMessage `json:"metadata" swaggertype:"object"`   // 按键合并到现有元数据，值为 null 的键被删除，整体为 null 表示清空
+}
+
+// ScaleAppRequest 调整副本数请求
+type ScaleAppRequest struct {
+	Replicas *int `json:"replicas" binding:"required,min=0" example:"3"` // 0 表示停止，范围由平台配置
+}
+
+// BatchAppsRequest 批量操作应用请求
+type BatchAppsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=50,dive,min=1" example:"1,2,3"` // 应用 ID 列表，重复的 ID 只处理一次
+}
+
+// RollbackAppRequest 回滚应用请求
+type RollbackAppRequest struct {
+	Revision int64 `json:"revision" binding:"min=0" example:"2"` // 目标版本，0 或不传表示上一个版本
+}
+
 // CreateApp 创建应用
 // @Summary 创建应用
 // @Description 创建一个新的容器应用
@@ -40,15 +312,24 @@ type AppLogsResponse struct {
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param Idempotency-Key header string false "幂等键（不超过 128 字符），24 小时内重复请求返回首次创建的应用"
 // @Param request body CreateAppRequest true "应用信息"
 // @Success 200 {object} Response "创建成功"
-// @Failure 400 {object} Response "参数错误"
+// @Failure 400 {object} Response{data=ValidationErrorData} "参数错误，data.errors 为逐字段的错误"
 // @Failure 401 {object} Response "未授权"
 // @Router /apps [post]
 func (h *AppHandler) CreateApp(c *gin.Context) {
 	var req CreateAppRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		BadRequest(c, "参数错误: "+err.Error())
+	if !bindJSON(c, &req) {
+		return
+	}
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if len(idempotencyKey) > 128 {
+		BadRequest(c, "Idempotency-Key 不能超过 128 个字符")
+		return
+	}
+	if errs := req.validate(); len(errs) > 0 {
+		ValidationFailed(c, errs)
 		return
 	}
 
@@ -58,13 +339,10 @@ func (h *AppHandler) CreateApp(c *gin.Context) {
 		return
 	}
 
-	app, err := h.svc.CreateApp(context.Background(), service.CreateAppRequest{
-		Name:     req.Name,
-		Image:    req.Image,
-		Replicas: req.Replicas,
-		Port:     req.Port,
-		UserID:   userID,
-	})
+	appReq := req.toServiceRequest()
+	appReq.IdempotencyKey = idempotencyKey
+	appReq.UserID = userID
+	app, err := h.svc.CreateApp(context.Background(), appReq)
 	if err != nil {
 		HandleError(c, err)
 		return
@@ -75,11 +353,14 @@ func (h *AppHandler) CreateApp(c *gin.Context) {
 
 // GetApps 获取应用列表
 // @Summary 获取应用列表
-// @Description 获取当前用户的所有应用
+// @Description 获取当前用户的所有应用，支持按应用名模糊搜索
 // @Tags 应用
 // @Produce json
 // @Security Bearer
-// @Success 200 {object} Response "成功"
+// @Param q query string false "应用名关键字"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页条数，最大 100" default(20)
+// @Success 200 {object} Response{data=PageData{items=[]service.AppListItem}} "成功"
 // @Failure 401 {object} Response "未授权"
 // @Router /apps [get]
 func (h *AppHandler) GetApps(c *gin.Context) {
@@ -89,23 +370,71 @@ func (h *AppHandler) GetApps(c *gin.Context) {
 		return
 	}
 
-	apps, err := h.svc.GetApps(context.Background(), userID)
+	keyword := strings.TrimSpace(c.Query("q"))
+	if len(keyword) > 64 {
+		BadRequest(c, "搜索关键字过长")
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	apps, total, err := h.svc.GetApps(context.Background(), userID, keyword, page, pageSize)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessPaged(c, apps, total, page, pageSize)
+}
+
+// CheckAppName 检查应用名是否可用
+// @Summary 检查应用名是否可用
+// @Description 创建前检查应用名格式及是否已被占用，不会创建任何资源；重复范围与创建时一致（默认为当前用户）
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param name query string true "应用名"
+// @Success 200 {object} Response{data=CheckAppNameResponse} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Router /apps/check-name [get]
+func (h *AppHandler) CheckAppName(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	name := c.Query("name")
+	if err := validateAppName(name); err != nil {
+		Success(c, CheckAppNameResponse{Reason: err.Error()})
+		return
+	}
+
+	available, err := h.svc.IsAppNameAvailable(context.Background(), userID, name)
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
-	Success(c, apps)
+	resp := CheckAppNameResponse{Available: available, Valid: true}
+	if !available {
+		resp.Reason = errcode.ErrAppExists.Message()
+	}
+	Success(c, resp)
 }
 
 // GetApp 获取应用详情
 // @Summary 获取应用详情
-// @Description 获取指定应用的详细信息
+// @Description 获取指定应用的规格与 K8s 实时状态，K8s 不可达时返回上次同步的数据并标记 status_stale
 // @Tags 应用
 // @Produce json
 // @Security Bearer
 // @Param id path int true "应用ID"
-// @Success 200 {object} Response "成功"
+// @Success 200 {object} Response{data=service.AppDetail} "成功"
 // @Failure 401 {object} Response "未授权"
 // @Failure 404 {object} Response "应用不存在"
 // @Router /apps/{id} [get]
@@ -170,7 +499,9 @@ func (h *AppHandler) DeleteApp(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param id path int true "应用ID"
-// @Success 200 {object} Response "启动成功"
+// @Param wait query bool false "是否等待应用就绪后再返回" default(false)
+// @Param timeout query int false "等待超时时间（秒），最大 600" default(60)
+// @Success 200 {object} Response "启动成功，等待模式下返回应用详情"
 // @Failure 401 {object} Response "未授权"
 // @Failure 404 {object} Response "应用不存在"
 // @Router /apps/{id}/start [post]
@@ -187,12 +518,28 @@ func (h *AppHandler) StartApp(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.StartApp(context.Background(), uint(appID), userID); err != nil {
+	waitTimeout, err := parseWaitTimeout(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.svc.StartApp(context.Background(), uint(appID), userID, waitTimeout); err != nil {
 		HandleError(c, err)
 		return
 	}
 
-	Success(c, nil)
+	if waitTimeout == 0 {
+		Success(c, nil)
+		return
+	}
+
+	app, err := h.svc.GetApp(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	Success(c, app)
 }
 
 // StopApp 停止应用
@@ -227,18 +574,78 @@ func (h *AppHandler) StopApp(c *gin.Context) {
 	Success(c, nil)
 }
 
-// RestartApp 重启应用
-// @Summary 重启应用
-// @Description 重启指定的应用
+// PatchApp 部分更新应用
+// @Summary 部分更新应用
+// @Description 按 JSON Merge Patch 语义只修改请求中出现的字段，支持 replicas、image、image_pull_policy、env、metadata，其他字段返回参数错误。
+// @Description 在当前配置上应用补丁并校验通过后，只同步发生变化的字段：镜像、拉取策略与环境变量合并为一次滚动更新，副本数变化与调整副本数接口相同；
+// @Description metadata 按键合并，值为 null 的键被删除。返回更新后的应用。
+// @Description strategy=recreate 时删除工作负载后按新配置重建：旧 Pod 全部删除后才创建新 Pod，期间应用不可用；
+// @Description 有状态应用的持久卷声明一并删除，数据不可恢复。重建不要求请求体中有修改，定时任务应用不支持
 // @Tags 应用
+// @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "应用ID"
-// @Success 200 {object} Response "重启成功"
+// @Param strategy query string false "更新方式：in-place 原地滚动更新，recreate 删除后重建" Enums(in-place, recreate) default(in-place)
+// @Param request body PatchAppRequest true "需要修改的字段"
+// @Success 200 {object} Response{data=model.App} "更新成功"
+// @Failure 400 {object} Response{data=ValidationErrorData} "参数错误、未知字段或超出副本数范围"
 // @Failure 401 {object} Response "未授权"
 // @Failure 404 {object} Response "应用不存在"
-// @Router /apps/{id}/restart [post]
-func (h *AppHandler) RestartApp(c *gin.Context) {
+// @Router /apps/{id} [patch]
+func (h *AppHandler) PatchApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		ValidationFailed(c, bindErrors(err))
+		return
+	}
+	var req PatchAppRequest
+	if errs := req.parse(body); len(errs) > 0 {
+		ValidationFailed(c, errs)
+		return
+	}
+
+	app, err := h.svc.PatchApp(context.Background(), uint(appID), userID, service.AppPatch{
+		Strategy:        c.Query("strategy"),
+		Replicas:        req.Replicas,
+		Image:           req.Image,
+		ImagePullPolicy: req.ImagePullPolicy,
+		Env:             req.Env,
+		Metadata:        req.Metadata,
+	})
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, app)
+}
+
+// ScaleToZeroApp 缩容到零
+// @Summary 缩容到零
+// @Description 将应用副本数调整为 0 并标记为缩容到零状态，保留期望副本数，之后可通过唤醒接口恢复；定时任务应用不支持
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response "缩容成功"
+// @Failure 400 {object} Response "定时任务应用不支持"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/scale-to-zero [post]
+func (h *AppHandler) ScaleToZeroApp(c *gin.Context) {
 	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		BadRequest(c, "无效的应用ID")
@@ -251,7 +658,7 @@ func (h *AppHandler) RestartApp(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.RestartApp(context.Background(), uint(appID), userID); err != nil {
+	if err := h.svc.ScaleToZeroApp(context.Background(), uint(appID), userID); err != nil {
 		HandleError(c, err)
 		return
 	}
@@ -259,19 +666,22 @@ func (h *AppHandler) RestartApp(c *gin.Context) {
 	Success(c, nil)
 }
 
-// GetAppLogs 获取应用日志
-// @Summary 获取应用日志
-// @Description 获取指定应用的容器日志
+// WakeApp 唤醒应用
+// @Summary 唤醒应用
+// @Description 将已停止或缩容到零的应用恢复到停止前的期望副本数，状态置为 starting，配额检查与启动相同；
+// @Description 应用未处于停止状态时返回参数错误。返回唤醒后的应用，等待模式下返回包含实时状态的应用详情
 // @Tags 应用
 // @Produce json
 // @Security Bearer
 // @Param id path int true "应用ID"
-// @Param lines query int false "日志行数" default(100)
-// @Success 200 {object} Response{data=AppLogsResponse} "成功"
+// @Param wait query bool false "是否等待应用就绪后再返回" default(false)
+// @Param timeout query int false "等待超时时间（秒），最大 600" default(60)
+// @Success 200 {object} Response{data=model.App} "唤醒成功"
+// @Failure 400 {object} Response "应用未处于停止状态"
 // @Failure 401 {object} Response "未授权"
 // @Failure 404 {object} Response "应用不存在"
-// @Router /apps/{id}/logs [get]
-func (h *AppHandler) GetAppLogs(c *gin.Context) {
+// @Router /apps/{id}/wake [post]
+func (h *AppHandler) WakeApp(c *gin.Context) {
 	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		BadRequest(c, "无效的应用ID")
@@ -284,34 +694,940 @@ func (h *AppHandler) GetAppLogs(c *gin.Context) {
 		return
 	}
 
-	lines := int64(100)
-	if linesStr := c.Query("lines"); linesStr != "" {
-		if l, err := strconv.ParseInt(linesStr, 10, 64); err == nil && l > 0 {
-			lines = l
-		}
+	waitTimeout, err := parseWaitTimeout(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
 	}
 
-	logs, err := h.svc.GetAppLogs(context.Background(), uint(appID), userID, lines)
+	app, err := h.svc.WakeApp(context.Background(), uint(appID), userID, waitTimeout)
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
-	Success(c, AppLogsResponse{Logs: logs})
+	if waitTimeout == 0 {
+		Success(c, app)
+		return
+	}
+
+	detail, err := h.svc.GetApp(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	Success(c, detail)
 }
 
-// RegisterAppRoutes 注册应用相关路由
-func RegisterAppRoutes(r *gin.RouterGroup) {
-	h := NewAppHandler()
-	apps := r.Group("/apps")
+// ScaleApp 调整应用副本数
+// @Summary 调整应用副本数
+// @Description 调整副本数并记为期望副本数，停止后再启动会恢复到该值；0 等同于停止
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param request body ScaleAppRequest true "副本数"
+// @Success 200 {object} Response "调整成功"
+// @Failure 400 {object} Response "参数错误或超出副本数范围"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/scale [post]
+func (h *AppHandler) ScaleApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	var req ScaleAppRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.ScaleApp(context.Background(), uint(appID), userID, *req.Replicas); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// RestartApp 重启应用
+// @Summary 重启应用
+// @Description 重启指定的应用。rolling（默认）逐个替换 Pod，多副本时不中断服务，单副本仍会短暂中断；
+// @Description recreate 立即删除所有 Pod 后重建，所有新 Pod 就绪前应用完全不可用，适合需要彻底重启的场景
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param mode query string false "重启方式" Enums(rolling, recreate) default(rolling)
+// @Param wait query bool false "是否等待应用就绪后再返回" default(false)
+// @Param timeout query int false "等待超时时间（秒），最大 600" default(60)
+// @Success 200 {object} Response "重启成功，等待模式下返回应用详情"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/restart [post]
+func (h *AppHandler) RestartApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	waitTimeout, err := parseWaitTimeout(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	mode := c.DefaultQuery("mode", service.RestartRolling)
+	if mode != service.RestartRolling && mode != service.RestartRecreate {
+		BadRequest(c, "无效的 mode 参数，可选 rolling、recreate")
+		return
+	}
+
+	if err := h.svc.RestartApp(context.Background(), uint(appID), userID, mode, waitTimeout); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	if waitTimeout == 0 {
+		Success(c, nil)
+		return
+	}
+
+	app, err := h.svc.GetApp(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	Success(c, app)
+}
+
+// SuspendApp 挂起应用
+// @Summary 挂起应用
+// @Description 挂起应用，挂起期间平台不再同步应用状态，可手动调整集群资源
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response "挂起成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/suspend [post]
+func (h *AppHandler) SuspendApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.SuspendApp(context.Background(), uint(appID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// ResumeApp 恢复应用
+// @Summary 恢复应用
+// @Description 恢复已挂起的应用
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response "恢复成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/resume [post]
+func (h *AppHandler) ResumeApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.ResumeApp(context.Background(), uint(appID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// SetAppEnvRequest 设置环境变量请求，整体替换应用的环境变量
+type SetAppEnvRequest struct {
+	Env []model.EnvVar `json:"env" binding:"max=100"` // 为空表示清空所有环境变量
+}
+
+// GetAppEnv 获取环境变量
+// @Summary 获取环境变量
+// @Description 获取应用容器的环境变量（按写入顺序）
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response{data=[]model.EnvVar} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/env [get]
+func (h *AppHandler) GetAppEnv(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	env, err := h.svc.GetAppEnv(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, env)
+}
+
+// SetAppEnv 设置环境变量
+// @Summary 设置环境变量
+// @Description 整体替换应用容器的环境变量并保存到应用记录。Deployment 与 StatefulSet 会滚动更新所有 Pod，定时任务从下一次调度开始生效。
+// @Description 变量名需为合法的环境变量名且不重复，KUBERNETES_、ASTRO_ 为保留前缀；返回更新后的环境变量与发布状态
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param request body SetAppEnvRequest true "环境变量，最多 100 个"
+// @Success 200 {object} Response{data=service.AppEnv} "设置成功"
+// @Failure 400 {object} Response{data=ValidationErrorData} "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/env [put]
+func (h *AppHandler) SetAppEnv(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	var req SetAppEnvRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if errs := req.validate(); len(errs) > 0 {
+		ValidationFailed(c, errs)
+		return
+	}
+
+	env := req.Env
+	if env == nil {
+		env = []model.EnvVar{}
+	}
+	result, err := h.svc.SetAppEnv(context.Background(), uint(appID), userID, env)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, result)
+}
+
+// PauseRollout 暂停发布
+// @Summary 暂停发布
+// @Description 暂停 Deployment 的滚动更新（同 kubectl rollout pause），新旧版本 Pod 保持当前比例，便于检查新版本
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response "暂停成功"
+// @Failure 400 {object} Response "非 Deployment 应用"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/rollout/pause [post]
+func (h *AppHandler) PauseRollout(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.PauseRollout(context.Background(), uint(appID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// ResumeRollout 继续发布
+// @Summary 继续发布
+// @Description 继续已暂停的滚动更新（同 kubectl rollout resume）
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response "继续成功"
+// @Failure 400 {object} Response "非 Deployment 应用"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/rollout/resume [post]
+func (h *AppHandler) ResumeRollout(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.ResumeRollout(context.Background(), uint(appID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// BatchStartApps 批量启动应用
+// @Summary 批量启动应用
+// @Description 批量启动应用，不等待就绪，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BatchAppsRequest true "应用 ID 列表（最多 50 个）"
+// @Success 200 {object} Response{data=[]service.BatchResult} "各应用的操作结果"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Router /apps/batch/start [post]
+func (h *AppHandler) BatchStartApps(c *gin.Context) {
+	h.batchOperateApps(c, service.BatchStart)
+}
+
+// BatchStopApps 批量停止应用
+// @Summary 批量停止应用
+// @Description 批量停止应用，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BatchAppsRequest true "应用 ID 列表（最多 50 个）"
+// @Success 200 {object} Response{data=[]service.BatchResult} "各应用的操作结果"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Router /apps/batch/stop [post]
+func (h *AppHandler) BatchStopApps(c *gin.Context) {
+	h.batchOperateApps(c, service.BatchStop)
+}
+
+// BatchRestartApps 批量重启应用
+// @Summary 批量重启应用
+// @Description 批量滚动重启应用，不等待就绪，逐个校验权限，单个应用失败不影响其他应用，按 ID 返回各自结果
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BatchAppsRequest true "应用 ID 列表（最多 50 个）"
+// @Success 200 {object} Response{data=[]service.BatchResult} "各应用的操作结果"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Router /apps/batch/restart [post]
+func (h *AppHandler) BatchRestartApps(c *gin.Context) {
+	h.batchOperateApps(c, service.BatchRestart)
+}
+
+// batchOperateApps 解析应用 ID 列表并执行批量操作
+func (h *AppHandler) batchOperateApps(c *gin.Context, action string) {
+	var req BatchAppsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	results, err := h.svc.BatchOperateApps(context.Background(), userID, req.IDs, action)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, results)
+}
+
+// ListAppPods 获取应用 Pod 列表
+// @Summary 获取应用 Pod 列表
+// @Description 列出应用当前的所有 Pod 及容器状态
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response{data=[]k8s.PodInfo} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/pods [get]
+func (h *AppHandler) ListAppPods(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	pods, err := h.svc.ListAppPods(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, pods)
+}
+
+// GetAppPod 获取应用的单个 Pod
+// @Summary 获取应用的单个 Pod
+// @Description 获取指定 Pod 的状态、所在节点与 IP，Pod 不属于该应用时返回资源不存在
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param pod path string true "Pod 名称"
+// @Success 200 {object} Response{data=k8s.PodDetail} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用或 Pod 不存在"
+// @Router /apps/{id}/pods/{pod} [get]
+func (h *AppHandler) GetAppPod(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	pod, err := h.svc.GetAppPod(context.Background(), uint(appID), userID, c.Param("pod"))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, pod)
+}
+
+// DeleteAppPod 删除应用的单个 Pod
+// @Summary 删除应用的单个 Pod
+// @Description 删除指定 Pod 并由工作负载重新创建，用于恢复卡住的单个副本而无需重启整个应用；Pod 不属于该应用时返回资源不存在
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param pod path string true "Pod 名称"
+// @Success 200 {object} Response "删除成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用或 Pod 不存在"
+// @Router /apps/{id}/pods/{pod} [delete]
+func (h *AppHandler) DeleteAppPod(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.DeleteAppPod(context.Background(), uint(appID), userID, c.Param("pod")); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// GetAppLogs 获取应用日志
+// @Summary 获取应用日志
+// @Description 获取指定应用的容器日志。容器重启过时先返回重启前的日志，再以 `--- pod restarted ---` 分隔行接上当前日志
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param lines query int false "日志行数，不超过配置上限（默认 10000）" default(100)
+// @Param all_pods query bool false "聚合所有 Pod 的日志，每行以 [Pod 名] 开头，不同 Pod 之间不保证时间顺序；lines 为总行数" default(false)
+// @Success 200 {object} Response{data=AppLogsResponse} "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/logs [get]
+func (h *AppHandler) GetAppLogs(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	lines, ok := h.logLines(c)
+	if !ok {
+		return
+	}
+
+	allPods := false
+	if v := c.Query("all_pods"); v != "" {
+		if allPods, err = strconv.ParseBool(v); err != nil {
+			BadRequest(c, "all_pods 只能为 true 或 false")
+			return
+		}
+	}
+
+	logs, err := h.svc.GetAppLogs(c.Request.Context(), uint(appID), userID, lines, allPods)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, AppLogsResponse{Logs: logs})
+}
+
+// logLines 解析日志行数参数，默认 100 行，超过配置上限时返回参数错误
+func (h *AppHandler) logLines(c *gin.Context) (int64, bool) {
+	lines := int64(100)
+	if linesStr := c.Query("lines"); linesStr != "" {
+		if l, err := strconv.ParseInt(linesStr, 10, 64); err == nil && l > 0 {
+			lines = l
+		}
+	}
+	if lines > h.maxLogLines {
+		BadRequest(c, fmt.Sprintf("lines 不能超过 %d", h.maxLogLines))
+		return 0, false
+	}
+	return lines, true
+}
+
+// FollowAppLogs 实时跟踪应用日志
+// @Summary 实时跟踪应用日志
+// @Description 升级为 WebSocket 连接，先推送第一个 Pod 日志的末尾 lines 行，之后每条文本消息为一行新日志。
+// @Description 容器重启或 Pod 被替换后自动重连，先推送 `--- pod restarted ---` 分隔行，再从新容器的第一行开始推送；
+// @Description 连续重连失败超过上限或客户端断开时关闭连接
+// @Tags 应用
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param lines query int false "初始推送的日志行数，不超过配置上限（默认 10000）" default(100)
+// @Success 101 {string} string "切换协议，后续消息为日志行"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/logs/follow [get]
+func (h *AppHandler) FollowAppLogs(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	lines, ok := h.logLines(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// 在升级连接前完成权限检查和日志流建立，失败时仍可返回统一响应
+	lineCh, err := h.svc.FollowAppLogs(ctx, uint(appID), userID, lines)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("WebSocket 升级失败", zap.Uint64("app_id", appID), zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// 客户端断开时停止跟踪
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for line := range lineCh {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+			return
+		}
+	}
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "log stream ended")
+	if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		logger.Debug("发送 WebSocket 关闭帧失败", zap.Error(err))
+	}
+}
+
+// DescribeApp 获取应用诊断信息
+// @Summary 获取应用诊断信息
+// @Description 一次返回应用规格、实时状态（Pod、重启次数）、最近的 K8s 事件和日志末尾，类似 kubectl describe 加日志；
+// @Description 各部分并发获取，失败的部分在 errors 中给出原因
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param lines query int false "日志行数，不超过配置上限" default(50)
+// @Success 200 {object} Response{data=service.AppDescription} "成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/describe [get]
+func (h *AppHandler) DescribeApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	lines := int64(50)
+	if linesStr := c.Query("lines"); linesStr != "" {
+		if l, err := strconv.ParseInt(linesStr, 10, 64); err == nil && l > 0 {
+			lines = l
+		}
+	}
+	if lines > h.maxLogLines {
+		BadRequest(c, fmt.Sprintf("lines 不能超过 %d", h.maxLogLines))
+		return
+	}
+
+	desc, err := h.svc.DescribeApp(context.Background(), uint(appID), userID, lines)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, desc)
+}
+
+// wsUpgrader WebSocket 升级器，使用默认的同源检查
+var wsUpgrader = websocket.Upgrader{}
+
+// WatchApp 实时监听应用状态
+// @Summary 实时监听应用状态
+// @Description 升级为 WebSocket 连接，应用 Deployment/Pod 变化时推送最新状态（JSON），应用被删除或客户端断开时关闭连接
+// @Tags 应用
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 101 {object} k8s.AppStatus "切换协议，后续消息为应用状态"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/watch [get]
+func (h *AppHandler) WatchApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// 在升级连接前完成权限检查和监听建立，失败时仍可返回统一响应
+	statusCh, err := h.svc.WatchApp(ctx, uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("WebSocket 升级失败", zap.Uint64("app_id", appID), zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// 客户端断开时取消监听
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for status := range statusCh {
+		if err := conn.WriteJSON(status); err != nil {
+			return
+		}
+	}
+
+	// 应用被删除或监听结束，通知客户端正常关闭
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "watch ended")
+	if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		logger.Debug("发送 WebSocket 关闭帧失败", zap.Error(err))
+	}
+}
+
+// GetAppManifests 获取应用资源清单
+// @Summary 获取应用资源清单
+// @Description 获取应用当前在集群中的 Deployment/Service 等资源（YAML），已去除 managedFields 和 status
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response{data=AppManifestsResponse} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/manifests [get]
+func (h *AppHandler) GetAppManifests(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	manifests, err := h.svc.GetAppManifests(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, AppManifestsResponse{Manifests: manifests})
+}
+
+// ListAppRevisions 获取应用历史版本
+// @Summary 获取应用历史版本
+// @Description 列出应用 Deployment 保留的历史版本（按版本号倒序）
+// @Tags 应用
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Success 200 {object} Response{data=[]k8s.Revision} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/revisions [get]
+func (h *AppHandler) ListAppRevisions(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	revisions, err := h.svc.ListRevisions(context.Background(), uint(appID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, revisions)
+}
+
+// RollbackApp 回滚应用
+// @Summary 回滚应用
+// @Description 将应用回滚到指定历史版本，不指定版本时回滚到上一个版本
+// @Tags 应用
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用ID"
+// @Param request body RollbackAppRequest false "回滚参数"
+// @Success 200 {object} Response{data=k8s.AppStatus} "回滚成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用不存在"
+// @Router /apps/{id}/rollback [post]
+func (h *AppHandler) RollbackApp(c *gin.Context) {
+	appID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	var req RollbackAppRequest
+	if c.Request.ContentLength > 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	status, err := h.svc.RollbackApp(context.Background(), uint(appID), userID, req.Revision)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, status)
+}
+
+// parseWaitTimeout 解析 wait/timeout 查询参数，未开启等待时返回 0
+func parseWaitTimeout(c *gin.Context) (time.Duration, error) {
+	wait, err := strconv.ParseBool(c.DefaultQuery("wait", "false"))
+	if err != nil {
+		return 0, errors.New("无效的 wait 参数")
+	}
+	if !wait {
+		return 0, nil
+	}
+
+	seconds, err := strconv.Atoi(c.DefaultQuery("timeout", "60"))
+	if err != nil || seconds <= 0 || seconds > 600 {
+		return 0, errors.New("无效的 timeout 参数，取值范围 1-600")
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// RegisterAppRoutes 注册应用相关路由
+// requireScope 由 middleware.RequireScope 注入，避免 handler 反向依赖 middleware
+func RegisterAppRoutes(r *gin.RouterGroup, c *container.Container, requireScope func(scope string) gin.HandlerFunc) {
+	h := NewAppHandler(c)
+	read := requireScope(model.ScopeAppsRead)
+	write := requireScope(model.ScopeAppsWrite)
+	apps := r.Group("/apps")
+	{
+		apps.POST("", write, h.CreateApp)
+		apps.GET("", read, h.GetApps)
+		apps.GET("/check-name", read, h.CheckAppName)
+		apps.GET("/:id", read, h.GetApp)
+		apps.PATCH("/:id", write, h.PatchApp)
+		apps.POST("/batch/start", write, h.BatchStartApps)
+		apps.POST("/batch/stop", write, h.BatchStopApps)
+		apps.POST("/batch/restart", write, h.BatchRestartApps)
+		apps.DELETE("/:id", write, h.DeleteApp)
+		apps.POST("/:id/start", write, h.StartApp)
+		apps.POST("/:id/stop", write, h.StopApp)
+		apps.POST("/:id/restart", write, h.RestartApp)
+		apps.POST("/:id/scale", write, h.ScaleApp)
+		apps.POST("/:id/scale-to-zero", write, h.ScaleToZeroApp)
+		apps.POST("/:id/wake", write, h.WakeApp)
+		apps.POST("/:id/suspend", write, h.SuspendApp)
+		apps.POST("/:id/resume", write, h.ResumeApp)
+		apps.GET("/:id/env", read, h.GetAppEnv)
+		apps.PUT("/:id/env", write, h.SetAppEnv)
+		apps.POST("/:id/rollout/pause", write, h.PauseRollout)
+		apps.POST("/:id/rollout/resume", write, h.ResumeRollout)
+		apps.GET("/:id/pods", read, h.ListAppPods)
+		apps.GET("/:id/pods/:pod", read, h.GetAppPod)
+		apps.DELETE("/:id/pods/:pod", write, h.DeleteAppPod)
+		apps.GET("/:id/logs", read, h.GetAppLogs)
+		apps.GET("/:id/logs/follow", read, h.FollowAppLogs)
+		apps.GET("/:id/describe", read, h.DescribeApp)
+		apps.GET("/:id/manifests", read, h.GetAppManifests)
+		apps.GET("/:id/revisions", read, h.ListAppRevisions)
+		apps.POST("/:id/rollback", write, h.RollbackApp)
+		apps.GET("/:id/watch", read, h.WatchApp)
+	}
+	// 应用组与应用共用同一个处理器，共享每个用户的并发创建名额
+	stacks := r.Group("/stacks")
 	{
-		apps.POST("", h.CreateApp)
-		apps.GET("", h.GetApps)
-		apps.GET("/:id", h.GetApp)
-		apps.DELETE("/:id", h.DeleteApp)
-		apps.POST("/:id/start", h.StartApp)
-		apps.POST("/:id/stop", h.StopApp)
-		apps.POST("/:id/restart", h.RestartApp)
-		apps.GET("/:id/logs", h.GetAppLogs)
+		stacks.POST("", write, h.CreateStack)
+		stacks.GET("", read, h.GetStacks)
+		stacks.GET("/:id", read, h.GetStack)
+		stacks.DELETE("/:id", write, h.DeleteStack)
+		stacks.GET("/:id/apps", read, h.ListStackApps)
+		stacks.POST("/:id/start", write, h.StartStack)
+		stacks.POST("/:id/stop", write, h.StopStack)
 	}
 }
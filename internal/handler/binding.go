@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// 校验错误中的字段名使用 JSON 字段名，与请求体保持一致
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName 返回结构体字段的 JSON 名称，未设置 json 标签时使用字段名
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field" example:"storage.size"` // 字段路径，与请求 JSON 一致，如 dns_config.nameservers[0]；请求体整体错误时为空
+	Message string `json:"message" example:"不能为空"`
+}
+
+// FieldErrors 按出现顺序收集的字段错误
+type FieldErrors []FieldError
+
+// Add 追加一个字段错误
+func (e *FieldErrors) Add(field, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Error 拼接所有字段错误，用作响应的 message
+func (e FieldErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		if fe.Field == "" {
+			parts = append(parts, fe.Message)
+			continue
+		}
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidationErrorData 参数校验失败时响应的 data
+type ValidationErrorData struct {
+	Errors FieldErrors `json:"errors"`
+}
+
+// ValidationFailed 参数校验失败响应，data 中返回逐字段的错误
+func ValidationFailed(c *gin.Context, errs FieldErrors) {
+	c.Set(ContextKeyResponseCode, errcode.ErrBadRequest)
+	c.JSON(http.StatusOK, Response{
+		Code:      errcode.ErrBadRequest.Int(),
+		Message:   "参数错误: " + errs.Error(),
+		Data:      ValidationErrorData{Errors: errs},
+		RequestID: c.GetString(ContextKeyRequestID),
+	})
+}
+
+// bindJSON 解析并按 binding 标签校验请求体，失败时写入字段错误响应并返回 false
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		ValidationFailed(c, bindErrors(err))
+		return false
+	}
+	return true
+}
+
+// bindErrors 将解析与校验错误转换为字段错误
+func bindErrors(err error) FieldErrors {
+	var errs FieldErrors
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			errs.Add(fieldPath(fe.Namespace()), "%s", tagMessage(fe))
+		}
+	case errors.As(err, &typeErr):
+		errs.Add(typeErr.Field, "类型错误，应为 %s", typeErr.Type.String())
+	default:
+		errs.Add("", "%s", err.Error())
+	}
+	return errs
+}
+
+// fieldPath 去掉校验错误命名空间中的顶层结构体名，如 CreateAppRequest.storage.size -> storage.size
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// tagMessage 根据校验规则生成错误提示
+func tagMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	sized := kind == reflect.Slice || kind == reflect.Map || kind == reflect.Array
+	switch fe.Tag() {
+	case "required":
+		return "不能为空"
+	case "min":
+		if kind == reflect.String {
+			return fmt.Sprintf("长度不能小于 %s", fe.Param())
+		}
+		if sized {
+			return fmt.Sprintf("至少需要 %s 项", fe.Param())
+		}
+		return fmt.Sprintf("不能小于 %s", fe.Param())
+	case "max":
+		if kind == reflect.String {
+			return fmt.Sprintf("长度不能超过 %s", fe.Param())
+		}
+		if sized {
+			return fmt.Sprintf("最多 %s 项", fe.Param())
+		}
+		return fmt.Sprintf("不能大于 %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("必须为以下值之一: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "url":
+		return "必须为合法的 URL"
+	case "email":
+		return "必须为合法的邮箱地址"
+	case "excludes":
+		return fmt.Sprintf("不能包含 %q", fe.Param())
+	default:
+		return fmt.Sprintf("不满足校验规则 %s", fe.Tag())
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DashboardHandler 首页统计处理器
+type DashboardHandler struct {
+	svc *service.AppService
+}
+
+// NewDashboardHandler 创建首页统计处理器
+func NewDashboardHandler(c *container.Container) *DashboardHandler {
+	return &DashboardHandler{
+		svc: service.NewAppService(c),
+	}
+}
+
+// GetStats 获取当前用户的应用统计
+// @Summary 获取应用统计
+// @Description 返回当前用户按状态分组的应用数及副本总数，统计前会同步一批状态过期的应用
+// @Tags 首页
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} Response{data=service.DashboardStats} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Router /dashboard/stats [get]
+func (h *DashboardHandler) GetStats(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	stats, err := h.svc.DashboardStats(context.Background(), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, stats)
+}
+
+// GetClusterStats 获取全平台应用统计
+// @Summary 获取全平台应用统计
+// @Description 管理员查看所有用户按状态分组的应用数及副本总数
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} Response{data=service.DashboardStats} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限"
+// @Router /admin/dashboard/stats [get]
+func (h *DashboardHandler) GetClusterStats(c *gin.Context) {
+	stats, err := h.svc.DashboardStats(context.Background(), 0)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, stats)
+}
+
+// GetNamespaceUsage 获取命名空间资源用量
+// @Summary 获取命名空间资源用量
+// @Description 管理员查看 Astro 创建的命名空间内的应用数、Pod 数、容器 requests/limits 之和及实时用量。
+// @Description 实时用量来自 metrics-server，未安装或不可用时 usage 为空并在 metrics_error 中说明原因
+// @Tags 管理员
+// @Produce json
+// @Security Bearer
+// @Param ns path string true "命名空间"
+// @Success 200 {object} Response{data=service.NamespaceUsage} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "无权限"
+// @Failure 404 {object} Response "命名空间不存在或不由 Astro 管理"
+// @Router /admin/namespaces/{ns}/usage [get]
+func (h *DashboardHandler) GetNamespaceUsage(c *gin.Context) {
+	namespace := c.Param("ns")
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		BadRequest(c, "无效的命名空间: "+strings.Join(errs, "; "))
+		return
+	}
+
+	usage, err := h.svc.NamespaceUsage(context.Background(), namespace)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, usage)
+}
+
+// RegisterDashboardRoutes 注册首页统计路由，调用方需挂载认证中间件
+func RegisterDashboardRoutes(r *gin.RouterGroup, c *container.Container, requireScope func(string) gin.HandlerFunc) {
+	h := NewDashboardHandler(c)
+	r.GET("/dashboard/stats", requireScope(model.ScopeAppsRead), h.GetStats)
+}
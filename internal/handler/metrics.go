@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// metricsContentType Prometheus 文本格式
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metrics 以 Prometheus 文本格式输出监控指标：K8s 熔断器状态与审计日志丢弃数
+// @Summary 监控指标
+// @Description 以 Prometheus 文本格式输出 K8s 熔断器状态与审计日志丢弃数（路径不带 /api/v1 前缀）。
+// @Description astro_k8s_breaker_state 按 state 标签（closed/open/half-open）输出，当前状态为 1；另有连续失败次数与累计打开次数。
+// @Description astro_audit_dropped_total 为审计日志队列已满时累计丢弃的记录数
+// @Tags 系统
+// @Produce plain
+// @Success 200 {string} string "指标"
+// @Router /metrics [get]
+func Metrics(breaker *k8s.CircuitBreaker, auditSvc *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics := formatBreakerMetrics(breaker.Stats()) + formatAuditMetrics(auditSvc.Dropped())
+		c.Data(200, metricsContentType, []byte(metrics))
+	}
+}
+
+// formatAuditMetrics 将审计日志丢弃数格式化为 Prometheus 指标
+func formatAuditMetrics(dropped int64) string {
+	return "# HELP astro_audit_dropped_total 审计日志队列已满时累计丢弃的记录数\n" +
+		"# TYPE astro_audit_dropped_total counter\n" +
+		fmt.Sprintf("astro_audit_dropped_total %d\n", dropped)
+}
+
+// formatBreakerMetrics 将熔断器状态格式化为 Prometheus 指标
+func formatBreakerMetrics(stats k8s.BreakerStats) string {
+	var sb strings.Builder
+	sb.WriteString("# HELP astro_k8s_breaker_state K8s 熔断器状态，当前状态为 1\n")
+	sb.WriteString("# TYPE astro_k8s_breaker_state gauge\n")
+	for _, state := range []string{k8s.BreakerClosed, k8s.BreakerOpen, k8s.BreakerHalfOpen} {
+		value := 0
+		if state == stats.State {
+			value = 1
+		}
+		fmt.Fprintf(&sb, "astro_k8s_breaker_state{state=%q} %d\n", state, value)
+	}
+	sb.WriteString("# HELP astro_k8s_breaker_consecutive_failures K8s 调用连续连接失败次数\n")
+	sb.WriteString("# TYPE astro_k8s_breaker_consecutive_failures gauge\n")
+	fmt.Fprintf(&sb, "astro_k8s_breaker_consecutive_failures %d\n", stats.Failures)
+	sb.WriteString("# HELP astro_k8s_breaker_opens_total K8s 熔断器从关闭进入打开的累计次数\n")
+	sb.WriteString("# TYPE astro_k8s_breaker_opens_total counter\n")
+	fmt.Fprintf(&sb, "astro_k8s_breaker_opens_total %d\n", stats.Opens)
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	auditSvc := service.NewAuditService(&container.Container{DB: db})
+	connErr := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		failures int
+		want     []string
+	}{
+		{"关闭", 0, []string{
+			`astro_k8s_breaker_state{state="closed"} 1`,
+			`astro_k8s_breaker_state{state="open"} 0`,
+			"astro_k8s_breaker_consecutive_failures 0",
+			"astro_k8s_breaker_opens_total 0",
+			"astro_audit_dropped_total 0",
+		}},
+		{"打开", 2, []string{
+			`astro_k8s_breaker_state{state="closed"} 0`,
+			`astro_k8s_breaker_state{state="open"} 1`,
+			"astro_k8s_breaker_consecutive_failures 2",
+			"astro_k8s_breaker_opens_total 1",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := k8s.NewCircuitBreaker(2, time.Minute)
+			for i := 0; i < tt.failures; i++ {
+				breaker.Record(connErr)
+			}
+			r := gin.New()
+			r.GET("/metrics", Metrics(breaker, auditSvc))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+				t.Fatalf("响应 = %d %s", w.Code, w.Header().Get("Content-Type"))
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(w.Body.String(), line+"\n") {
+					t.Errorf("指标缺少 %q:\n%s", line, w.Body.String())
+				}
+			}
+		})
+	}
+}
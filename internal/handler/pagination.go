@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// PageData 分页数据
+type PageData struct {
+	Items    interface{} `json:"items"`
+	Total    int64       `json:"total" example:"42"`
+	Page     int         `json:"page" example:"1"`
+	PageSize int         `json:"page_size" example:"20"`
+}
+
+// SuccessPaged 分页成功响应
+func SuccessPaged(c *gin.Context, items interface{}, total int64, page, pageSize int) {
+	Success(c, PageData{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// parsePagination 解析 page/page_size 查询参数，返回页码和每页条数
+func parsePagination(c *gin.Context) (int, int, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, errors.New("无效的 page 参数")
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		return 0, 0, errors.New("无效的 page_size 参数，取值范围 1-100")
+	}
+
+	return page, pageSize, nil
+}
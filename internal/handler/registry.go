@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RegistryHandler 镜像仓库处理器
+type RegistryHandler struct {
+	svc *service.RegistryService
+}
+
+// NewRegistryHandler 创建镜像仓库处理器
+func NewRegistryHandler() *RegistryHandler {
+	return &RegistryHandler{
+		svc: service.NewRegistryService(),
+	}
+}
+
+// TestRegistryRequest 镜像仓库连通性检查请求
+type TestRegistryRequest struct {
+	Registry string `json:"registry" binding:"required" example:"registry.example.com"`
+	Username string `json:"username" example:"robot"`
+	Password string `json:"password" example:"secret"`
+}
+
+// TestRegistry 检查镜像仓库凭证
+// @Summary 检查镜像仓库凭证
+// @Description 访问仓库 /v2/ 接口验证连通性与认证，凭证仅用于本次检查，不会保存；超时 10 秒。
+// @Description 不允许访问回环、私有、链路本地等内部地址；Token 认证时只向与仓库同一主机的 https 认证地址发送凭证；
+// @Description 无法访问时只返回通用错误，不返回上游状态码或网络错误。访问令牌需要 apps:write 权限
+// @Tags 镜像仓库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body TestRegistryRequest true "仓库地址与凭证"
+// @Success 200 {object} Response "检查通过"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 403 {object} Response "访问令牌权限不足"
+// @Failure 401 {object} Response "未授权"
+// @Router /registry/test [post]
+func (h *RegistryHandler) TestRegistry(c *gin.Context) {
+	var req TestRegistryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Password != "" && req.Username == "" {
+		BadRequest(c, "提供密码时必须提供用户名")
+		return
+	}
+
+	c.Set(ContextKeyAuditTarget, req.Registry)
+	if err := h.svc.TestConnection(context.Background(), req.Registry, req.Username, req.Password); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// RegisterRegistryRoutes 注册镜像仓库路由，仓库检查用于创建应用前确认镜像可拉取，访问令牌需要 apps:write 权限
+func RegisterRegistryRoutes(r *gin.RouterGroup, requireScope func(scope string) gin.HandlerFunc) {
+	h := NewRegistryHandler()
+	r.POST("/registry/test", requireScope(model.ScopeAppsWrite), h.TestRegistry)
+}
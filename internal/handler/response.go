@@ -7,15 +7,43 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// 供中间件读取的上下文键
+const (
+	// ContextKeyResponseCode 本次请求响应的错误码
+	ContextKeyResponseCode = "response_code"
+	// ContextKeyAuditTarget 审计日志的操作对象，未设置时使用路径中的 id 参数
+	ContextKeyAuditTarget = "audit_target"
+	// ContextKeyAuthType 本次请求的认证方式，取值见 AuthTypeJWT/AuthTypePAT
+	ContextKeyAuthType = "auth_type"
+	// ContextKeyTokenScopes 个人访问令牌的权限范围（[]string），为空表示不限制
+	ContextKeyTokenScopes = "token_scopes"
+	// ContextKeyTokenIssuedAt/ContextKeyTokenExpiresAt 本次请求凭证的签发与过期时间（time.Time），未知或永不过期时不设置
+	ContextKeyTokenIssuedAt  = "token_issued_at"
+	ContextKeyTokenExpiresAt = "token_expires_at"
+	// ContextKeyActorID 模拟登录时实际操作的管理员 ID，user_id 为被模拟的用户
+	ContextKeyActorID = "actor_id"
+	// ContextKeyRequestID 本次请求的 ID，错误响应中返回给客户端
+	ContextKeyRequestID = "request_id"
+)
+
+// 认证方式
+const (
+	AuthTypeJWT = "jwt"
+	AuthTypePAT = "pat"
+)
+
 // Response 统一响应结构
 type Response struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	// RequestID 仅错误响应返回，反馈问题时提供该 ID 即可在服务端日志中定位
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
+	c.Set(ContextKeyResponseCode, errcode.Success)
 	c.JSON(http.StatusOK, Response{
 		Code:    errcode.Success.Int(),
 		Message: errcode.Success.Message(),
@@ -29,9 +57,11 @@ func Error(c *gin.Context, code errcode.Code, message string) {
 	if msg == "" {
 		msg = code.Message()
 	}
+	c.Set(ContextKeyResponseCode, code)
 	c.JSON(http.StatusOK, Response{
-		Code:    code.Int(),
-		Message: msg,
+		Code:      code.Int(),
+		Message:   msg,
+		RequestID: c.GetString(ContextKeyRequestID),
 	})
 }
 
@@ -68,5 +98,15 @@ func InternalError(c *gin.Context, message string) {
 // HandleError 处理 service 层返回的错误
 func HandleError(c *gin.Context, err error) {
 	e := errcode.FromError(err)
-	Error(c, e.Code, e.Msg)
+	if e.Data == nil {
+		Error(c, e.Code, e.Msg)
+		return
+	}
+	c.Set(ContextKeyResponseCode, e.Code)
+	c.JSON(http.StatusOK, Response{
+		Code:      e.Code.Int(),
+		Message:   e.Msg,
+		Data:      e.Data,
+		RequestID: c.GetString(ContextKeyRequestID),
+	})
 }
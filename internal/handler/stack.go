@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStackRequest 创建应用组请求，类似 docker-compose，一次部署多个相互引用的应用
+type CreateStackRequest struct {
+	Name string `json:"name" binding:"required,max=64" example:"shop"`
+	// 应用列表，字段与创建应用相同（不支持 source）；字符串字段中的 ${svc:<应用名>} 会替换为该应用 Service 的集群内域名，
+	// 支持 env 与 service_annotations/deployment_annotations 的值、pre_stop_command 与 metadata
+	Apps []CreateAppRequest `json:"apps" binding:"required,min=1,max=20,dive"`
+}
+
+// CreateStack 创建应用组
+// @Summary 创建应用组
+// @Description 按清单一次创建多个应用并作为一组管理。创建前校验整个清单、应用名与配额，
+// @Description 之后按顺序创建，任一应用创建失败时删除已创建的应用，不会留下部分应用
+// @Tags 应用组
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body CreateStackRequest true "应用组清单"
+// @Success 200 {object} Response{data=service.StackDetail} "创建成功"
+// @Failure 400 {object} Response{data=ValidationErrorData} "参数错误，data.errors 为逐字段的错误"
+// @Failure 401 {object} Response "未授权"
+// @Router /stacks [post]
+func (h *AppHandler) CreateStack(c *gin.Context) {
+	var req CreateStackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	c.Set(ContextKeyAuditTarget, req.Name)
+	if errs := req.validate(); len(errs) > 0 {
+		ValidationFailed(c, errs)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	apps := make([]service.CreateAppRequest, 0, len(req.Apps))
+	for i := range req.Apps {
+		apps = append(apps, req.Apps[i].toServiceRequest())
+	}
+	stack, err := h.svc.CreateStack(context.Background(), service.CreateStackRequest{
+		Name:   req.Name,
+		UserID: userID,
+		Apps:   apps,
+	})
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, stack)
+}
+
+// GetStacks 获取应用组列表
+// @Summary 获取应用组列表
+// @Description 分页获取当前用户的应用组
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页条数，最大 100" default(20)
+// @Success 200 {object} Response{data=PageData{items=[]model.Stack}} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Router /stacks [get]
+func (h *AppHandler) GetStacks(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	stacks, total, err := h.svc.GetStacks(context.Background(), userID, page, pageSize)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	SuccessPaged(c, stacks, total, page, pageSize)
+}
+
+// GetStack 获取应用组详情
+// @Summary 获取应用组详情
+// @Description 获取应用组及其包含的应用
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用组ID"
+// @Success 200 {object} Response{data=service.StackDetail} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用组不存在"
+// @Router /stacks/{id} [get]
+func (h *AppHandler) GetStack(c *gin.Context) {
+	stackID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用组ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	stack, err := h.svc.GetStack(context.Background(), uint(stackID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, stack)
+}
+
+// DeleteStack 删除应用组
+// @Summary 删除应用组
+// @Description 删除应用组及其包含的所有应用；某个应用删除失败时保留应用组，可重试
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用组ID"
+// @Success 200 {object} Response "删除成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用组不存在"
+// @Router /stacks/{id} [delete]
+func (h *AppHandler) DeleteStack(c *gin.Context) {
+	stackID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用组ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	if err := h.svc.DeleteStack(context.Background(), uint(stackID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// ListStackApps 获取应用组内的应用
+// @Summary 获取应用组内的应用
+// @Description 获取应用组包含的应用，状态在后台异步同步，集群不可达时标记 status_stale
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用组ID"
+// @Success 200 {object} Response{data=[]service.AppListItem} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用组不存在"
+// @Router /stacks/{id}/apps [get]
+func (h *AppHandler) ListStackApps(c *gin.Context) {
+	stackID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用组ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	apps, err := h.svc.ListStackApps(context.Background(), uint(stackID), userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, apps)
+}
+
+// StartStack 启动应用组
+// @Summary 启动应用组
+// @Description 启动应用组内的所有应用，不等待就绪；逐个返回结果，单个应用失败不影响其他应用
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用组ID"
+// @Success 200 {object} Response{data=[]service.BatchResult} "各应用的执行结果"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用组不存在"
+// @Router /stacks/{id}/start [post]
+func (h *AppHandler) StartStack(c *gin.Context) {
+	h.operateStack(c, service.BatchStart)
+}
+
+// StopStack 停止应用组
+// @Summary 停止应用组
+// @Description 停止应用组内的所有应用；逐个返回结果，单个应用失败不影响其他应用
+// @Tags 应用组
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用组ID"
+// @Success 200 {object} Response{data=[]service.BatchResult} "各应用的执行结果"
+// @Failure 401 {object} Response "未授权"
+// @Failure 404 {object} Response "应用组不存在"
+// @Router /stacks/{id}/stop [post]
+func (h *AppHandler) StopStack(c *gin.Context) {
+	h.operateStack(c, service.BatchStop)
+}
+
+// operateStack 对应用组内所有应用执行批量操作
+func (h *AppHandler) operateStack(c *gin.Context, action string) {
+	stackID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的应用组ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	results, err := h.svc.OperateStack(context.Background(), uint(stackID), userID, action)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, results)
+}
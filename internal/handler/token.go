@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TokenHandler 访问令牌处理器
+type TokenHandler struct {
+	svc *service.TokenService
+}
+
+// NewTokenHandler 创建访问令牌处理器
+func NewTokenHandler(c *container.Container) *TokenHandler {
+	return &TokenHandler{
+		svc: service.NewTokenService(c),
+	}
+}
+
+// CreateTokenRequest 创建访问令牌请求
+type CreateTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=64" example:"gitlab-ci"`
+	Scopes        []string `json:"scopes" binding:"omitempty,dive,oneof=apps:read apps:write" example:"apps:read"` // 为空表示不限制
+	ExpiresInDays int      `json:"expires_in_days" binding:"min=0,max=365" example:"90"`                           // 0 表示永不过期
+}
+
+// CreateTokenResponse 创建访问令牌响应
+type CreateTokenResponse struct {
+	Token string                     `json:"token" example:"astro_pat_3f9a..."` // 明文令牌，仅在创建时返回一次
+	Info  *model.PersonalAccessToken `json:"info"`
+}
+
+// CreateToken 创建访问令牌
+// @Summary 创建访问令牌
+// @Description 创建长期有效的个人访问令牌，供 CI 等自动化场景使用；明文令牌仅返回一次
+// @Tags 访问令牌
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body CreateTokenRequest true "令牌信息"
+// @Success 200 {object} Response{data=CreateTokenResponse} "创建成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "不能使用访问令牌创建令牌"
+// @Router /tokens [post]
+func (h *TokenHandler) CreateToken(c *gin.Context) {
+	var req CreateTokenRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+	// 令牌管理需要登录凭证，防止泄露的令牌自我续期
+	if c.GetString(ContextKeyAuthType) == AuthTypePAT {
+		Forbidden(c, "访问令牌不能用于管理访问令牌")
+		return
+	}
+	// 模拟登录的 Token 有效期很短，不允许借此创建长期有效的访问令牌
+	if c.GetUint(ContextKeyActorID) > 0 {
+		Forbidden(c, "模拟登录不能创建访问令牌")
+		return
+	}
+
+	c.Set(ContextKeyAuditTarget, req.Name)
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	plaintext, token, err := h.svc.Create(userID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, CreateTokenResponse{Token: plaintext, Info: token})
+}
+
+// ListTokens 获取访问令牌列表
+// @Summary 获取访问令牌列表
+// @Description 获取当前用户的访问令牌（不含明文）
+// @Tags 访问令牌
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} Response{data=[]model.PersonalAccessToken} "成功"
+// @Failure 401 {object} Response "未授权"
+// @Router /tokens [get]
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+
+	tokens, err := h.svc.List(userID)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, tokens)
+}
+
+// RevokeToken 吊销访问令牌
+// @Summary 吊销访问令牌
+// @Description 吊销当前用户的访问令牌，立即失效
+// @Tags 访问令牌
+// @Produce json
+// @Security Bearer
+// @Param id path int true "令牌ID"
+// @Success 200 {object} Response "吊销成功"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "不能使用访问令牌吊销令牌"
+// @Failure 404 {object} Response "令牌不存在"
+// @Router /tokens/{id} [delete]
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+	tokenID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的令牌ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+	if c.GetString(ContextKeyAuthType) == AuthTypePAT {
+		Forbidden(c, "访问令牌不能用于管理访问令牌")
+		return
+	}
+
+	if err := h.svc.Revoke(uint(tokenID), userID); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// RegisterTokenRoutes 注册访问令牌路由
+func RegisterTokenRoutes(r *gin.RouterGroup, c *container.Container) {
+	h := NewTokenHandler(c)
+	tokens := r.Group("/tokens")
+	{
+		tokens.POST("", h.CreateToken)
+		tokens.GET("", h.ListTokens)
+		tokens.DELETE("/:id", h.RevokeToken)
+	}
+}
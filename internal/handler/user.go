@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"net/mail"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
 	"github.com/cuihe500/astro/internal/service"
+	"github.com/cuihe500/astro/pkg/errcode"
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,29 +14,37 @@ type UserHandler struct {
 	svc *service.UserService
 }
 
-func NewUserHandler() *UserHandler {
+func NewUserHandler(c *container.Container) *UserHandler {
 	return &UserHandler{
-		svc: service.NewUserService(),
+		svc: service.NewUserService(c),
 	}
 }
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required" example:"johndoe"`
+	Username string `json:"username" binding:"required,excludes=@" example:"johndoe"` // 不能包含 @，以便登录时区分邮箱
 	Password string `json:"password" binding:"required" example:"password123"`
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
 }
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required" example:"johndoe"`
+	Username string `json:"username" binding:"required" example:"johndoe"` // 用户名或邮箱
 	Password string `json:"password" binding:"required" example:"password123"`
+	Remember bool   `json:"remember" example:"false"` // 记住我，为 true 时签发长有效期 Token
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
-	UUID  string `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Token     string    `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	UUID      string    `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-01-01T00:00:00Z"` // Token 过期时间，客户端可据此提前刷新
+	ExpiresIn int64     `json:"expires_in" example:"86400"`                // Token 剩余有效秒数
+}
+
+// UpdateEmailRequest 修改邮箱请求
+type UpdateEmailRequest struct {
+	Email string `json:"email" binding:"required" example:"new@example.com"`
 }
 
 // Register 用户注册
@@ -47,11 +60,11 @@ type LoginResponse struct {
 // @Router /register [post]
 func (h *UserHandler) Register(c *gin.Context) {
 	var req RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		BadRequest(c, "参数错误: "+err.Error())
+	if !bindJSON(c, &req) {
 		return
 	}
 
+	c.Set(ContextKeyAuditTarget, req.Username)
 	if err := h.svc.Register(req.Username, req.Password, req.Email); err != nil {
 		HandleError(c, err)
 		return
@@ -62,7 +75,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户登录获取 Token
+// @Description 使用用户名或邮箱登录获取 Token
 // @Tags 用户
 // @Accept json
 // @Produce json
@@ -73,23 +86,152 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Router /login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		BadRequest(c, "参数错误: "+err.Error())
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	c.Set(ContextKeyAuditTarget, req.Username)
+	token, expiresAt, user, err := h.svc.Login(req.Username, req.Password, req.Remember)
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+	// 登录接口无需认证，审计日志的 user_id 取登录成功的用户
+	c.Set("user_id", user.ID)
+
+	Success(c, LoginResponse{
+		Token:     token,
+		UUID:      user.UUID,
+		ExpiresAt: expiresAt,
+		ExpiresIn: int64(time.Until(expiresAt).Seconds()),
+	})
+}
+
+// UpdateEmail 修改邮箱
+// @Summary 修改邮箱
+// @Description 修改当前登录用户的邮箱
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body UpdateEmailRequest true "新邮箱"
+// @Success 200 {object} Response "修改成功"
+// @Failure 400 {object} Response "参数错误"
+// @Failure 401 {object} Response "未授权"
+// @Failure 403 {object} Response "不能使用访问令牌修改"
+// @Router /users/email [post]
+func (h *UserHandler) UpdateEmail(c *gin.Context) {
+	var req UpdateEmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil || addr.Address != req.Email {
+		ErrorWithCode(c, errcode.ErrInvalidEmail)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		Unauthorized(c, "未登录")
+		return
+	}
+	// 账号设置仅允许登录凭证修改，访问令牌权限范围只覆盖应用操作
+	if c.GetString(ContextKeyAuthType) == AuthTypePAT {
+		Forbidden(c, "访问令牌不能用于修改账号信息")
+		return
+	}
+	if c.GetUint(ContextKeyActorID) > 0 {
+		Forbidden(c, "模拟登录不能修改账号信息")
 		return
 	}
 
-	token, user, err := h.svc.Login(req.Username, req.Password)
+	if err := h.svc.UpdateEmail(userID, req.Email); err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// IntrospectResponse 当前凭证信息，不包含签名等敏感内容
+type IntrospectResponse struct {
+	UserID    uint       `json:"user_id"`
+	UUID      string     `json:"uuid"`
+	Role      string     `json:"role"`
+	AuthType  string     `json:"auth_type"`            // jwt（登录 Token）/pat（个人访问令牌）
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`  // 早期签发的 Token 可能为空
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 为空表示永不过期
+	ExpiresIn *int64     `json:"expires_in,omitempty"` // 剩余有效秒数
+	Scopes    []string   `json:"scopes"`               // 访问令牌的权限范围，为空表示不限制
+	ActorID   *uint      `json:"actor_id,omitempty"`   // 管理员模拟登录时为实际操作的管理员 ID
+}
+
+// Introspect 查看当前凭证
+// @Summary 查看当前凭证
+// @Description 返回当前请求所用 Token 的用户、角色、签发与过期时间及权限范围，便于客户端安排续期
+// @Tags 用户
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} Response{data=IntrospectResponse} "成功"
+// @Failure 401 {object} Response "未授权或 Token 无效"
+// @Router /auth/introspect [get]
+func (h *UserHandler) Introspect(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		ErrorWithCode(c, errcode.ErrTokenInvalid)
+		return
+	}
+
+	user, err := h.svc.GetUser(userID)
 	if err != nil {
 		HandleError(c, err)
 		return
 	}
 
-	Success(c, LoginResponse{Token: token, UUID: user.UUID})
+	resp := IntrospectResponse{
+		UserID:   user.ID,
+		UUID:     user.UUID,
+		Role:     user.Role,
+		AuthType: c.GetString(ContextKeyAuthType),
+		Scopes:   c.GetStringSlice(ContextKeyTokenScopes),
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	if actorID := c.GetUint(ContextKeyActorID); actorID > 0 {
+		resp.ActorID = &actorID
+	}
+	if v, ok := c.Get(ContextKeyTokenIssuedAt); ok {
+		if t, ok := v.(time.Time); ok {
+			resp.IssuedAt = &t
+		}
+	}
+	if v, ok := c.Get(ContextKeyTokenExpiresAt); ok {
+		if t, ok := v.(time.Time); ok {
+			expiresIn := int64(time.Until(t).Seconds())
+			resp.ExpiresAt = &t
+			resp.ExpiresIn = &expiresIn
+		}
+	}
+
+	Success(c, resp)
 }
 
 // RegisterRoutes 注册用户相关路由
-func RegisterUserRoutes(r *gin.RouterGroup) {
-	h := NewUserHandler()
+func RegisterUserRoutes(r *gin.RouterGroup, c *container.Container) {
+	h := NewUserHandler(c)
 	r.POST("/register", h.Register)
 	r.POST("/login", h.Login)
 }
+
+// RegisterUserAuthRoutes 注册需要认证的用户路由
+func RegisterUserAuthRoutes(r *gin.RouterGroup, c *container.Container) {
+	h := NewUserHandler(c)
+	users := r.Group("/users")
+	{
+		users.POST("/email", h.UpdateEmail)
+	}
+	r.GET("/auth/introspect", h.Introspect)
+}
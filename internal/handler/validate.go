@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// reservedAnnotationKeys 平台内部使用的注解键，不允许用户设置
+var reservedAnnotationKeys = map[string]bool{
+	"kubectl.kubernetes.io/restartedAt": true,
+}
+
+// reservedEnvPrefixes 保留的环境变量名前缀：KUBERNETES_ 由 K8s 注入，ASTRO_ 预留给平台
+var reservedEnvPrefixes = []string{"KUBERNETES_", "ASTRO_"}
+
+// validate 校验创建应用请求中 binding 标签无法表达的规则（字段格式、字段间约束），
+// 返回所有不合法的字段而不是遇到第一个错误就停止；新增的创建入口应复用该方法
+func (r *CreateAppRequest) validate() FieldErrors {
+	var errs FieldErrors
+	if err := validateAppName(r.Name); err != nil {
+		errs.Add("name", "%s", err.Error())
+	}
+	if (r.Image == "") == (r.Source == nil) {
+		errs.Add("image", "image 与 source 必须且只能指定一个")
+	}
+	if r.Source != nil {
+		r.Source.validate(&errs)
+	}
+	validateAnnotations(&errs, "service_annotations", r.ServiceAnnotations)
+	validateAnnotations(&errs, "deployment_annotations", r.DeploymentAnnotations)
+	validateEnv(&errs, r.Env)
+
+	if r.Kind == k8s.KindCronJob {
+		if _, err := cron.ParseStandard(r.Schedule); err != nil {
+			errs.Add("schedule", "无效的 cron 表达式: %s", err.Error())
+		}
+		if r.RestartPolicy == "Always" {
+			errs.Add("restart_policy", "定时任务只能为 OnFailure 或 Never")
+		}
+		if r.Headless {
+			errs.Add("headless", "定时任务应用不创建 Service，不能设置")
+		}
+		if r.Spread != "" {
+			errs.Add("spread", "定时任务应用不能设置")
+		}
+		if r.MinReadySeconds != nil {
+			errs.Add("min_ready_seconds", "定时任务应用不能设置")
+		}
+	} else {
+		if r.Schedule != "" {
+			errs.Add("schedule", "仅定时任务应用可设置")
+		}
+		// Deployment/StatefulSet 的 Pod 只允许 Always，提前拒绝避免被 API Server 拒绝后才发现
+		if r.RestartPolicy != "" && r.RestartPolicy != "Always" {
+			errs.Add("restart_policy", "常驻应用只能为 Always")
+		}
+		if r.BackoffLimit != nil {
+			errs.Add("backoff_limit", "仅定时任务应用可设置")
+		}
+	}
+
+	if r.Kind == k8s.KindStatefulSet {
+		r.Storage.validate(&errs)
+		if r.Hostname != "" || r.Subdomain != "" {
+			errs.Add("hostname", "有状态应用的 hostname 与 subdomain 由 K8s 管理，不能设置")
+		}
+	} else if r.Storage != nil {
+		errs.Add("storage", "仅有状态应用可设置")
+	}
+	validatePodIdentity(&errs, r.WorkingDir, r.Hostname, r.Subdomain)
+	r.DNSConfig.validate(&errs, r.DNSPolicy)
+
+	if r.ServiceAccountName != "" {
+		if msgs := validation.IsDNS1123Subdomain(r.ServiceAccountName); len(msgs) > 0 {
+			errs.Add("service_account_name", "无效的名称 %q: %s", r.ServiceAccountName, strings.Join(msgs, "; "))
+		}
+	} else if r.CreateServiceAccount {
+		errs.Add("create_service_account", "需要同时设置 service_account_name")
+	}
+	validateMetadata(&errs, r.Metadata)
+
+	if r.Headless && r.Port <= 0 && r.Kind != k8s.KindStatefulSet {
+		errs.Add("headless", "需要同时设置 port")
+	}
+	if sc := r.SecurityContext; sc != nil && sc.RunAsNonRoot != nil && *sc.RunAsNonRoot &&
+		sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		errs.Add("security_context.run_as_user", "run_as_non_root 为 true 时不能为 0")
+	}
+	return errs
+}
+
+// validateAppName 校验应用名。应用名同时用作 Deployment 与 Service 名称，
+// Service 名称要求 RFC 1035 标签（RFC 1123 标签的子集，且必须以字母开头）
+func validateAppName(name string) error {
+	if msgs := validation.IsDNS1035Label(name); len(msgs) > 0 {
+		return errors.New("应用名只能包含小写字母、数字和连字符，以字母开头、字母或数字结尾，且不超过 63 个字符")
+	}
+	return nil
+}
+
+// validateAnnotations 校验注解键是否符合 K8s 规范（可选前缀/名称），按原样校验，前缀必须为小写域名
+func validateAnnotations(errs *FieldErrors, field string, annotations map[string]string) {
+	for key := range annotations {
+		if reservedAnnotationKeys[key] {
+			errs.Add(field, "注解键 %q 为平台保留键", key)
+			continue
+		}
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			errs.Add(field, "无效的注解键 %q: %s", key, strings.Join(msgs, "; "))
+		}
+	}
+}
+
+// validate 校验环境变量
+func (r *SetAppEnvRequest) validate() FieldErrors {
+	var errs FieldErrors
+	validateEnv(&errs, r.Env)
+	return errs
+}
+
+// validateEnv 校验环境变量名合法、不重复且不使用保留前缀
+func validateEnv(errs *FieldErrors, env []model.EnvVar) {
+	seen := make(map[string]bool, len(env))
+	for i, e := range env {
+		field := fmt.Sprintf("env[%d].name", i)
+		if msgs := validation.IsEnvVarName(e.Name); len(msgs) > 0 {
+			errs.Add(field, "无效的环境变量名 %q: %s", e.Name, strings.Join(msgs, "; "))
+			continue
+		}
+		if prefix := reservedEnvPrefix(e.Name); prefix != "" {
+			errs.Add(field, "环境变量名 %q 使用了保留前缀 %s", e.Name, prefix)
+			continue
+		}
+		if seen[e.Name] {
+			errs.Add(field, "环境变量 %q 重复", e.Name)
+			continue
+		}
+		seen[e.Name] = true
+	}
+}
+
+// reservedEnvPrefix 返回环境变量名使用的保留前缀，未使用时返回空字符串
+func reservedEnvPrefix(name string) string {
+	for _, prefix := range reservedEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// validateMetadata 校验应用元数据为 JSON 对象且不超过大小上限，为空时跳过
+func validateMetadata(errs *FieldErrors, metadata json.RawMessage) {
+	trimmed := bytes.TrimSpace(metadata)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return
+	}
+	if len(trimmed) > service.MaxMetadataBytes {
+		errs.Add("metadata", "不能超过 %d 字节", service.MaxMetadataBytes)
+		return
+	}
+	if !json.Valid(trimmed) || trimmed[0] != '{' {
+		errs.Add("metadata", "必须为 JSON 对象")
+	}
+}
+
+// validatePodIdentity 校验容器工作目录与 Pod 主机名、子域名
+func validatePodIdentity(errs *FieldErrors, workingDir, hostname, subdomain string) {
+	if workingDir != "" && !path.IsAbs(workingDir) {
+		errs.Add("working_dir", "必须为绝对路径: %s", workingDir)
+	}
+	for _, f := range []struct{ field, value string }{{"hostname", hostname}, {"subdomain", subdomain}} {
+		if f.value == "" {
+			continue
+		}
+		if msgs := validation.IsDNS1123Label(f.value); len(msgs) > 0 {
+			errs.Add(f.field, "无效的 DNS 标签 %q: %s", f.value, strings.Join(msgs, "; "))
+		}
+	}
+}
+
+// validate 校验应用组清单：逐个校验应用，并检查应用名重复与 ${svc:<应用名>} 引用，全部通过才会开始创建
+func (r *CreateStackRequest) validate() FieldErrors {
+	var errs FieldErrors
+	index := make(map[string]int, len(r.Apps))
+	for i := range r.Apps {
+		app := &r.Apps[i]
+		prefix := fmt.Sprintf("apps[%d]", i)
+		for _, fe := range app.validate() {
+			errs.Add(prefix+"."+fe.Field, "%s", fe.Message)
+		}
+		if app.Source != nil {
+			errs.Add(prefix+".source", "应用组暂不支持源码构建，请先构建镜像后使用 image")
+		}
+		if j, ok := index[app.Name]; ok {
+			errs.Add(prefix+".name", "与 apps[%d] 重名", j)
+			continue
+		}
+		index[app.Name] = i
+	}
+
+	for i := range r.Apps {
+		app := &r.Apps[i]
+		prefix := fmt.Sprintf("apps[%d]", i)
+		check := func(field, value string) {
+			for _, name := range service.StackReferences(value) {
+				j, ok := index[name]
+				if !ok {
+					errs.Add(field, "引用的应用 %q 不在应用组中", name)
+					continue
+				}
+				if target := r.Apps[j]; target.Port <= 0 || target.Kind == k8s.KindCronJob {
+					errs.Add(field, "引用的应用 %q 没有 Service，需要设置 port 且不能为定时任务", name)
+				}
+			}
+		}
+		for k, arg := range app.PreStopCommand {
+			check(fmt.Sprintf("%s.pre_stop_command[%d]", prefix, k), arg)
+		}
+		for k, e := range app.Env {
+			check(fmt.Sprintf("%s.env[%d].value", prefix, k), e.Value)
+		}
+		for key, value := range app.ServiceAnnotations {
+			check(prefix+".service_annotations."+key, value)
+		}
+		for key, value := range app.DeploymentAnnotations {
+			check(prefix+".deployment_annotations."+key, value)
+		}
+		check(prefix+".metadata", string(app.Metadata))
+	}
+	return errs
+}
+
+// patchAppNotNullFields 不能为 null 的补丁字段
+var patchAppNotNullFields = []string{"replicas", "image", "image_pull_policy"}
+
+// parse 解析补丁请求体：拒绝未知字段与不能为 null 的字段为 null，并按 JSON Merge Patch 语义处理 null
+func (r *PatchAppRequest) parse(body []byte) FieldErrors {
+	var errs FieldErrors
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		errs.Add("", "请求体必须为 JSON 对象")
+		return errs
+	}
+	known := map[string]bool{"metadata": true, "env": true}
+	for _, name := range patchAppNotNullFields {
+		known[name] = true
+		if value, ok := fields[name]; ok && string(bytes.TrimSpace(value)) == "null" {
+			errs.Add(name, "不能为 null")
+		}
+	}
+	for name := range fields {
+		if !known[name] {
+			errs.Add(name, "未知字段或不支持修改")
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+		return errs
+	}
+
+	if err := json.Unmarshal(body, r); err != nil {
+		return bindErrors(err)
+	}
+	if err := binding.Validator.ValidateStruct(r); err != nil {
+		return bindErrors(err)
+	}
+	// Merge Patch 中 null 表示删除：环境变量为 null 时清空
+	if value, ok := fields["env"]; ok && r.Env == nil && string(bytes.TrimSpace(value)) == "null" {
+		r.Env = &[]model.EnvVar{}
+	}
+	if r.Env != nil {
+		validateEnv(&errs, *r.Env)
+	}
+	// metadata 为 null 时 Unmarshal 会保留原始的 null，交给服务层清空
+	validateMetadata(&errs, r.Metadata)
+	return errs
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/cuihe500/astro/internal/model"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"无前缀", "owner", false},
+		{"名称含大写", "Owner", false},
+		{"带前缀", "example.com/owner", false},
+		{"前缀含大写", "Example.com/owner", true},
+		{"名称含非法字符", "example.com/own er", true},
+		{"空前缀", "/owner", true},
+		{"名称过长", "a123456789012345678901234567890123456789012345678901234567890123", true},
+		{"平台保留键", "kubectl.kubernetes.io/restartedAt", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs FieldErrors
+			validateAnnotations(&errs, "service_annotations", map[string]string{tt.key: "v"})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateAnnotations(%q) = %v, wantErr %v", tt.key, errs, tt.wantErr)
+			}
+			for _, e := range errs {
+				if e.Field != "service_annotations" {
+					t.Errorf("错误字段 = %q, want service_annotations", e.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStackEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []model.EnvVar
+		wantField string // 为空表示校验通过
+	}{
+		{"引用组内应用", []model.EnvVar{{Name: "DB_HOST", Value: "${svc:db}"}}, ""},
+		{"引用不存在的应用", []model.EnvVar{{Name: "CACHE_HOST", Value: "${svc:cache}"}}, "apps[0].env[0].value"},
+		{"环境变量名无效", []model.EnvVar{{Name: "1DB", Value: "x"}}, "apps[0].env[0].name"},
+		{"保留前缀", []model.EnvVar{{Name: "ASTRO_TOKEN", Value: "x"}}, "apps[0].env[0].name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &CreateStackRequest{Name: "shop", Apps: []CreateAppRequest{
+				{Name: "api", Image: "nginx:latest", Port: 80, Env: tt.env},
+				{Name: "db", Image: "postgres:16", Port: 5432},
+			}}
+			errs := r.validate()
+			if tt.wantField == "" {
+				if len(errs) > 0 {
+					t.Errorf("validate() = %v, want 通过", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.wantField {
+				t.Errorf("validate() = %v, want 字段 %s", errs, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/cuihe500/astro/pkg/version"
+	"github.com/gin-gonic/gin"
+)
+
+// GetVersion 获取构建版本信息
+// @Summary 获取版本信息
+// @Description 返回当前服务的版本号、Git 提交、构建时间和 Go 版本（路径不带 /api/v1 前缀）
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} Response{data=version.Info} "成功"
+// @Router /version [get]
+func GetVersion(c *gin.Context) {
+	Success(c, version.Get())
+}
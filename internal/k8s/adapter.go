@@ -1,10 +1,12 @@
 package k8s
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -12,31 +14,130 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 )
 
 // AppSpec 应用规格
 type AppSpec struct {
-	Name      string
-	Namespace string
-	Image     string
-	Replicas  int32
-	Port      int32
-	Labels    map[string]string
+	Name                  string
+	Namespace             string
+	Image                 string
+	Replicas              int32
+	Port                  int32
+	Labels                map[string]string
+	Kind                  string            // deployment（默认）/cronjob
+	Schedule              string            // Kind 为 cronjob 时的 cron 表达式
+	RestartPolicy         string            // Kind 为 cronjob 时的 Pod 重启策略（OnFailure/Never），Deployment 固定为 Always
+	BackoffLimit          *int32            // Kind 为 cronjob 时单次任务的失败重试次数，nil 使用 K8s 默认值（6）
+	ServiceAnnotations    map[string]string // Service 注解，如负载均衡配置
+	DeploymentAnnotations map[string]string // Deployment 注解，仅作用于 Deployment 本身，不会触发滚动更新
+	Security              *SecurityOptions  // 容器安全上下文，nil 表示不设置
+	ImagePullPolicy       string            // 镜像拉取策略，为 Always 时 RestartApp 可拉取重新推送的同名 tag
+	Headless              bool              // Service 使用 ClusterIP: None，DNS 直接解析到各 Pod IP
+	// RevisionHistoryLimit 保留的历史 ReplicaSet 数，nil 使用 K8s 默认值
+	RevisionHistoryLimit *int32
+	// ProgressDeadlineSeconds 滚动更新超时秒数，nil 使用 K8s 默认值
+	ProgressDeadlineSeconds *int32
+	// MinReadySeconds 新 Pod 持续就绪多少秒后才视为可用，0 表示就绪即可用
+	MinReadySeconds int32
+	// TerminationGracePeriodSeconds 优雅退出等待秒数，nil 使用 K8s 默认值
+	TerminationGracePeriodSeconds *int64
+	// TerminationMessagePolicy/TerminationMessagePath 容器终止消息策略与文件路径，留空使用 K8s 默认值（File、/dev/termination-log）
+	TerminationMessagePolicy string
+	TerminationMessagePath   string
+	// PreStopCommand 容器停止前执行的命令，用于排空连接等收尾工作
+	PreStopCommand []string
+	// Env 容器环境变量
+	Env []EnvVar
+	// WorkingDir 容器工作目录，留空使用镜像的 WORKDIR
+	WorkingDir string
+	// Hostname/Subdomain Pod 主机名与子域名，留空使用 K8s 默认值（主机名为 Pod 名）
+	Hostname  string
+	Subdomain string
+	// ServiceAccountName Pod 使用的 ServiceAccount，留空使用命名空间的 default
+	ServiceAccountName string
+	// CreateServiceAccount 为 true 时在 ServiceAccount 不存在时自动创建
+	CreateServiceAccount bool
+	// DNSPolicy Pod DNS 策略，留空使用 K8s 默认值（ClusterFirst）
+	DNSPolicy string
+	// DNSNameservers/DNSSearches/DNSOptions 自定义 DNS 配置，与 DNSPolicy 生成的配置合并；选项值为空表示无值选项
+	DNSNameservers []string
+	DNSSearches    []string
+	DNSOptions     map[string]string
+	// OwnerID/OwnerUUID 所属用户，写入资源注解，操作前用于校验归属
+	OwnerID   uint
+	OwnerUUID string
+	// AppID 平台应用 ID，加入选择器使其全局唯一
+	AppID uint
+	// Spread 副本打散维度：node（跨节点）/zone（跨可用区），留空不限制
+	Spread string
+	// StorageSize/StorageClass/StorageMountPath Kind 为 statefulset 时每个副本的持久卷容量（如 10Gi）、存储类和挂载路径
+	StorageSize      string
+	StorageClass     string
+	StorageMountPath string
 }
 
+// SecurityOptions 容器安全上下文选项
+type SecurityOptions struct {
+	RunAsNonRoot           bool
+	RunAsUser              *int64
+	ReadOnlyRootFilesystem bool
+	DropCapabilities       []string
+}
+
+// 副本打散维度
+const (
+	SpreadNode = "node"
+	SpreadZone = "zone"
+)
+
+// 应用工作负载类型
+const (
+	KindDeployment  = "deployment"
+	KindCronJob     = "cronjob"
+	KindStatefulSet = "statefulset"
+)
+
 // AppStatus 应用状态
 type AppStatus struct {
-	Status        string // pending/running/stopped/starting/restarting/unknown
-	ReadyReplicas int32
-	Replicas      int32
-	Pods          []PodInfo
+	Status        string    `json:"status"` // pending/running/stopped/starting/restarting/rollout_failed/unknown
+	ReadyReplicas int32     `json:"ready_replicas"`
+	Replicas      int32     `json:"replicas"`
+	RestartCount  int32     `json:"restart_count"`  // 所有 Pod 容器重启次数之和
+	RolloutPaused bool      `json:"rollout_paused"` // Deployment 滚动更新已暂停
+	Pods          []PodInfo `json:"pods"`
 }
 
 // PodInfo Pod 信息
 type PodInfo struct {
-	Name   string
-	Status string
-	Ready  bool
+	Name              string            `json:"name"`
+	Ordinal           *int              `json:"ordinal,omitempty"` // StatefulSet 副本序号
+	Status            string            `json:"status"`
+	Ready             bool              `json:"ready"`
+	RestartCount      int32             `json:"restart_count"` // Pod 内所有容器重启次数之和
+	ContainerStatuses []ContainerStatus `json:"container_statuses"`
+}
+
+// ContainerStatus 容器状态
+type ContainerStatus struct {
+	Name         string `json:"name"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restart_count"`
+	State        string `json:"state"`               // running/waiting/terminated/unknown
+	Reason       string `json:"reason,omitempty"`    // 如 CrashLoopBackOff、OOMKilled
+	Message      string `json:"message,omitempty"`   // 当前状态的详细信息，已终止时为容器的终止消息
+	ExitCode     *int32 `json:"exit_code,omitempty"` // 已终止时的退出码
+	// LastTermination 上一次终止的信息，CrashLoopBackOff 时当前状态为 waiting，崩溃原因在这里
+	LastTermination *ContainerTermination `json:"last_termination,omitempty"`
+}
+
+// ContainerTermination 容器终止信息
+type ContainerTermination struct {
+	Reason     string    `json:"reason,omitempty"`  // 如 Error、OOMKilled、Completed
+	Message    string    `json:"message,omitempty"` // 终止消息，FallbackToLogsOnError 策略下可能为日志末尾
+	ExitCode   int32     `json:"exit_code"`
+	FinishedAt time.Time `json:"finished_at"`
 }
 
 // AppAdapter K8s 应用适配器接口
@@ -45,8 +146,8 @@ type AppAdapter interface {
 	EnsureNamespace(ctx context.Context, namespace string) error
 	// CreateApp 创建应用
 	CreateApp(ctx context.Context, spec AppSpec) error
-	// DeleteApp 删除应用
-	DeleteApp(ctx context.Context, name, namespace string) error
+	// DeleteApp 删除应用，只删除 kind 对应的工作负载，kind 留空按 Deployment 处理
+	DeleteApp(ctx context.Context, name, namespace, kind string) error
 	// ScaleApp 调整副本数
 	ScaleApp(ctx context.Context, name, namespace string, replicas int32) error
 	// GetAppStatus 获取应用状态
@@ -55,20 +156,79 @@ type AppAdapter interface {
 	RestartApp(ctx context.Context, name, namespace string) error
 	// GetAppLogs 获取应用日志
 	GetAppLogs(ctx context.Context, name, namespace string, lines int64) (string, error)
+	// GetAllPodLogs 获取应用所有 Pod 的日志，每行以 [Pod 名] 开头，总行数不超过 lines
+	GetAllPodLogs(ctx context.Context, name, namespace string, lines int64) (string, error)
+	// FollowAppLogs 持续跟踪应用日志，容器重启或 Pod 被替换后自动重连，ctx 取消后关闭返回的 channel
+	FollowAppLogs(ctx context.Context, name, namespace string, lines int64) (<-chan string, error)
+	// WaitForReady 等待应用所有副本就绪，超时返回错误和最后一次获取到的状态
+	WaitForReady(ctx context.Context, name, namespace string, timeout time.Duration) (*AppStatus, error)
+	// GetAppEvents 获取应用相关的 K8s 事件
+	GetAppEvents(ctx context.Context, name, namespace string) ([]Event, error)
+	// GetAppManifests 获取应用在集群中的资源清单（YAML）
+	GetAppManifests(ctx context.Context, name, namespace string) (string, error)
+	// WatchApp 监听应用状态变化
+	WatchApp(ctx context.Context, name, namespace string) (<-chan *AppStatus, error)
+	// VerifyOwner 校验集群资源是否由 Astro 创建且属于指定用户
+	VerifyOwner(ctx context.Context, name, namespace string, ownerID uint) error
+	// ListRevisions 列出应用历史版本
+	ListRevisions(ctx context.Context, name, namespace string) ([]Revision, error)
+	// RollbackApp 回滚应用到指定版本，toRevision 为 0 表示上一个版本
+	RollbackApp(ctx context.Context, name, namespace string, toRevision int64) error
+	// ListAppPods 列出应用的所有 Pod
+	ListAppPods(ctx context.Context, name, namespace string) ([]PodInfo, error)
+	// GetAppPod 获取应用的指定 Pod，不属于该应用时返回 ErrPodNotFound
+	GetAppPod(ctx context.Context, name, namespace, podName string) (*PodDetail, error)
+	// DeleteAppPod 删除应用的指定 Pod，不属于该应用时返回 ErrPodNotFound
+	DeleteAppPod(ctx context.Context, name, namespace, podName string) error
+	// RecreateAppPods 同时删除应用的所有 Pod，由工作负载控制器全部重建
+	RecreateAppPods(ctx context.Context, name, namespace string) error
+	// SetRolloutPaused 暂停或继续 Deployment 的滚动更新
+	SetRolloutPaused(ctx context.Context, name, namespace string, paused bool) error
+	// SetAppEnv 替换应用容器的全部环境变量，Deployment 与 StatefulSet 会触发滚动更新
+	SetAppEnv(ctx context.Context, name, namespace string, env []EnvVar) error
+	// UpdateAppContainer 一次性修改应用容器的镜像、拉取策略与环境变量，只触发一次滚动更新
+	UpdateAppContainer(ctx context.Context, name, namespace string, update ContainerUpdate) error
+	// RecreateApp 删除工作负载后按原配置重建，StatefulSet 的持久卷声明一并删除；定时任务应用返回 ErrRecreateNotSupported
+	RecreateApp(ctx context.Context, name, namespace string, update ContainerUpdate) error
+	// GetNamespaceUsage 汇总命名空间的资源 requests/limits 与实时用量，非 Astro 创建的命名空间返回 ErrNamespaceNotManaged
+	GetNamespaceUsage(ctx context.Context, namespace string) (*NamespaceUsage, error)
+	// StartBuild 创建源码构建 Job，返回 Job 名
+	StartBuild(ctx context.Context, spec BuildSpec) (string, error)
+	// GetBuildStatus 获取构建 Job 状态
+	GetBuildStatus(ctx context.Context, job, namespace string) (*BuildStatus, error)
 }
 
 // ClientGoAdapter 基于 client-go 的适配器实现
-type ClientGoAdapter struct{}
+type ClientGoAdapter struct {
+	client kubernetes.Interface
+	// defaultLabels 附加到平台创建的命名空间与应用资源上的默认标签，优先级最低
+	defaultLabels map[string]string
+}
 
-// NewClientGoAdapter 创建 ClientGoAdapter
-func NewClientGoAdapter() *ClientGoAdapter {
-	return &ClientGoAdapter{}
+// NewClientGoAdapter 创建 ClientGoAdapter，defaultLabels 可为 nil
+func NewClientGoAdapter(client kubernetes.Interface, defaultLabels map[string]string) *ClientGoAdapter {
+	return &ClientGoAdapter{client: client, defaultLabels: defaultLabels}
+}
+
+// withDefaultLabels 以默认标签为底合并 labels，同名时 labels 优先
+func (a *ClientGoAdapter) withDefaultLabels(labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(a.defaultLabels)+len(labels))
+	for k, v := range a.defaultLabels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
 }
 
 // EnsureNamespace 确保命名空间存在
 func (a *ClientGoAdapter) EnsureNamespace(ctx context.Context, namespace string) error {
-	_, err := Client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	existing, err := a.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
 	if err == nil {
+		if existing.Status.Phase == corev1.NamespaceTerminating {
+			return fmt.Errorf("%w: %s", ErrNamespaceTerminating, namespace)
+		}
 		return nil
 	}
 	if !errors.IsNotFound(err) {
@@ -78,12 +238,36 @@ func (a *ClientGoAdapter) EnsureNamespace(ctx context.Context, namespace string)
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: namespace,
-			Labels: map[string]string{
-				"managed-by": "astro",
-			},
+			Labels: a.withDefaultLabels(map[string]string{
+				ManagedByLabel: ManagedByValue,
+			}),
 		},
 	}
-	_, err = Client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	_, err = a.client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	return err
+}
+
+// ensureServiceAccount 确保 ServiceAccount 存在，已存在时不做修改；
+// ServiceAccount 可能被多个应用共用，删除应用时不会随之删除
+func (a *ClientGoAdapter) ensureServiceAccount(ctx context.Context, namespace, name string) error {
+	_, err := a.client.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: a.withDefaultLabels(map[string]string{
+				ManagedByLabel: ManagedByValue,
+			}),
+		},
+	}
+	_, err = a.client.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
 	return err
 }
 
@@ -93,56 +277,53 @@ func (a *ClientGoAdapter) CreateApp(ctx context.Context, spec AppSpec) error {
 	if err := a.EnsureNamespace(ctx, spec.Namespace); err != nil {
 		return fmt.Errorf("创建命名空间失败: %w", err)
 	}
-
-	// 构建标签
-	labels := map[string]string{
-		"app":        spec.Name,
-		"managed-by": "astro",
+	if spec.CreateServiceAccount && spec.ServiceAccountName != "" {
+		if err := a.ensureServiceAccount(ctx, spec.Namespace, spec.ServiceAccountName); err != nil {
+			return fmt.Errorf("创建 ServiceAccount 失败: %w", err)
+		}
 	}
-	for k, v := range spec.Labels {
+
+	// 构建标签，优先级：平台标签（选择器、管理与归属）> 应用标签 > 默认标签，平台标签不可被覆盖
+	labels := a.withDefaultLabels(spec.Labels)
+	for k, v := range selectorLabels(spec) {
 		labels[k] = v
 	}
+	labels[ManagedByLabel] = ManagedByValue
+	if spec.OwnerID > 0 {
+		labels[OwnerIDLabel] = strconv.FormatUint(uint64(spec.OwnerID), 10)
+	}
+
+	template := buildPodTemplate(spec, labels)
+
+	// 定时任务只创建 CronJob，不创建 Service
+	if spec.Kind == KindCronJob {
+		return a.createCronJob(ctx, spec, labels, template)
+	}
+	if spec.Kind == KindStatefulSet {
+		return a.createStatefulSet(ctx, spec, labels, template)
+	}
 
 	// 创建 Deployment
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      spec.Name,
-			Namespace: spec.Namespace,
-			Labels:    labels,
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Labels:      labels,
+			Annotations: ownerAnnotations(spec, spec.DeploymentAnnotations),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &spec.Replicas,
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app": spec.Name,
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  spec.Name,
-							Image: spec.Image,
-						},
-					},
-				},
+				MatchLabels: selectorLabels(spec),
 			},
+			Template:                template,
+			RevisionHistoryLimit:    spec.RevisionHistoryLimit,
+			ProgressDeadlineSeconds: spec.ProgressDeadlineSeconds,
+			MinReadySeconds:         spec.MinReadySeconds,
 		},
 	}
 
-	// 如果指定了端口，添加端口配置
-	if spec.Port > 0 {
-		deployment.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{
-			{
-				ContainerPort: spec.Port,
-			},
-		}
-	}
-
-	_, err := Client.AppsV1().Deployments(spec.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	_, err := a.client.AppsV1().Deployments(spec.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("创建 Deployment 失败: %w", err)
 	}
@@ -151,14 +332,15 @@ func (a *ClientGoAdapter) CreateApp(ctx context.Context, spec AppSpec) error {
 	if spec.Port > 0 {
 		service := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      spec.Name,
-				Namespace: spec.Namespace,
-				Labels:    labels,
+				Name:        spec.Name,
+				Namespace:   spec.Namespace,
+				Labels:      labels,
+				Annotations: ownerAnnotations(spec, spec.ServiceAnnotations),
 			},
 			Spec: corev1.ServiceSpec{
-				Selector: map[string]string{
-					"app": spec.Name,
-				},
+				Selector: selectorLabels(spec),
+				// Headless Service 不分配虚拟 IP，<name>.<namespace>.svc.cluster.local 直接返回所有就绪 Pod 的 IP
+				ClusterIP: headlessClusterIP(spec.Headless),
 				Ports: []corev1.ServicePort{
 					{
 						Port:       spec.Port,
@@ -167,7 +349,7 @@ func (a *ClientGoAdapter) CreateApp(ctx context.Context, spec AppSpec) error {
 				},
 			},
 		}
-		_, err = Client.CoreV1().Services(spec.Namespace).Create(ctx, service, metav1.CreateOptions{})
+		_, err = a.client.CoreV1().Services(spec.Namespace).Create(ctx, service, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("创建 Service 失败: %w", err)
 		}
@@ -176,32 +358,185 @@ func (a *ClientGoAdapter) CreateApp(ctx context.Context, spec AppSpec) error {
 	return nil
 }
 
-// DeleteApp 删除应用
-func (a *ClientGoAdapter) DeleteApp(ctx context.Context, name, namespace string) error {
-	// 删除 Deployment
-	err := Client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
-	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("删除 Deployment 失败: %w", err)
+// headlessClusterIP Headless 时返回 None，否则由 K8s 自动分配
+func headlessClusterIP(headless bool) string {
+	if headless {
+		return corev1.ClusterIPNone
+	}
+	return ""
+}
+
+// buildPodTemplate 构建应用的 Pod 模板，Deployment 与 CronJob 共用
+func buildPodTemplate(spec AppSpec, labels map[string]string) corev1.PodTemplateSpec {
+	container := corev1.Container{
+		Name:                     spec.Name,
+		Image:                    spec.Image,
+		ImagePullPolicy:          corev1.PullPolicy(spec.ImagePullPolicy),
+		WorkingDir:               spec.WorkingDir,
+		Env:                      buildEnv(spec.Env),
+		TerminationMessagePolicy: corev1.TerminationMessagePolicy(spec.TerminationMessagePolicy),
+		TerminationMessagePath:   spec.TerminationMessagePath,
+	}
+
+	if spec.Security != nil {
+		container.SecurityContext = buildSecurityContext(spec.Security)
+	}
+
+	if len(spec.PreStopCommand) > 0 {
+		container.Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: spec.PreStopCommand},
+			},
+		}
+	}
+
+	// 如果指定了端口，添加端口配置
+	if spec.Port > 0 {
+		container.Ports = []corev1.ContainerPort{
+			{
+				ContainerPort: spec.Port,
+			},
+		}
+	}
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			Containers:                    []corev1.Container{container},
+			Hostname:                      spec.Hostname,
+			Subdomain:                     spec.Subdomain,
+			ServiceAccountName:            spec.ServiceAccountName,
+			DNSPolicy:                     corev1.DNSPolicy(spec.DNSPolicy),
+			DNSConfig:                     buildDNSConfig(spec),
+			TerminationGracePeriodSeconds: spec.TerminationGracePeriodSeconds,
+			TopologySpreadConstraints:     buildSpreadConstraints(spec),
+		},
+	}
+}
+
+// buildDNSConfig 构建 Pod 自定义 DNS 配置，未设置时返回 nil；选项按名称排序，保证模板稳定
+func buildDNSConfig(spec AppSpec) *corev1.PodDNSConfig {
+	if len(spec.DNSNameservers) == 0 && len(spec.DNSSearches) == 0 && len(spec.DNSOptions) == 0 {
+		return nil
+	}
+	config := &corev1.PodDNSConfig{
+		Nameservers: spec.DNSNameservers,
+		Searches:    spec.DNSSearches,
+	}
+	names := make([]string, 0, len(spec.DNSOptions))
+	for name := range spec.DNSOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		option := corev1.PodDNSConfigOption{Name: name}
+		if value := spec.DNSOptions[name]; value != "" {
+			option.Value = &value
+		}
+		config.Options = append(config.Options, option)
+	}
+	return config
+}
+
+// buildSpreadConstraints 将副本尽量均匀分布到不同节点或可用区
+// 使用 ScheduleAnyway 软约束，节点或可用区不足时仍可调度，不会因此卡在 Pending
+func buildSpreadConstraints(spec AppSpec) []corev1.TopologySpreadConstraint {
+	var topologyKey string
+	switch spec.Spread {
+	case SpreadNode:
+		topologyKey = corev1.LabelHostname
+	case SpreadZone:
+		topologyKey = corev1.LabelTopologyZone
+	default:
+		return nil
+	}
+	return []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: selectorLabels(spec)},
+	}}
+}
+
+// buildSecurityContext 将安全选项转换为容器 SecurityContext
+func buildSecurityContext(opts *SecurityOptions) *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
+		RunAsUser: opts.RunAsUser,
+	}
+	if opts.RunAsNonRoot {
+		securityContext.RunAsNonRoot = &opts.RunAsNonRoot
+	}
+	if opts.ReadOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = &opts.ReadOnlyRootFilesystem
+	}
+	if len(opts.DropCapabilities) > 0 {
+		drop := make([]corev1.Capability, 0, len(opts.DropCapabilities))
+		for _, capability := range opts.DropCapabilities {
+			drop = append(drop, corev1.Capability(capability))
+		}
+		securityContext.Capabilities = &corev1.Capabilities{Drop: drop}
+	}
+	return securityContext
+}
+
+// DeleteApp 删除应用，只删除 kind 对应的工作负载，kind 留空按 Deployment 处理
+func (a *ClientGoAdapter) DeleteApp(ctx context.Context, name, namespace, kind string) error {
+	if err := a.deleteWorkload(ctx, name, namespace, kind); err != nil {
+		return err
 	}
 
 	// 删除 Service（忽略不存在的错误）
-	err = Client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	err := a.client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return fmt.Errorf("删除 Service 失败: %w", err)
 	}
 
-	return nil
+	return a.deleteBuilds(ctx, name, namespace)
 }
 
-// ScaleApp 调整副本数
+// deleteWorkload 删除应用的工作负载，已不存在时忽略
+func (a *ClientGoAdapter) deleteWorkload(ctx context.Context, name, namespace, kind string) error {
+	switch kind {
+	case KindStatefulSet:
+		return a.deleteStatefulSet(ctx, name, namespace)
+	case KindCronJob:
+		// 删除 CronJob，同时清理其创建的 Job 和 Pod
+		propagation := metav1.DeletePropagationBackground
+		err := a.client.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("删除 CronJob 失败: %w", err)
+		}
+		return nil
+	default:
+		err := a.client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("删除 Deployment 失败: %w", err)
+		}
+		return nil
+	}
+}
+
+// ScaleApp 调整副本数，定时任务应用中 0 表示暂停调度，大于 0 表示恢复调度
 func (a *ClientGoAdapter) ScaleApp(ctx context.Context, name, namespace string, replicas int32) error {
-	deployment, err := Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			return a.scaleStatefulSet(ctx, statefulSet, replicas)
+		}
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+		}
+		return a.suspendCronJob(ctx, name, namespace, replicas)
+	}
 	if err != nil {
 		return fmt.Errorf("获取 Deployment 失败: %w", err)
 	}
 
 	deployment.Spec.Replicas = &replicas
-	_, err = Client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	_, err = a.client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("更新副本数失败: %w", err)
 	}
@@ -211,36 +546,24 @@ func (a *ClientGoAdapter) ScaleApp(ctx context.Context, name, namespace string,
 
 // GetAppStatus 获取应用状态
 func (a *ClientGoAdapter) GetAppStatus(ctx context.Context, name, namespace string) (*AppStatus, error) {
-	deployment, err := Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return &AppStatus{Status: "unknown"}, nil
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("获取 Deployment 失败: %w", err)
+		}
+		statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			return a.getStatefulSetStatus(ctx, statefulSet)
+		}
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("获取 StatefulSet 失败: %w", err)
 		}
-		return nil, fmt.Errorf("获取 Deployment 失败: %w", err)
+		return a.getCronJobStatus(ctx, name, namespace)
 	}
 
-	// 获取 Pod 列表
-	pods, err := Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("app=%s", name),
-	})
+	podInfos, restartCount, err := a.listPodInfos(ctx, labelSelectorForApp(name, deployment.Labels), namespace)
 	if err != nil {
-		return nil, fmt.Errorf("获取 Pod 列表失败: %w", err)
-	}
-
-	podInfos := make([]PodInfo, 0, len(pods.Items))
-	for _, pod := range pods.Items {
-		ready := false
-		for _, cond := range pod.Status.Conditions {
-			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
-				ready = true
-				break
-			}
-		}
-		podInfos = append(podInfos, PodInfo{
-			Name:   pod.Name,
-			Status: string(pod.Status.Phase),
-			Ready:  ready,
-		})
+		return nil, err
 	}
 
 	// 确定应用状态
@@ -250,16 +573,92 @@ func (a *ClientGoAdapter) GetAppStatus(ctx context.Context, name, namespace stri
 		Status:        status,
 		ReadyReplicas: deployment.Status.ReadyReplicas,
 		Replicas:      *deployment.Spec.Replicas,
+		RestartCount:  restartCount,
+		RolloutPaused: deployment.Spec.Paused,
 		Pods:          podInfos,
 	}, nil
 }
 
+// listPodInfos 按选择器获取应用的 Pod 信息列表及容器重启次数之和
+func (a *ClientGoAdapter) listPodInfos(ctx context.Context, selector, namespace string) ([]PodInfo, int32, error) {
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取 Pod 列表失败: %w", err)
+	}
+
+	podInfos := make([]PodInfo, 0, len(pods.Items))
+	var restartCount int32
+	for _, pod := range pods.Items {
+		podInfo := buildPodInfo(&pod)
+		restartCount += podInfo.RestartCount
+		podInfos = append(podInfos, podInfo)
+	}
+	return podInfos, restartCount, nil
+}
+
+// buildPodInfo 从 Pod 对象提取 Pod 及容器状态信息
+func buildPodInfo(pod *corev1.Pod) PodInfo {
+	ready := false
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			ready = true
+			break
+		}
+	}
+
+	info := PodInfo{
+		Name:              pod.Name,
+		Status:            string(pod.Status.Phase),
+		Ready:             ready,
+		ContainerStatuses: make([]ContainerStatus, 0, len(pod.Status.ContainerStatuses)),
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		containerStatus := ContainerStatus{
+			Name:         cs.Name,
+			Ready:        cs.Ready,
+			RestartCount: cs.RestartCount,
+			State:        "unknown",
+		}
+		switch {
+		case cs.State.Running != nil:
+			containerStatus.State = "running"
+		case cs.State.Waiting != nil:
+			containerStatus.State = "waiting"
+			containerStatus.Reason = cs.State.Waiting.Reason
+			containerStatus.Message = cs.State.Waiting.Message
+		case cs.State.Terminated != nil:
+			containerStatus.State = "terminated"
+			containerStatus.Reason = cs.State.Terminated.Reason
+			containerStatus.Message = cs.State.Terminated.Message
+			containerStatus.ExitCode = &cs.State.Terminated.ExitCode
+		}
+		if last := cs.LastTerminationState.Terminated; last != nil {
+			containerStatus.LastTermination = &ContainerTermination{
+				Reason:     last.Reason,
+				Message:    last.Message,
+				ExitCode:   last.ExitCode,
+				FinishedAt: last.FinishedAt.Time,
+			}
+		}
+		info.RestartCount += cs.RestartCount
+		info.ContainerStatuses = append(info.ContainerStatuses, containerStatus)
+	}
+	return info
+}
+
 // determineStatus 根据 Deployment 状态确定应用状态
 func (a *ClientGoAdapter) determineStatus(deployment *appsv1.Deployment) string {
 	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas == 0 {
 		return "stopped"
 	}
 
+	// 超过 progressDeadlineSeconds 仍未完成发布，说明更新已失败而非仅仅缓慢
+	if isRolloutFailed(deployment) {
+		return "rollout_failed"
+	}
+
 	if deployment.Status.ReadyReplicas == *deployment.Spec.Replicas {
 		return "running"
 	}
@@ -273,7 +672,14 @@ func (a *ClientGoAdapter) determineStatus(deployment *appsv1.Deployment) string
 
 // RestartApp 滚动重启应用
 func (a *ClientGoAdapter) RestartApp(ctx context.Context, name, namespace string) error {
-	deployment, err := Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+		}
+		return a.restartStatefulSet(ctx, statefulSet)
+	}
 	if err != nil {
 		return fmt.Errorf("获取 Deployment 失败: %w", err)
 	}
@@ -284,7 +690,7 @@ func (a *ClientGoAdapter) RestartApp(ctx context.Context, name, namespace string
 	}
 	deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
 
-	_, err = Client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	_, err = a.client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("重启 Deployment 失败: %w", err)
 	}
@@ -292,24 +698,70 @@ func (a *ClientGoAdapter) RestartApp(ctx context.Context, name, namespace string
 	return nil
 }
 
-// GetAppLogs 获取应用日志
-func (a *ClientGoAdapter) GetAppLogs(ctx context.Context, name, namespace string, lines int64) (string, error) {
-	// 获取应用的 Pod 列表
-	pods, err := Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("app=%s", name),
+// WaitForReady 等待应用所有副本就绪
+func (a *ClientGoAdapter) WaitForReady(ctx context.Context, name, namespace string, timeout time.Duration) (*AppStatus, error) {
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, fmt.Errorf("获取 StatefulSet 失败: %w", err)
+			}
+			return isStatefulSetReady(statefulSet), nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("获取 Deployment 失败: %w", err)
+		}
+		if isRolloutFailed(deployment) {
+			return false, fmt.Errorf("应用发布失败：超过发布期限仍未完成")
+		}
+		return isRolloutComplete(deployment), nil
 	})
+
+	// 无论是否超时都返回最新状态，便于调用方了解当前进度
+	status, statusErr := a.GetAppStatus(context.Background(), name, namespace)
 	if err != nil {
-		return "", fmt.Errorf("获取 Pod 列表失败: %w", err)
+		if wait.Interrupted(err) {
+			return status, fmt.Errorf("等待应用就绪超时（%s）", timeout)
+		}
+		return status, err
 	}
+	return status, statusErr
+}
 
-	if len(pods.Items) == 0 {
-		return "", fmt.Errorf("没有找到运行中的 Pod")
+// isRolloutComplete 判断 Deployment 是否完成滚动更新且所有副本就绪
+func isRolloutComplete(deployment *appsv1.Deployment) bool {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
 	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == desired &&
+		status.ReadyReplicas == desired &&
+		status.Replicas == desired
+}
+
+// maxLogBytes 单次读取日志的字节上限
+const maxLogBytes int64 = 10 << 20
 
+// GetAppLogs 获取应用日志
+func (a *ClientGoAdapter) GetAppLogs(ctx context.Context, name, namespace string, lines int64) (string, error) {
 	// 获取第一个 Pod 的日志
-	podName := pods.Items[0].Name
-	req := Client.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
-		TailLines: &lines,
+	pod, err := a.logPod(ctx, name, namespace, "")
+	if err != nil {
+		return "", err
+	}
+	return a.readPodLogsAcrossRestart(ctx, pod, lines, maxLogBytes)
+}
+
+// readPodLogs 读取单个 Pod 的日志末尾，LimitBytes 由 kubelet 截断，避免超长行撑爆服务端内存；
+// previous 为 true 时读取上一次运行（重启前）的容器日志
+func (a *ClientGoAdapter) readPodLogs(ctx context.Context, namespace, podName string, previous bool, lines, limitBytes int64) (string, error) {
+	req := a.client.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		TailLines:  &lines,
+		LimitBytes: &limitBytes,
+		Previous:   previous,
 	})
 
 	stream, err := req.Stream(ctx)
@@ -318,14 +770,12 @@ func (a *ClientGoAdapter) GetAppLogs(ctx context.Context, name, namespace string
 	}
 	defer stream.Close()
 
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, stream)
+	// 边读边写入，并再次限制读取上限，防止 API Server 未遵守 LimitBytes
+	var sb strings.Builder
+	_, err = io.Copy(&sb, io.LimitReader(stream, limitBytes))
 	if err != nil {
 		return "", fmt.Errorf("读取日志失败: %w", err)
 	}
 
-	return buf.String(), nil
+	return sb.String(), nil
 }
-
-// Adapter 全局适配器实例
-var Adapter AppAdapter = NewClientGoAdapter()
@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "astro-user-1"
+
+// newTestAdapter 使用 fake clientset 构建适配器，objects 为集群中预置的资源
+func newTestAdapter(objects ...runtime.Object) *ClientGoAdapter {
+	return NewClientGoAdapter(fake.NewSimpleClientset(objects...), nil)
+}
+
+// testDeployment 构造 app=<name> 的 Deployment，副本数与就绪数由参数指定
+func testDeployment(name string, replicas, ready int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"app": name}},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+	}
+}
+
+// testPod 构造属于应用的 Pod，containers 为各容器的状态
+func testPod(app, name string, containers ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"app": app}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: containers,
+		},
+	}
+}
+
+func TestGetAppStatusRestartCount(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	crashLoop := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	oomKilled := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+
+	tests := []struct {
+		name        string
+		pods        []*corev1.Pod
+		wantTotal   int32
+		wantPods    []int32
+		wantState   string // 第一个 Pod 第一个容器的状态
+		wantLastErr string // 第一个 Pod 第一个容器上一次终止的原因
+	}{
+		{
+			name:      "无重启",
+			pods:      []*corev1.Pod{testPod("api", "api-1", corev1.ContainerStatus{Name: "api", Ready: true, State: running})},
+			wantTotal: 0,
+			wantPods:  []int32{0},
+			wantState: "running",
+		},
+		{
+			name: "崩溃重启",
+			pods: []*corev1.Pod{
+				testPod("api", "api-1",
+					corev1.ContainerStatus{Name: "api", RestartCount: 5, State: crashLoop,
+						LastTerminationState: oomKilled},
+					corev1.ContainerStatus{Name: "sidecar", Ready: true, RestartCount: 1, State: running}),
+				testPod("api", "api-2", corev1.ContainerStatus{Name: "api", Ready: true, RestartCount: 2, State: running}),
+			},
+			wantTotal:   8,
+			wantPods:    []int32{6, 2},
+			wantState:   "waiting",
+			wantLastErr: "OOMKilled",
+		},
+		{
+			name: "不统计其他应用的 Pod",
+			pods: []*corev1.Pod{
+				testPod("api", "api-1", corev1.ContainerStatus{Name: "api", RestartCount: 1, State: running}),
+				testPod("web", "web-1", corev1.ContainerStatus{Name: "web", RestartCount: 9, State: running}),
+			},
+			wantTotal: 1,
+			wantPods:  []int32{1},
+			wantState: "running",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{testDeployment("api", 2, 2)}
+			for _, pod := range tt.pods {
+				objects = append(objects, pod)
+			}
+			status, err := newTestAdapter(objects...).GetAppStatus(context.Background(), "api", testNamespace)
+			if err != nil {
+				t.Fatalf("GetAppStatus() error = %v", err)
+			}
+			if status.RestartCount != tt.wantTotal {
+				t.Errorf("RestartCount = %d, want %d", status.RestartCount, tt.wantTotal)
+			}
+			if len(status.Pods) != len(tt.wantPods) {
+				t.Fatalf("Pod 数量 = %d, want %d", len(status.Pods), len(tt.wantPods))
+			}
+			for i, want := range tt.wantPods {
+				if status.Pods[i].RestartCount != want {
+					t.Errorf("Pods[%d].RestartCount = %d, want %d", i, status.Pods[i].RestartCount, want)
+				}
+			}
+			first := status.Pods[0].ContainerStatuses[0]
+			if first.State != tt.wantState {
+				t.Errorf("容器状态 = %q, want %q", first.State, tt.wantState)
+			}
+			if tt.wantLastErr != "" && (first.LastTermination == nil || first.LastTermination.Reason != tt.wantLastErr) {
+				t.Errorf("上一次终止 = %+v, want reason %q", first.LastTermination, tt.wantLastErr)
+			}
+		})
+	}
+}
+
+func TestEnsureNamespace(t *testing.T) {
+	namespace := func(phase corev1.NamespacePhase) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			Status:     corev1.NamespaceStatus{Phase: phase},
+		}
+	}
+	tests := []struct {
+		name     string
+		existing *corev1.Namespace
+		wantErr  error
+	}{
+		{"不存在时创建", nil, nil},
+		{"已存在", namespace(corev1.NamespaceActive), nil},
+		{"正在删除", namespace(corev1.NamespaceTerminating), ErrNamespaceTerminating},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tt.existing != nil {
+				objects = append(objects, tt.existing)
+			}
+			a := newTestAdapter(objects...)
+			err := a.EnsureNamespace(context.Background(), testNamespace)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnsureNamespace() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			ns, err := a.client.CoreV1().Namespaces().Get(context.Background(), testNamespace, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("命名空间不存在: %v", err)
+			}
+			if tt.existing == nil && ns.Labels[ManagedByLabel] != ManagedByValue {
+				t.Errorf("新建的命名空间缺少 %s 标签", ManagedByLabel)
+			}
+		})
+	}
+}
+
+func TestDeleteApp(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "db", Namespace: testNamespace, Labels: map[string]string{"app": "db", AppIDLabel: "7", ManagedByLabel: ManagedByValue},
+	}}
+	cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: testNamespace}}
+
+	tests := []struct {
+		name          string
+		app           string
+		kind          string
+		wantDeleted   []string // 被删除的工作负载资源类型
+		wantSelector  string   // 删除持久卷声明的选择器，为空表示不删除
+		wantRemaining []string // 仍应存在的其他应用工作负载
+	}{
+		{"Deployment", "api", KindDeployment, []string{"deployments"}, "", []string{"db", "job"}},
+		{"未记录类型按 Deployment", "api", "", []string{"deployments"}, "", []string{"db", "job"}},
+		{"StatefulSet 按 app-id 删除持久卷", "db", KindStatefulSet, []string{"statefulsets"}, "app=db,astro.io/app-id=7", []string{"api", "job"}},
+		{"StatefulSet 已不存在", "gone", KindStatefulSet, nil, "", []string{"api", "db", "job"}},
+		{"CronJob", "job", KindCronJob, []string{"cronjobs"}, "", []string{"api", "db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAdapter(testDeployment("api", 1, 1), statefulSet.DeepCopy(), cronJob.DeepCopy())
+			ctx := context.Background()
+			if err := a.DeleteApp(ctx, tt.app, testNamespace, tt.kind); err != nil {
+				t.Fatalf("DeleteApp() error = %v", err)
+			}
+
+			var deleted []string
+			selector := ""
+			for _, action := range a.client.(*fake.Clientset).Actions() {
+				switch action := action.(type) {
+				case k8stesting.DeleteActionImpl:
+					if resource := action.GetResource().Resource; resource != "services" {
+						deleted = append(deleted, resource)
+					}
+				case k8stesting.DeleteCollectionActionImpl:
+					if action.GetResource().Resource == "persistentvolumeclaims" {
+						selector = action.GetListRestrictions().Labels.String()
+					}
+				}
+			}
+			if strings.Join(deleted, ",") != strings.Join(tt.wantDeleted, ",") {
+				t.Errorf("删除的工作负载 = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if selector != tt.wantSelector {
+				t.Errorf("持久卷选择器 = %q, want %q", selector, tt.wantSelector)
+			}
+
+			for _, name := range tt.wantRemaining {
+				_, errDeploy := a.client.AppsV1().Deployments(testNamespace).Get(ctx, name, metav1.GetOptions{})
+				_, errSts := a.client.AppsV1().StatefulSets(testNamespace).Get(ctx, name, metav1.GetOptions{})
+				_, errCron := a.client.BatchV1().CronJobs(testNamespace).Get(ctx, name, metav1.GetOptions{})
+				if errDeploy != nil && errSts != nil && errCron != nil {
+					t.Errorf("应用 %s 的工作负载被误删", name)
+				}
+			}
+		})
+	}
+}
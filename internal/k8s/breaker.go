@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker 简单熔断器：连续失败达到阈值后在冷却期内拒绝调用，冷却结束后放行一次探测
+// 用于集群不可用时避免读接口持续发起注定失败的 K8s 请求
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	opens     int64
+}
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常放行
+	BreakerOpen     = "open"      // 冷却期内拒绝调用
+	BreakerHalfOpen = "half-open" // 冷却结束，等待探测结果
+)
+
+// BreakerStats 熔断器状态快照，供监控指标使用
+type BreakerStats struct {
+	State    string
+	Failures int   // 当前连续连接失败次数
+	Opens    int64 // 累计从关闭进入打开的次数，半开探测失败后重新打开不计入
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Allow 判断当前是否允许调用
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) {
+		return false
+	}
+	// 冷却结束进入半开状态，放行一次探测，探测失败将重新计时
+	b.openUntil = time.Now().Add(b.cooldown)
+	return true
+}
+
+// Open 判断熔断是否处于打开状态，不消耗半开探测机会
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && time.Now().Before(b.openUntil)
+}
+
+// Stats 返回熔断器当前状态，不消耗半开探测机会
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BreakerClosed
+	if b.failures >= b.threshold {
+		state = BreakerHalfOpen
+		if time.Now().Before(b.openUntil) {
+			state = BreakerOpen
+		}
+	}
+	return BreakerStats{State: state, Failures: b.failures, Opens: b.opens}
+}
+
+// Record 记录一次调用结果，成功时关闭熔断
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+	if b.failures == b.threshold {
+		b.opens++
+	}
+}
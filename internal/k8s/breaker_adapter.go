@@ -0,0 +1,244 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// BreakerAdapter 为 AppAdapter 加上熔断保护，集群不可达时快速返回 ErrCircuitOpen
+type BreakerAdapter struct {
+	next    AppAdapter
+	breaker *CircuitBreaker
+}
+
+// NewBreakerAdapter 创建带熔断的适配器
+func NewBreakerAdapter(next AppAdapter, breaker *CircuitBreaker) *BreakerAdapter {
+	return &BreakerAdapter{next: next, breaker: breaker}
+}
+
+// guard 熔断打开时拒绝调用，否则执行并记录结果；只有连接类错误计入失败。
+// 调用方自己的 ctx 已取消或到期时，失败与集群状态无关，既不计入失败也不重置计数
+func (a *BreakerAdapter) guard(ctx context.Context, fn func() error) error {
+	if !a.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	switch {
+	case err != nil && ctx.Err() != nil:
+	case isConnectivityError(err):
+		a.breaker.Record(err)
+	default:
+		a.breaker.Record(nil)
+	}
+	return err
+}
+
+// isConnectivityError 判断错误是否表示 API Server 不可达或过载，NotFound 等业务错误不影响熔断。
+// HTTP 客户端超时、连接或读取超时同样计入：API Server 无响应正是熔断要保护的场景；
+// 调用方主动取消（context.Canceled）不计入
+func isConnectivityError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsTooManyRequests(err)
+}
+
+// EnsureNamespace 确保命名空间存在，受熔断保护
+func (a *BreakerAdapter) EnsureNamespace(ctx context.Context, namespace string) error {
+	return a.guard(ctx, func() error { return a.next.EnsureNamespace(ctx, namespace) })
+}
+
+// CreateApp 创建应用，受熔断保护
+func (a *BreakerAdapter) CreateApp(ctx context.Context, spec AppSpec) error {
+	return a.guard(ctx, func() error { return a.next.CreateApp(ctx, spec) })
+}
+
+// DeleteApp 删除应用，受熔断保护
+func (a *BreakerAdapter) DeleteApp(ctx context.Context, name, namespace, kind string) error {
+	return a.guard(ctx, func() error { return a.next.DeleteApp(ctx, name, namespace, kind) })
+}
+
+// ScaleApp 调整副本数，受熔断保护
+func (a *BreakerAdapter) ScaleApp(ctx context.Context, name, namespace string, replicas int32) error {
+	return a.guard(ctx, func() error { return a.next.ScaleApp(ctx, name, namespace, replicas) })
+}
+
+// GetAppStatus 获取应用状态，受熔断保护
+func (a *BreakerAdapter) GetAppStatus(ctx context.Context, name, namespace string) (status *AppStatus, err error) {
+	err = a.guard(ctx, func() error {
+		status, err = a.next.GetAppStatus(ctx, name, namespace)
+		return err
+	})
+	return status, err
+}
+
+// RestartApp 滚动重启应用，受熔断保护
+func (a *BreakerAdapter) RestartApp(ctx context.Context, name, namespace string) error {
+	return a.guard(ctx, func() error { return a.next.RestartApp(ctx, name, namespace) })
+}
+
+// GetAppLogs 获取应用日志，受熔断保护
+func (a *BreakerAdapter) GetAppLogs(ctx context.Context, name, namespace string, lines int64) (logs string, err error) {
+	err = a.guard(ctx, func() error {
+		logs, err = a.next.GetAppLogs(ctx, name, namespace, lines)
+		return err
+	})
+	return logs, err
+}
+
+// GetAllPodLogs 获取应用所有 Pod 的日志，受熔断保护
+func (a *BreakerAdapter) GetAllPodLogs(ctx context.Context, name, namespace string, lines int64) (logs string, err error) {
+	err = a.guard(ctx, func() error {
+		logs, err = a.next.GetAllPodLogs(ctx, name, namespace, lines)
+		return err
+	})
+	return logs, err
+}
+
+// WaitForReady 等待应用所有副本就绪，受熔断保护
+func (a *BreakerAdapter) WaitForReady(ctx context.Context, name, namespace string, timeout time.Duration) (status *AppStatus, err error) {
+	err = a.guard(ctx, func() error {
+		status, err = a.next.WaitForReady(ctx, name, namespace, timeout)
+		return err
+	})
+	return status, err
+}
+
+// GetAppManifests 获取应用在集群中的资源清单（YAML），受熔断保护
+func (a *BreakerAdapter) GetAppManifests(ctx context.Context, name, namespace string) (manifests string, err error) {
+	err = a.guard(ctx, func() error {
+		manifests, err = a.next.GetAppManifests(ctx, name, namespace)
+		return err
+	})
+	return manifests, err
+}
+
+// FollowAppLogs 持续跟踪应用日志，受熔断保护；只保护建立日志流，之后的自动重连不计入熔断
+func (a *BreakerAdapter) FollowAppLogs(ctx context.Context, name, namespace string, lines int64) (ch <-chan string, err error) {
+	err = a.guard(ctx, func() error {
+		ch, err = a.next.FollowAppLogs(ctx, name, namespace, lines)
+		return err
+	})
+	return ch, err
+}
+
+// WatchApp 监听应用状态变化，受熔断保护
+func (a *BreakerAdapter) WatchApp(ctx context.Context, name, namespace string) (ch <-chan *AppStatus, err error) {
+	err = a.guard(ctx, func() error {
+		ch, err = a.next.WatchApp(ctx, name, namespace)
+		return err
+	})
+	return ch, err
+}
+
+// VerifyOwner 校验集群资源是否由 Astro 创建且属于指定用户，受熔断保护
+func (a *BreakerAdapter) VerifyOwner(ctx context.Context, name, namespace string, ownerID uint) error {
+	return a.guard(ctx, func() error { return a.next.VerifyOwner(ctx, name, namespace, ownerID) })
+}
+
+// ListRevisions 列出应用历史版本，受熔断保护
+func (a *BreakerAdapter) ListRevisions(ctx context.Context, name, namespace string) (revisions []Revision, err error) {
+	err = a.guard(ctx, func() error {
+		revisions, err = a.next.ListRevisions(ctx, name, namespace)
+		return err
+	})
+	return revisions, err
+}
+
+// RollbackApp 回滚应用到指定版本，受熔断保护
+func (a *BreakerAdapter) RollbackApp(ctx context.Context, name, namespace string, toRevision int64) error {
+	return a.guard(ctx, func() error { return a.next.RollbackApp(ctx, name, namespace, toRevision) })
+}
+
+// ListAppPods 列出应用的所有 Pod，受熔断保护
+func (a *BreakerAdapter) ListAppPods(ctx context.Context, name, namespace string) (pods []PodInfo, err error) {
+	err = a.guard(ctx, func() error {
+		pods, err = a.next.ListAppPods(ctx, name, namespace)
+		return err
+	})
+	return pods, err
+}
+
+// GetAppPod 获取应用的指定 Pod，受熔断保护
+func (a *BreakerAdapter) GetAppPod(ctx context.Context, name, namespace, podName string) (pod *PodDetail, err error) {
+	err = a.guard(ctx, func() error {
+		pod, err = a.next.GetAppPod(ctx, name, namespace, podName)
+		return err
+	})
+	return pod, err
+}
+
+// DeleteAppPod 删除应用的指定 Pod，受熔断保护
+func (a *BreakerAdapter) DeleteAppPod(ctx context.Context, name, namespace, podName string) error {
+	return a.guard(ctx, func() error { return a.next.DeleteAppPod(ctx, name, namespace, podName) })
+}
+
+// RecreateAppPods 同时删除应用的所有 Pod，受熔断保护
+func (a *BreakerAdapter) RecreateAppPods(ctx context.Context, name, namespace string) error {
+	return a.guard(ctx, func() error { return a.next.RecreateAppPods(ctx, name, namespace) })
+}
+
+// GetNamespaceUsage 汇总命名空间的资源 requests/limits 与实时用量，受熔断保护
+func (a *BreakerAdapter) GetNamespaceUsage(ctx context.Context, namespace string) (usage *NamespaceUsage, err error) {
+	err = a.guard(ctx, func() error {
+		usage, err = a.next.GetNamespaceUsage(ctx, namespace)
+		return err
+	})
+	return usage, err
+}
+
+// SetRolloutPaused 暂停或继续 Deployment 的滚动更新，受熔断保护
+func (a *BreakerAdapter) SetRolloutPaused(ctx context.Context, name, namespace string, paused bool) error {
+	return a.guard(ctx, func() error { return a.next.SetRolloutPaused(ctx, name, namespace, paused) })
+}
+
+// SetAppEnv 替换应用容器的全部环境变量，受熔断保护
+func (a *BreakerAdapter) SetAppEnv(ctx context.Context, name, namespace string, env []EnvVar) error {
+	return a.guard(ctx, func() error { return a.next.SetAppEnv(ctx, name, namespace, env) })
+}
+
+// UpdateAppContainer 一次性修改应用容器的镜像、拉取策略与环境变量，受熔断保护
+func (a *BreakerAdapter) UpdateAppContainer(ctx context.Context, name, namespace string, update ContainerUpdate) error {
+	return a.guard(ctx, func() error { return a.next.UpdateAppContainer(ctx, name, namespace, update) })
+}
+
+// RecreateApp 删除工作负载后按原配置重建，受熔断保护
+func (a *BreakerAdapter) RecreateApp(ctx context.Context, name, namespace string, update ContainerUpdate) error {
+	return a.guard(ctx, func() error { return a.next.RecreateApp(ctx, name, namespace, update) })
+}
+
+// StartBuild 创建源码构建 Job，受熔断保护
+func (a *BreakerAdapter) StartBuild(ctx context.Context, spec BuildSpec) (job string, err error) {
+	err = a.guard(ctx, func() error {
+		job, err = a.next.StartBuild(ctx, spec)
+		return err
+	})
+	return job, err
+}
+
+// GetBuildStatus 获取构建 Job 状态，受熔断保护
+func (a *BreakerAdapter) GetBuildStatus(ctx context.Context, job, namespace string) (status *BuildStatus, err error) {
+	err = a.guard(ctx, func() error {
+		status, err = a.next.GetBuildStatus(ctx, job, namespace)
+		return err
+	})
+	return status, err
+}
+
+// GetAppEvents 获取应用相关的 K8s 事件，受熔断保护
+func (a *BreakerAdapter) GetAppEvents(ctx context.Context, name, namespace string) (events []Event, err error) {
+	err = a.guard(ctx, func() error {
+		events, err = a.next.GetAppEvents(ctx, name, namespace)
+		return err
+	})
+	return events, err
+}
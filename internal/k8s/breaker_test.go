@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestCircuitBreakerStats(t *testing.T) {
+	connErr := errors.New("connection refused")
+	tests := []struct {
+		name         string
+		cooldown     time.Duration
+		results      []error
+		wantState    string
+		wantFailures int
+		wantOpens    int64
+	}{
+		{"无调用", time.Minute, nil, BreakerClosed, 0, 0},
+		{"失败未达阈值", time.Minute, []error{connErr, connErr}, BreakerClosed, 2, 0},
+		{"达到阈值打开", time.Minute, []error{connErr, connErr, connErr}, BreakerOpen, 3, 1},
+		{"冷却结束进入半开", 0, []error{connErr, connErr, connErr}, BreakerHalfOpen, 3, 1},
+		{"成功后关闭", time.Minute, []error{connErr, connErr, connErr, nil}, BreakerClosed, 0, 1},
+		{"再次打开累计次数", time.Minute, []error{connErr, connErr, connErr, nil, connErr, connErr, connErr, connErr}, BreakerOpen, 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(3, tt.cooldown)
+			for _, err := range tt.results {
+				b.Record(err)
+			}
+			stats := b.Stats()
+			if stats.State != tt.wantState || stats.Failures != tt.wantFailures || stats.Opens != tt.wantOpens {
+				t.Errorf("Stats() = %+v, want {%s %d %d}", stats, tt.wantState, tt.wantFailures, tt.wantOpens)
+			}
+			if b.Open() != (tt.wantState == BreakerOpen) {
+				t.Errorf("Open() = %v, 与状态 %s 不一致", b.Open(), tt.wantState)
+			}
+		})
+	}
+}
+
+func TestIsConnectivityError(t *testing.T) {
+	resource := schema.GroupResource{Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"无错误", nil, false},
+		{"业务错误", apierrors.NewNotFound(resource, "api"), false},
+		{"连接失败", &url.Error{Op: "Get", URL: "https://k8s", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{"服务不可用", apierrors.NewServiceUnavailable("维护中"), true},
+		{"请求过多", apierrors.NewTooManyRequests("限流", 1), true},
+		{"调用方取消", context.Canceled, false},
+		{"超时", context.DeadlineExceeded, true},
+		{"url.Error 包装的取消", &url.Error{Op: "Get", URL: "https://k8s", Err: context.Canceled}, false},
+		{"HTTP 客户端超时", fmt.Errorf("获取 Deployment 失败: %w", &url.Error{Op: "Get", URL: "https://k8s", Err: context.DeadlineExceeded}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectivityError(tt.err); got != tt.want {
+				t.Errorf("isConnectivityError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakerAdapterCallerContext(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		err          error
+		wantFailures int
+	}{
+		{"调用方取消不计入", canceled, &url.Error{Op: "Get", URL: "https://k8s", Err: context.Canceled}, 0},
+		{"调用方超时不计入", expired, &url.Error{Op: "Get", URL: "https://k8s", Err: context.DeadlineExceeded}, 0},
+		{"传输层超时计入", context.Background(), &url.Error{Op: "Get", URL: "https://k8s", Err: context.DeadlineExceeded}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(3, time.Minute)
+			a := NewBreakerAdapter(newTestAdapter(), breaker)
+			_ = a.guard(tt.ctx, func() error { return tt.err })
+			if stats := breaker.Stats(); stats.Failures != tt.wantFailures {
+				t.Errorf("连续失败次数 = %d, want %d", stats.Failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+// TestBreakerAdapterHungServer API Server 接受连接但从不响应时，客户端超时应计入失败并打开熔断
+func TestBreakerAdapterHungServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				<-done
+				conn.Close()
+			}()
+		}
+	}()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: "http://" + listener.Addr().String(), Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaker := NewCircuitBreaker(2, time.Minute)
+	a := NewBreakerAdapter(NewClientGoAdapter(client, nil), breaker)
+
+	for i := 0; i < 2; i++ {
+		if _, err := a.GetAppStatus(context.Background(), "api", testNamespace); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("第 %d 次调用 error = %v, want 超时错误", i+1, err)
+		}
+	}
+	if stats := breaker.Stats(); stats.State != BreakerOpen {
+		t.Fatalf("熔断状态 = %+v, want open", stats)
+	}
+	if _, err := a.GetAppStatus(context.Background(), "api", testNamespace); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("熔断打开后 error = %v, want ErrCircuitOpen", err)
+	}
+}
@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// BuildForLabel 构建 Job 所属应用，构建 Pod 不带 app 标签，避免混入应用状态
+const BuildForLabel = "astro.io/build-for"
+
+// 构建阶段
+const (
+	BuildPhaseRunning   = "running"
+	BuildPhaseSucceeded = "succeeded"
+	BuildPhaseFailed    = "failed"
+)
+
+// buildTTLSeconds 构建 Job 完成后保留一天，便于查看失败原因
+const buildTTLSeconds int32 = 24 * 3600
+
+// BuildSpec 源码构建规格，使用 Kaniko 从 Git 仓库构建镜像并推送到 Destination
+type BuildSpec struct {
+	Name         string // 应用名
+	Namespace    string
+	GitURL       string // https 仓库地址
+	Ref          string // 分支名或完整 ref（如 refs/tags/v1.0），留空为默认分支
+	Dockerfile   string // 相对仓库根目录的 Dockerfile 路径，留空为 Dockerfile
+	Destination  string // 推送的目标镜像
+	BuilderImage string
+	PushSecret   string        // dockerconfigjson 类型的 Secret，需存在于构建命名空间
+	Insecure     bool          // 目标仓库使用 HTTP
+	Timeout      time.Duration // 构建超时，0 表示不限制
+	OwnerID      uint
+	OwnerUUID    string
+}
+
+// BuildStatus 构建状态
+type BuildStatus struct {
+	Job         string     `json:"job"`
+	Phase       string     `json:"phase"`             // running/succeeded/failed
+	Message     string     `json:"message,omitempty"` // 失败时为构建日志末尾
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// StartBuild 创建构建 Job，返回 Job 名
+func (a *ClientGoAdapter) StartBuild(ctx context.Context, spec BuildSpec) (string, error) {
+	if err := a.EnsureNamespace(ctx, spec.Namespace); err != nil {
+		return "", fmt.Errorf("创建命名空间失败: %w", err)
+	}
+
+	labels := map[string]string{
+		BuildForLabel:  spec.Name,
+		ManagedByLabel: ManagedByValue,
+	}
+	if spec.OwnerID > 0 {
+		labels[OwnerIDLabel] = strconv.FormatUint(uint64(spec.OwnerID), 10)
+	}
+
+	dockerfile := spec.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	args := []string{
+		"--context=" + kanikoGitContext(spec.GitURL, spec.Ref),
+		"--dockerfile=" + dockerfile,
+		"--destination=" + spec.Destination,
+	}
+	if spec.Insecure {
+		args = append(args, "--insecure")
+	}
+
+	container := corev1.Container{
+		Name:  "build",
+		Image: spec.BuilderImage,
+		Args:  args,
+		// 失败时将日志末尾作为终止信息，查询构建状态即可看到失败原因
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers:    []corev1.Container{container},
+	}
+	if spec.PushSecret != "" {
+		podSpec.Volumes = []corev1.Volume{{
+			Name: "docker-config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: spec.PushSecret,
+					Items:      []corev1.KeyToPath{{Key: corev1.DockerConfigJsonKey, Path: "config.json"}},
+				},
+			},
+		}}
+		podSpec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "docker-config", MountPath: "/kaniko/.docker"}}
+	}
+
+	backoffLimit := int32(0)
+	ttl := buildTTLSeconds
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-build-%d", spec.Name, time.Now().Unix()),
+			Namespace:   spec.Namespace,
+			Labels:      labels,
+			Annotations: ownerAnnotations(AppSpec{OwnerID: spec.OwnerID, OwnerUUID: spec.OwnerUUID}, nil),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+	if spec.Timeout > 0 {
+		deadline := int64(spec.Timeout.Seconds())
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	created, err := a.client.BatchV1().Jobs(spec.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("创建构建 Job 失败: %w", err)
+	}
+	return created.Name, nil
+}
+
+// kanikoGitContext 将 https 仓库地址转换为 Kaniko 的 git 上下文，分支名补全为 refs/heads/<branch>
+func kanikoGitContext(gitURL, ref string) string {
+	gitContext := "git://" + strings.TrimPrefix(gitURL, "https://")
+	if ref == "" {
+		return gitContext
+	}
+	if !strings.HasPrefix(ref, "refs/") {
+		ref = "refs/heads/" + ref
+	}
+	return gitContext + "#" + ref
+}
+
+// GetBuildStatus 获取构建 Job 状态
+func (a *ClientGoAdapter) GetBuildStatus(ctx context.Context, job, namespace string) (*BuildStatus, error) {
+	j, err := a.client.BatchV1().Jobs(namespace).Get(ctx, job, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取构建 Job 失败: %w", err)
+	}
+
+	status := &BuildStatus{Job: j.Name, Phase: BuildPhaseRunning}
+	if j.Status.StartTime != nil {
+		status.StartedAt = &j.Status.StartTime.Time
+	}
+	for _, cond := range j.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.Phase = BuildPhaseSucceeded
+			status.CompletedAt = &cond.LastTransitionTime.Time
+		case batchv1.JobFailed:
+			status.Phase = BuildPhaseFailed
+			status.CompletedAt = &cond.LastTransitionTime.Time
+			status.Message = cond.Message
+		}
+	}
+	if status.Phase == BuildPhaseFailed {
+		if msg := a.buildFailureMessage(ctx, j.Name, namespace); msg != "" {
+			status.Message = msg
+		}
+	}
+	return status, nil
+}
+
+// buildFailureMessage 读取构建容器的终止信息，获取不到时返回空
+func (a *ClientGoAdapter) buildFailureMessage(ctx context.Context, job, namespace string) string {
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{"job-name": job}.AsSelector().String(),
+	})
+	if err != nil {
+		return ""
+	}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil && t.ExitCode != 0 && t.Message != "" {
+				return t.Message
+			}
+		}
+	}
+	return ""
+}
+
+// deleteBuilds 删除应用的所有构建 Job 及其 Pod
+func (a *ClientGoAdapter) deleteBuilds(ctx context.Context, name, namespace string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := a.client.BatchV1().Jobs(namespace).DeleteCollection(ctx, metav1.DeleteOptions{PropagationPolicy: &propagation}, metav1.ListOptions{
+		LabelSelector: managedSelector(BuildForLabel, name),
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("删除构建 Job 失败: %w", err)
+	}
+	return nil
+}
@@ -1,15 +1,16 @@
 package k8s
 
 import (
+	"context"
+	"fmt"
+
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-var Client *kubernetes.Clientset
-
-// Init 初始化 K8s 客户端
-func Init(kubeconfig string) error {
+// NewClient 创建 K8s 客户端
+func NewClient(kubeconfig string) (*kubernetes.Clientset, error) {
 	var config *rest.Config
 	var err error
 
@@ -20,9 +21,16 @@ func Init(kubeconfig string) error {
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	Client, err = kubernetes.NewForConfig(config)
-	return err
+	return kubernetes.NewForConfig(config)
+}
+
+// CheckConnectivity 请求 API Server 的 /version 确认集群可达且凭证有效
+func CheckConnectivity(ctx context.Context, client kubernetes.Interface) error {
+	if _, err := client.Discovery().RESTClient().Get().AbsPath("/version").DoRaw(ctx); err != nil {
+		return fmt.Errorf("K8s API 不可达: %w", err)
+	}
+	return nil
 }
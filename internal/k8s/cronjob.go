@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createCronJob 创建定时任务类型的应用
+func (a *ClientGoAdapter) createCronJob(ctx context.Context, spec AppSpec, labels map[string]string, template corev1.PodTemplateSpec) error {
+	// Job 的 Pod 不能使用 Always 重启策略，默认失败时原地重启容器
+	template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	if spec.RestartPolicy != "" {
+		template.Spec.RestartPolicy = corev1.RestartPolicy(spec.RestartPolicy)
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Labels:      labels,
+			Annotations: ownerAnnotations(spec, spec.DeploymentAnnotations),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          spec.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					Template:     template,
+					BackoffLimit: spec.BackoffLimit,
+				},
+			},
+		},
+	}
+
+	_, err := a.client.BatchV1().CronJobs(spec.Namespace).Create(ctx, cronJob, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("创建 CronJob 失败: %w", err)
+	}
+	return nil
+}
+
+// suspendCronJob 暂停或恢复定时任务调度，replicas 为 0 时暂停
+func (a *ClientGoAdapter) suspendCronJob(ctx context.Context, name, namespace string, replicas int32) error {
+	cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("获取 CronJob 失败: %w", err)
+	}
+
+	suspend := replicas == 0
+	cronJob.Spec.Suspend = &suspend
+	_, err = a.client.BatchV1().CronJobs(namespace).Update(ctx, cronJob, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("更新 CronJob 失败: %w", err)
+	}
+	return nil
+}
+
+// getCronJobStatus 获取定时任务应用状态，CronJob 不存在时返回 unknown
+func (a *ClientGoAdapter) getCronJobStatus(ctx context.Context, name, namespace string) (*AppStatus, error) {
+	cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &AppStatus{Status: "unknown"}, nil
+		}
+		return nil, fmt.Errorf("获取 CronJob 失败: %w", err)
+	}
+
+	podInfos, restartCount, err := a.listPodInfos(ctx, labelSelectorForApp(name, cronJob.Labels), namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var readyReplicas int32
+	for _, pod := range podInfos {
+		if pod.Ready {
+			readyReplicas++
+		}
+	}
+
+	// 定时任务没有固定副本数，Replicas 为 0 避免覆盖应用记录中的副本数
+	return &AppStatus{
+		Status:        determineCronJobStatus(cronJob),
+		ReadyReplicas: readyReplicas,
+		RestartCount:  restartCount,
+		Pods:          podInfos,
+	}, nil
+}
+
+// determineCronJobStatus 根据 CronJob 状态确定应用状态
+// 暂停为 stopped，有正在运行的 Job 为 running，否则为等待调度的 scheduled
+func determineCronJobStatus(cronJob *batchv1.CronJob) string {
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		return "stopped"
+	}
+	if len(cronJob.Status.Active) > 0 {
+		return "running"
+	}
+	return "scheduled"
+}
@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnvVar 容器环境变量
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// ContainerUpdate 应用容器的部分更新，为 nil 的字段保持不变
+type ContainerUpdate struct {
+	Image           *string
+	ImagePullPolicy *string
+	Env             *[]EnvVar // 非 nil 时整体替换环境变量
+}
+
+// SetAppEnv 替换应用容器的全部环境变量；Deployment 与 StatefulSet 因 Pod 模板变化触发滚动更新，
+// CronJob 从下一次调度开始生效
+func (a *ClientGoAdapter) SetAppEnv(ctx context.Context, name, namespace string, env []EnvVar) error {
+	return a.UpdateAppContainer(ctx, name, namespace, ContainerUpdate{Env: &env})
+}
+
+// UpdateAppContainer 在一次更新中修改应用容器的镜像、拉取策略与环境变量，避免多次触发滚动更新；
+// CronJob 从下一次调度开始生效
+func (a *ClientGoAdapter) UpdateAppContainer(ctx context.Context, name, namespace string, update ContainerUpdate) error {
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		applyContainerUpdate(&deployment.Spec.Template.Spec, name, update)
+		if _, err := a.client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("更新 Deployment 失败: %w", err)
+		}
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		applyContainerUpdate(&statefulSet.Spec.Template.Spec, name, update)
+		if _, err := a.client.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("更新 StatefulSet 失败: %w", err)
+		}
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+	}
+
+	cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("获取 CronJob 失败: %w", err)
+	}
+	applyContainerUpdate(&cronJob.Spec.JobTemplate.Spec.Template.Spec, name, update)
+	if _, err := a.client.BatchV1().CronJobs(namespace).Update(ctx, cronJob, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("更新 CronJob 失败: %w", err)
+	}
+	return nil
+}
+
+// applyContainerUpdate 修改与应用同名的容器，找不到时修改第一个容器
+func applyContainerUpdate(spec *corev1.PodSpec, name string, update ContainerUpdate) {
+	if len(spec.Containers) == 0 {
+		return
+	}
+	container := &spec.Containers[0]
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			container = &spec.Containers[i]
+			break
+		}
+	}
+
+	if update.Image != nil {
+		container.Image = *update.Image
+	}
+	if update.ImagePullPolicy != nil {
+		container.ImagePullPolicy = corev1.PullPolicy(*update.ImagePullPolicy)
+	}
+	if update.Env != nil {
+		container.Env = buildEnv(*update.Env)
+		if container.Env == nil {
+			container.Env = []corev1.EnvVar{}
+		}
+	}
+}
+
+// buildEnv 转换为容器环境变量，为空时返回 nil
+func buildEnv(env []EnvVar) []corev1.EnvVar {
+	if len(env) == 0 {
+		return nil
+	}
+	vars := make([]corev1.EnvVar, 0, len(env))
+	for _, e := range env {
+		vars = append(vars, corev1.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	return vars
+}
@@ -0,0 +1,15 @@
+package k8s
+
+import "errors"
+
+// ErrNamespaceTerminating 命名空间处于 Terminating 状态，无法在其中创建资源
+var ErrNamespaceTerminating = errors.New("命名空间正在删除中，请等待删除完成后重试")
+
+// ErrCircuitOpen K8s API 连续不可达，熔断期间直接拒绝调用
+var ErrCircuitOpen = errors.New("K8s API 暂不可用，请稍后重试")
+
+// ErrPodNotFound Pod 不存在或不属于指定应用
+var ErrPodNotFound = errors.New("Pod 不存在或不属于该应用")
+
+// ErrNamespaceNotManaged 命名空间不存在或不是 Astro 创建的
+var ErrNamespaceNotManaged = errors.New("命名空间不存在或不由 Astro 管理")
@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxAppEvents 单次返回的事件数上限
+const maxAppEvents = 50
+
+// Event 应用相关的 K8s 事件
+type Event struct {
+	Type     string    `json:"type"`   // Normal/Warning
+	Reason   string    `json:"reason"` // 如 BackOff、FailedScheduling
+	Message  string    `json:"message"`
+	Object   string    `json:"object"` // 事件关联的对象，如 Pod/my-app-7d9c-abcde
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// GetAppEvents 获取应用工作负载及其 ReplicaSet、Job、Pod 的事件，按最近发生时间倒序
+func (a *ClientGoAdapter) GetAppEvents(ctx context.Context, name, namespace string) ([]Event, error) {
+	// 应用创建的资源都带 app 标签，收集名称用于过滤事件
+	objects := map[string]bool{name: true}
+	podSelector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	selector := metav1.ListOptions{LabelSelector: podSelector}
+
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("获取 Pod 列表失败: %w", err)
+	}
+	for _, pod := range pods.Items {
+		objects[pod.Name] = true
+	}
+	replicaSets, err := a.client.AppsV1().ReplicaSets(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("获取 ReplicaSet 列表失败: %w", err)
+	}
+	for _, rs := range replicaSets.Items {
+		objects[rs.Name] = true
+	}
+	jobs, err := a.client.BatchV1().Jobs(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("获取 Job 列表失败: %w", err)
+	}
+	for _, job := range jobs.Items {
+		objects[job.Name] = true
+	}
+
+	list, err := a.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取事件失败: %w", err)
+	}
+
+	events := make([]Event, 0)
+	for _, e := range list.Items {
+		if !objects[e.InvolvedObject.Name] {
+			continue
+		}
+		events = append(events, Event{
+			Type:     e.Type,
+			Reason:   e.Reason,
+			Message:  e.Message,
+			Object:   e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
+			Count:    e.Count,
+			LastSeen: eventTime(&e),
+		})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+	if len(events) > maxAppEvents {
+		events = events[:maxAppEvents]
+	}
+	return events, nil
+}
+
+// eventTime 事件最近发生时间，新版事件 API 写入的事件可能只有 EventTime
+func eventTime(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
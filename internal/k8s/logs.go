@@ -0,0 +1,230 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxConcurrentLogStreams 聚合日志时同时读取的 Pod 数上限
+const maxConcurrentLogStreams = 5
+
+// GetAllPodLogs 并发读取应用所有 Pod 的日志，按 Pod 名依次拼接，每行加 [Pod 名] 前缀。
+// 不同 Pod 之间的行不保证时间顺序；行数和字节上限按 Pod 数均分，总量不超过单 Pod 查询的上限
+func (a *ClientGoAdapter) GetAllPodLogs(ctx context.Context, name, namespace string, lines int64) (string, error) {
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return "", fmt.Errorf("获取 Pod 列表失败: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return "", fmt.Errorf("没有找到运行中的 Pod")
+	}
+
+	count := int64(len(pods.Items))
+	linesPerPod := max(lines/count, 1)
+	bytesPerPod := max(maxLogBytes/count, 1)
+
+	// 单个 Pod 失败（如容器尚未启动）不影响其他 Pod，错误写入该 Pod 的输出
+	results := make([]string, len(pods.Items))
+	sem := make(chan struct{}, maxConcurrentLogStreams)
+	var wg sync.WaitGroup
+	for i, pod := range pods.Items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			logs, err := a.readPodLogsAcrossRestart(ctx, &pod, linesPerPod, bytesPerPod)
+			if err != nil {
+				results[i] = prefixLines(pod.Name, err.Error())
+				return
+			}
+			results[i] = prefixLines(pod.Name, logs)
+		}()
+	}
+	wg.Wait()
+
+	return strings.Join(results, ""), nil
+}
+
+// podRestartedMarker 重启前后两段日志之间的分隔行
+const podRestartedMarker = "--- pod restarted ---"
+
+// readPodLogsAcrossRestart 读取 Pod 日志，容器重启过时先读取重启前的日志，再以分隔行接上当前日志，
+// 便于在崩溃重启后仍能看到崩溃前的输出。分隔行计入行数，两段平分其余行数与字节上限，总量不超过 lines 与 limitBytes；
+// lines 不足 3 行时只读取当前日志。上一次的日志已被清理时只返回当前日志，其他读取失败返回错误
+func (a *ClientGoAdapter) readPodLogsAcrossRestart(ctx context.Context, pod *corev1.Pod, lines, limitBytes int64) (string, error) {
+	if podRestartCount(pod) == 0 || lines < 3 {
+		return a.readPodLogs(ctx, pod.Namespace, pod.Name, false, lines, limitBytes)
+	}
+
+	previousLines := (lines - 1) / 2
+	currentLines := lines - 1 - previousLines
+	half := max(limitBytes/2, 1)
+	previous, err := a.readPodLogs(ctx, pod.Namespace, pod.Name, true, previousLines, half)
+	// 上一次运行的容器已被清理时 kubelet 返回 400/404，此时只返回当前日志
+	if err != nil && (ctx.Err() != nil || !(apierrors.IsBadRequest(err) || apierrors.IsNotFound(err))) {
+		return "", fmt.Errorf("读取重启前日志失败: %w", err)
+	}
+	current, err := a.readPodLogs(ctx, pod.Namespace, pod.Name, false, currentLines, half)
+	if err != nil {
+		return "", err
+	}
+	if previous == "" {
+		return current, nil
+	}
+	if !strings.HasSuffix(previous, "\n") {
+		previous += "\n"
+	}
+	return previous + podRestartedMarker + "\n" + current, nil
+}
+
+// podRestartCount Pod 内所有容器重启次数之和
+func podRestartCount(pod *corev1.Pod) int32 {
+	var count int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		count += cs.RestartCount
+	}
+	return count
+}
+
+// prefixLines 为每行日志加 [Pod 名] 前缀，结果以换行结尾
+func prefixLines(podName, logs string) string {
+	logs = strings.TrimSuffix(logs, "\n")
+	if logs == "" {
+		return ""
+	}
+	prefix := "[" + podName + "] "
+	return prefix + strings.ReplaceAll(logs, "\n", "\n"+prefix) + "\n"
+}
+
+// maxLogStreamRetries 跟踪日志时连续重连失败的次数上限，超过后结束跟踪
+const maxLogStreamRetries = 5
+
+// maxLogLineBytes 跟踪日志时单行的字节上限，超长的行会使本次日志流中断并重连
+const maxLogLineBytes = 1 << 20
+
+// logStreamRetryInterval 日志流中断后重连的间隔，测试中可调小
+var logStreamRetryInterval = 2 * time.Second
+
+// FollowAppLogs 持续跟踪应用第一个 Pod 的日志，先推送末尾 lines 行，之后逐行推送新日志。
+// 日志流结束（容器重启、Pod 被替换或连接被服务端关闭）时自动重连：容器重启或 Pod 被替换时先推送分隔行，
+// 再从新容器的第一行开始推送；同一容器则从断开时刻继续。ctx 取消或连续重连失败超过上限时关闭返回的 channel
+func (a *ClientGoAdapter) FollowAppLogs(ctx context.Context, name, namespace string, lines int64) (<-chan string, error) {
+	pod, err := a.logPod(ctx, name, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	stream, err := a.openLogStream(ctx, pod, &corev1.PodLogOptions{Follow: true, TailLines: &lines})
+	if err != nil {
+		return nil, err
+	}
+
+	lineCh := make(chan string)
+	go func() {
+		defer close(lineCh)
+		for {
+			ok := sendLogLines(ctx, stream, lineCh)
+			stream.Close()
+			if !ok {
+				return
+			}
+			disconnectedAt := metav1.Now()
+
+			var next *corev1.Pod
+			var err error
+			for retries := 0; ; retries++ {
+				if retries == maxLogStreamRetries {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(logStreamRetryInterval):
+				}
+				next, err = a.logPod(ctx, name, namespace, pod.Name)
+				if err != nil {
+					continue
+				}
+				opts := &corev1.PodLogOptions{Follow: true}
+				restarted := next.UID != pod.UID || podRestartCount(next) != podRestartCount(pod)
+				if !restarted {
+					opts.SinceTime = &disconnectedAt
+				}
+				if stream, err = a.openLogStream(ctx, next, opts); err != nil {
+					continue
+				}
+				if restarted {
+					select {
+					case lineCh <- podRestartedMarker:
+					case <-ctx.Done():
+						stream.Close()
+						return
+					}
+				}
+				break
+			}
+			pod = next
+		}
+	}()
+	return lineCh, nil
+}
+
+// logPod 获取读取日志的 Pod：优先返回名为 preferred 的 Pod，不存在时返回第一个 Pod
+func (a *ClientGoAdapter) logPod(ctx context.Context, name, namespace, preferred string) (*corev1.Pod, error) {
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("获取 Pod 列表失败: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("没有找到运行中的 Pod")
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Name == preferred {
+			return &pods.Items[i], nil
+		}
+	}
+	return &pods.Items[0], nil
+}
+
+// openLogStream 打开 Pod 的日志流
+func (a *ClientGoAdapter) openLogStream(ctx context.Context, pod *corev1.Pod, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	stream, err := a.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取日志流失败: %w", err)
+	}
+	return stream, nil
+}
+
+// sendLogLines 逐行推送日志流直到流结束，ctx 取消时返回 false
+func sendLogLines(ctx context.Context, stream io.Reader, lineCh chan<- string) bool {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLogLineBytes)
+	for scanner.Scan() {
+		select {
+		case lineCh <- scanner.Text():
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return ctx.Err() == nil
+}
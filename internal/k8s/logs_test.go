@@ -0,0 +1,158 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetAppLogsAcrossRestart(t *testing.T) {
+	tests := []struct {
+		name         string
+		allPods      bool
+		restarts     int32
+		lines        int64
+		want         string
+		wantPrevious int64 // 重启前日志读取的行数，0 表示不读取
+		wantCurrent  int64
+	}{
+		{"未重启只读当前日志", false, 0, 100, "fake logs", 0, 100},
+		{"重启后拼接重启前日志", false, 2, 100, "fake logs\n" + podRestartedMarker + "\nfake logs", 49, 50},
+		{"分隔行计入行数", false, 1, 3, "fake logs\n" + podRestartedMarker + "\nfake logs", 1, 1},
+		{"行数不足时只读当前日志", false, 1, 2, "fake logs", 0, 2},
+		{"聚合日志同样拼接", true, 1, 100, "[api-1] fake logs\n[api-1] " + podRestartedMarker + "\n[api-1] fake logs\n", 49, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAdapter(
+				testDeployment("api", 1, 1),
+				testPod("api", "api-1", corev1.ContainerStatus{Name: "api", RestartCount: tt.restarts}),
+			)
+			getLogs := a.GetAppLogs
+			if tt.allPods {
+				getLogs = a.GetAllPodLogs
+			}
+			logs, err := getLogs(context.Background(), "api", testNamespace, tt.lines)
+			if err != nil {
+				t.Fatalf("获取日志失败: %v", err)
+			}
+			if logs != tt.want {
+				t.Errorf("日志 = %q, want %q", logs, tt.want)
+			}
+
+			var previous, current int64
+			for _, action := range a.client.(*fake.Clientset).Actions() {
+				generic, ok := action.(k8stesting.GenericActionImpl)
+				if !ok || generic.GetSubresource() != "log" {
+					continue
+				}
+				if opts := generic.Value.(*corev1.PodLogOptions); opts.Previous {
+					previous = *opts.TailLines
+				} else {
+					current = *opts.TailLines
+				}
+			}
+			if previous != tt.wantPrevious || current != tt.wantCurrent {
+				t.Errorf("读取行数 重启前 %d 当前 %d, want %d %d", previous, current, tt.wantPrevious, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestGetAppLogsCanceled(t *testing.T) {
+	a := newTestAdapter(
+		testDeployment("api", 1, 1),
+		testPod("api", "api-1", corev1.ContainerStatus{Name: "api", RestartCount: 1}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.GetAppLogs(ctx, "api", testNamespace, 100); err == nil || !strings.Contains(err.Error(), "重启前") {
+		t.Errorf("调用方取消时 error = %v, want 读取重启前日志失败", err)
+	}
+}
+
+func TestFollowAppLogs(t *testing.T) {
+	logStreamRetryInterval = time.Millisecond
+	defer func() { logStreamRetryInterval = 2 * time.Second }()
+
+	tests := []struct {
+		name    string
+		replace func(pod *corev1.Pod) // 重连时看到的 Pod 变化，nil 表示不变
+		want    []string
+	}{
+		{"同一容器断开后继续", nil, []string{"fake logs", "fake logs"}},
+		{"容器重启后推送分隔行", func(pod *corev1.Pod) { pod.Status.ContainerStatuses[0].RestartCount++ }, []string{"fake logs", podRestartedMarker, "fake logs"}},
+		{"Pod 被替换后推送分隔行", func(pod *corev1.Pod) { pod.UID = types.UID("new") }, []string{"fake logs", podRestartedMarker, "fake logs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod("api", "api-1", corev1.ContainerStatus{Name: "api"})
+			pod.UID = "old"
+			a := newTestAdapter(testDeployment("api", 1, 1), pod)
+			// 首次列出 Pod 后返回变化后的 Pod，模拟日志流结束时容器已重启或 Pod 已被替换
+			lists := 0
+			a.client.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+				lists++
+				if lists == 1 || tt.replace == nil {
+					return false, nil, nil
+				}
+				changed := pod.DeepCopy()
+				tt.replace(changed)
+				return true, &corev1.PodList{Items: []corev1.Pod{*changed}}, nil
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			lineCh, err := a.FollowAppLogs(ctx, "api", testNamespace, 10)
+			if err != nil {
+				t.Fatalf("跟踪日志失败: %v", err)
+			}
+			var got []string
+			for len(got) < len(tt.want) {
+				got = append(got, <-lineCh)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("日志行 = %q, want %q", got, tt.want)
+			}
+
+			cancel()
+			for range lineCh {
+			}
+		})
+	}
+}
+
+func TestFollowAppLogsRetryLimit(t *testing.T) {
+	logStreamRetryInterval = time.Millisecond
+	defer func() { logStreamRetryInterval = 2 * time.Second }()
+
+	a := newTestAdapter(testDeployment("api", 1, 1), testPod("api", "api-1"))
+	lineCh, err := a.FollowAppLogs(context.Background(), "api", testNamespace, 10)
+	if err != nil {
+		t.Fatalf("跟踪日志失败: %v", err)
+	}
+	<-lineCh
+	// Pod 删除后无法重连，超过重试上限时关闭 channel
+	if err := a.client.CoreV1().Pods(testNamespace).Delete(context.Background(), "api-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("删除 Pod 失败: %v", err)
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-lineCh:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("重连失败超过上限后未结束跟踪")
+		}
+	}
+}
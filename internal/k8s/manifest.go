@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// GetAppManifests 获取应用当前在集群中的资源清单（YAML，多文档以 --- 分隔）
+// 包含 Deployment/StatefulSet/CronJob/Service，已去除 managedFields 和 status，不存在的资源会被跳过
+func (a *ClientGoAdapter) GetAppManifests(ctx context.Context, name, namespace string) (string, error) {
+	var objects []runtime.Object
+
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		deployment.ManagedFields = nil
+		deployment.Status.Reset()
+		deployment.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+		objects = append(objects, deployment)
+	} else if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		statefulSet.ManagedFields = nil
+		statefulSet.Status = appsv1.StatefulSetStatus{}
+		statefulSet.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"})
+		objects = append(objects, statefulSet)
+	} else if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 StatefulSet 失败: %w", err)
+	}
+
+	cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		cronJob.ManagedFields = nil
+		cronJob.Status.Reset()
+		cronJob.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"})
+		objects = append(objects, cronJob)
+	} else if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 CronJob 失败: %w", err)
+	}
+
+	service, err := a.client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		service.ManagedFields = nil
+		service.Status.Reset()
+		service.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Service"})
+		objects = append(objects, service)
+	} else if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 Service 失败: %w", err)
+	}
+
+	serializer := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	buf := new(bytes.Buffer)
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		if err := serializer.Encode(obj, buf); err != nil {
+			return "", fmt.Errorf("序列化资源清单失败: %w", err)
+		}
+	}
+
+	return buf.String(), nil
+}
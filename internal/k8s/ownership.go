@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 资源归属标记，创建时写入 Deployment/CronJob/Service
+const (
+	ManagedByLabel      = "managed-by"
+	ManagedByValue      = "astro"
+	OwnerIDLabel        = "astro.io/owner-id"
+	OwnerIDAnnotation   = "astro.io/owner-id"
+	OwnerUUIDAnnotation = "astro.io/owner-uuid"
+)
+
+// ErrNotOwned 集群中的资源不由 Astro 管理或不属于当前用户
+var ErrNotOwned = errors.New("资源不由 Astro 管理或不属于当前用户")
+
+// ownerAnnotations 在原有注解基础上追加归属注解，不修改入参
+func ownerAnnotations(spec AppSpec, base map[string]string) map[string]string {
+	annotations := make(map[string]string, len(base)+2)
+	for k, v := range base {
+		annotations[k] = v
+	}
+	if spec.OwnerID > 0 {
+		annotations[OwnerIDAnnotation] = strconv.FormatUint(uint64(spec.OwnerID), 10)
+	}
+	if spec.OwnerUUID != "" {
+		annotations[OwnerUUIDAnnotation] = spec.OwnerUUID
+	}
+	return annotations
+}
+
+// VerifyOwner 校验应用工作负载（Deployment/StatefulSet/CronJob）是否由 Astro 创建且属于指定用户
+// 资源不存在时视为通过；早期创建的资源没有归属注解，仅校验 managed-by 标签
+func (a *ClientGoAdapter) VerifyOwner(ctx context.Context, name, namespace string, ownerID uint) error {
+	var meta *metav1.ObjectMeta
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		meta = &deployment.ObjectMeta
+	case apierrors.IsNotFound(err):
+		statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			meta = &statefulSet.ObjectMeta
+			break
+		}
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+		}
+		cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("获取 CronJob 失败: %w", err)
+		}
+		meta = &cronJob.ObjectMeta
+	default:
+		return fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	if meta.Labels[ManagedByLabel] != ManagedByValue {
+		return ErrNotOwned
+	}
+	if owner, ok := meta.Annotations[OwnerIDAnnotation]; ok && owner != strconv.FormatUint(uint64(ownerID), 10) {
+		return ErrNotOwned
+	}
+	return nil
+}
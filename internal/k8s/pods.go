@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PodDetail 单个 Pod 的详细信息
+type PodDetail struct {
+	PodInfo
+	NodeName  string     `json:"node_name"`
+	PodIP     string     `json:"pod_ip"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+}
+
+// ListAppPods 列出应用的所有 Pod
+func (a *ClientGoAdapter) ListAppPods(ctx context.Context, name, namespace string) ([]PodInfo, error) {
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	podInfos, _, err := a.listPodInfos(ctx, selector, namespace)
+	return podInfos, err
+}
+
+// GetAppPod 获取应用的指定 Pod，Pod 不匹配应用的选择器时返回 ErrPodNotFound
+func (a *ClientGoAdapter) GetAppPod(ctx context.Context, name, namespace, podName string) (*PodDetail, error) {
+	pod, err := a.getAppPod(ctx, name, namespace, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &PodDetail{
+		PodInfo:  buildPodInfo(pod),
+		NodeName: pod.Spec.NodeName,
+		PodIP:    pod.Status.PodIP,
+	}
+	if pod.Status.StartTime != nil {
+		detail.StartTime = &pod.Status.StartTime.Time
+	}
+	return detail, nil
+}
+
+// DeleteAppPod 删除应用的指定 Pod，由工作负载控制器重新创建，Pod 不匹配应用的选择器时返回 ErrPodNotFound
+func (a *ClientGoAdapter) DeleteAppPod(ctx context.Context, name, namespace, podName string) error {
+	pod, err := a.getAppPod(ctx, name, namespace, podName)
+	if err != nil {
+		return err
+	}
+
+	// 带 UID 前置条件，避免删除期间同名 Pod 被重建后误删新 Pod
+	err = a.client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &pod.UID},
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("删除 Pod 失败: %w", err)
+	}
+	return nil
+}
+
+// RecreateAppPods 按应用选择器一次删除所有 Pod，新 Pod 就绪前应用不可用
+func (a *ClientGoAdapter) RecreateAppPods(ctx context.Context, name, namespace string) error {
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+	err = a.client.CoreV1().Pods(namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return fmt.Errorf("删除 Pod 失败: %w", err)
+	}
+	return nil
+}
+
+// getAppPod 获取 Pod 并确认其属于应用
+func (a *ClientGoAdapter) getAppPod(ctx context.Context, name, namespace, podName string) (*corev1.Pod, error) {
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Pod 选择器失败: %w", err)
+	}
+
+	pod, err := a.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, ErrPodNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取 Pod 失败: %w", err)
+	}
+	if !parsed.Matches(labels.Set(pod.Labels)) {
+		return nil, ErrPodNotFound
+	}
+	return pod, nil
+}
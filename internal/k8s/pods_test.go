@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRecreateAppPods(t *testing.T) {
+	withID := testDeployment("api", 2, 2)
+	withID.Labels[AppIDLabel] = "42"
+
+	tests := []struct {
+		name      string
+		app       string
+		wantMatch []labels.Set
+		wantSkip  []labels.Set
+	}{
+		{
+			name:      "按应用 ID 选择 Pod",
+			app:       "api",
+			wantMatch: []labels.Set{{"app": "api", AppIDLabel: "42"}},
+			wantSkip:  []labels.Set{{"app": "api", AppIDLabel: "43"}, {"app": "web", AppIDLabel: "42"}},
+		},
+		{
+			name:      "早期创建的应用按名称选择",
+			app:       "web",
+			wantMatch: []labels.Set{{"app": "web"}},
+			wantSkip:  []labels.Set{{"app": "api"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAdapter(withID, testDeployment("web", 1, 1))
+			if err := a.RecreateAppPods(context.Background(), tt.app, testNamespace); err != nil {
+				t.Fatalf("RecreateAppPods() error = %v", err)
+			}
+
+			// fake clientset 不执行 DeleteCollection，这里校验发出的删除请求及其选择器
+			var selector labels.Selector
+			for _, action := range a.client.(*fake.Clientset).Actions() {
+				if dc, ok := action.(k8stesting.DeleteCollectionAction); ok && dc.GetResource().Resource == "pods" {
+					if dc.GetNamespace() != testNamespace {
+						t.Errorf("命名空间 = %q, want %q", dc.GetNamespace(), testNamespace)
+					}
+					selector = dc.GetListRestrictions().Labels
+				}
+			}
+			if selector == nil {
+				t.Fatal("没有发出删除 Pod 的请求")
+			}
+			for _, set := range tt.wantMatch {
+				if !selector.Matches(set) {
+					t.Errorf("选择器 %q 应匹配 %v", selector, set)
+				}
+			}
+			for _, set := range tt.wantSkip {
+				if selector.Matches(set) {
+					t.Errorf("选择器 %q 不应匹配 %v", selector, set)
+				}
+			}
+		})
+	}
+}
+
+func TestRestartApp(t *testing.T) {
+	a := newTestAdapter(testDeployment("api", 2, 2))
+	ctx := context.Background()
+	if err := a.RestartApp(ctx, "api", testNamespace); err != nil {
+		t.Fatalf("RestartApp() error = %v", err)
+	}
+	deployment, err := a.client.AppsV1().Deployments(testNamespace).Get(ctx, "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] == "" {
+		t.Error("滚动重启应更新 Pod 模板的 restartedAt 注解")
+	}
+}
@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ErrRecreateNotSupported 定时任务应用每次调度都会创建新的 Pod，无需重建
+var ErrRecreateNotSupported = errors.New("定时任务应用不支持重建")
+
+// 重建时轮询旧资源是否删除完成的间隔与最长等待时间，测试中可调小
+var (
+	recreatePollInterval = 2 * time.Second
+	recreateWaitTimeout  = 5 * time.Minute
+)
+
+// RecreateApp 删除应用的工作负载后按原配置重新创建，update 在重建前应用到容器上。
+// 旧 Pod 全部删除后才会创建新 Pod，期间应用不可用；StatefulSet 同时删除持久卷声明，数据不可恢复
+func (a *ClientGoAdapter) RecreateApp(ctx context.Context, name, namespace string, update ContainerUpdate) error {
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return a.recreateDeployment(ctx, deployment, update)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return a.recreateStatefulSet(ctx, statefulSet, update)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+	}
+	return ErrRecreateNotSupported
+}
+
+// recreateDeployment 前台删除 Deployment，等待其 Pod 全部退出后以相同配置重新创建
+func (a *ClientGoAdapter) recreateDeployment(ctx context.Context, deployment *appsv1.Deployment, update ContainerUpdate) error {
+	applyContainerUpdate(&deployment.Spec.Template.Spec, deployment.Name, update)
+	recreated := &appsv1.Deployment{
+		ObjectMeta: recreatedMeta(deployment.ObjectMeta),
+		Spec:       deployment.Spec,
+	}
+	// 版本号由控制器重新从 1 开始记录
+	delete(recreated.Annotations, revisionAnnotation)
+
+	deployments := a.client.AppsV1().Deployments(deployment.Namespace)
+	propagation := metav1.DeletePropagationForeground
+	if err := deployments.Delete(ctx, deployment.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("删除 Deployment 失败: %w", err)
+	}
+	err := a.waitDeleted(ctx, "Deployment", func(ctx context.Context) (int, error) {
+		_, err := deployments.Get(ctx, deployment.Name, metav1.GetOptions{})
+		return existing(err)
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.waitPodsDeleted(ctx, deployment.Namespace, labelSelectorForApp(deployment.Name, deployment.Labels)); err != nil {
+		return err
+	}
+	if _, err := deployments.Create(ctx, recreated, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("重建 Deployment 失败: %w", err)
+	}
+	return nil
+}
+
+// recreateStatefulSet 前台删除 StatefulSet，Pod 全部退出后删除持久卷声明，
+// 持久卷声明删除完成后以相同配置重新创建，新副本使用全新的持久卷
+func (a *ClientGoAdapter) recreateStatefulSet(ctx context.Context, statefulSet *appsv1.StatefulSet, update ContainerUpdate) error {
+	applyContainerUpdate(&statefulSet.Spec.Template.Spec, statefulSet.Name, update)
+	recreated := &appsv1.StatefulSet{
+		ObjectMeta: recreatedMeta(statefulSet.ObjectMeta),
+		Spec:       statefulSet.Spec,
+	}
+
+	statefulSets := a.client.AppsV1().StatefulSets(statefulSet.Namespace)
+	propagation := metav1.DeletePropagationForeground
+	if err := statefulSets.Delete(ctx, statefulSet.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("删除 StatefulSet 失败: %w", err)
+	}
+	err := a.waitDeleted(ctx, "StatefulSet", func(ctx context.Context) (int, error) {
+		_, err := statefulSets.Get(ctx, statefulSet.Name, metav1.GetOptions{})
+		return existing(err)
+	})
+	if err != nil {
+		return err
+	}
+	// 持久卷声明带有 StatefulSet 的选择器标签；仍被 Pod 挂载的声明不会被真正删除，须等 Pod 退出后再删
+	selector := labelSelectorForApp(statefulSet.Name, statefulSet.Labels)
+	if err := a.waitPodsDeleted(ctx, statefulSet.Namespace, selector); err != nil {
+		return err
+	}
+	claims := a.client.CoreV1().PersistentVolumeClaims(statefulSet.Namespace)
+	if err := claims.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector}); err != nil {
+		return fmt.Errorf("删除持久卷声明失败: %w", err)
+	}
+	err = a.waitDeleted(ctx, "持久卷声明", func(ctx context.Context) (int, error) {
+		list, err := claims.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return 0, err
+		}
+		return len(list.Items), nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := statefulSets.Create(ctx, recreated, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("重建 StatefulSet 失败: %w", err)
+	}
+	return nil
+}
+
+// waitPodsDeleted 等待选择器匹配的 Pod 全部删除
+func (a *ClientGoAdapter) waitPodsDeleted(ctx context.Context, namespace, selector string) error {
+	return a.waitDeleted(ctx, "Pod", func(ctx context.Context) (int, error) {
+		pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return 0, err
+		}
+		return len(pods.Items), nil
+	})
+}
+
+// waitDeleted 轮询 remaining 直到剩余资源数为 0，超时或查询失败时返回错误
+func (a *ClientGoAdapter) waitDeleted(ctx context.Context, what string, remaining func(ctx context.Context) (int, error)) error {
+	err := wait.PollUntilContextTimeout(ctx, recreatePollInterval, recreateWaitTimeout, true, func(ctx context.Context) (bool, error) {
+		n, err := remaining(ctx)
+		if err != nil {
+			return false, err
+		}
+		return n == 0, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if wait.Interrupted(err) {
+		return fmt.Errorf("等待旧%s删除超时（%s）", what, recreateWaitTimeout)
+	}
+	return fmt.Errorf("查询%s失败: %w", what, err)
+}
+
+// existing 将单个资源的 Get 结果转换为剩余数量，不存在时为 0
+func existing(err error) (int, error) {
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// recreatedMeta 保留名称、标签与注解，去掉由 API Server 维护的字段
+func recreatedMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRecreateApp(t *testing.T) {
+	deployment := testDeployment("api", 2, 2)
+	deployment.ResourceVersion = "7"
+	deployment.Labels[AppIDLabel] = "1"
+	deployment.Annotations = map[string]string{revisionAnnotation: "3", OwnerIDAnnotation: "1"}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "api", Image: "nginx:1.25"}}
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: testNamespace, Labels: map[string]string{"app": "db", AppIDLabel: "2"}},
+		Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "db", Image: "postgres:15"}},
+		}}},
+	}
+
+	image := "nginx:1.27"
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		app           string
+		update        ContainerUpdate
+		wantImage     string
+		wantClaims    bool // 是否删除持久卷声明
+		wantErr       error
+		wantDeleted   string // 被删除重建的资源类型
+		wantNoVersion bool
+	}{
+		{"Deployment 带镜像更新", []runtime.Object{deployment}, "api", ContainerUpdate{Image: &image}, image, false, nil, "deployments", true},
+		{"Deployment 无修改", []runtime.Object{deployment}, "api", ContainerUpdate{}, "nginx:1.25", false, nil, "deployments", true},
+		{"StatefulSet 删除持久卷", []runtime.Object{statefulSet}, "db", ContainerUpdate{}, "postgres:15", true, nil, "statefulsets", false},
+		{"定时任务或不存在", nil, "job", ContainerUpdate{}, "", false, ErrRecreateNotSupported, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, obj := range tt.objects {
+				objects = append(objects, obj.DeepCopyObject())
+			}
+			a := newTestAdapter(objects...)
+			ctx := context.Background()
+			err := a.RecreateApp(ctx, tt.app, testNamespace, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecreateApp() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var deleted, created, claims bool
+			for _, action := range a.client.(*fake.Clientset).Actions() {
+				switch action := action.(type) {
+				case k8stesting.DeleteActionImpl:
+					if action.GetResource().Resource == tt.wantDeleted {
+						deleted = true
+						if p := action.GetDeleteOptions().PropagationPolicy; p == nil || *p != metav1.DeletePropagationForeground {
+							t.Errorf("删除传播策略 = %v, want Foreground", p)
+						}
+					}
+				case k8stesting.CreateActionImpl:
+					created = created || (deleted && action.GetResource().Resource == tt.wantDeleted)
+				case k8stesting.DeleteCollectionActionImpl:
+					if action.GetResource().Resource == "persistentvolumeclaims" {
+						claims = true
+						if !action.GetListRestrictions().Labels.Matches(labels.Set(statefulSet.Labels)) {
+							t.Errorf("持久卷选择器 %q 未匹配应用标签", action.GetListRestrictions().Labels)
+						}
+					}
+				}
+			}
+			if !deleted || !created {
+				t.Errorf("应先删除再创建 %s，deleted=%v created=%v", tt.wantDeleted, deleted, created)
+			}
+			if claims != tt.wantClaims {
+				t.Errorf("删除持久卷声明 = %v, want %v", claims, tt.wantClaims)
+			}
+
+			var spec corev1.PodSpec
+			var meta metav1.ObjectMeta
+			if tt.wantDeleted == "deployments" {
+				got, err := a.client.AppsV1().Deployments(testNamespace).Get(ctx, tt.app, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("重建后的 Deployment 不存在: %v", err)
+				}
+				spec, meta = got.Spec.Template.Spec, got.ObjectMeta
+			} else {
+				got, err := a.client.AppsV1().StatefulSets(testNamespace).Get(ctx, tt.app, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("重建后的 StatefulSet 不存在: %v", err)
+				}
+				spec, meta = got.Spec.Template.Spec, got.ObjectMeta
+			}
+			if spec.Containers[0].Image != tt.wantImage {
+				t.Errorf("镜像 = %q, want %q", spec.Containers[0].Image, tt.wantImage)
+			}
+			if meta.Labels[AppIDLabel] == "" {
+				t.Error("重建后应保留应用 ID 标签")
+			}
+			if tt.wantNoVersion && meta.Annotations[revisionAnnotation] != "" {
+				t.Error("重建后不应保留旧的版本号注解")
+			}
+			if tt.wantNoVersion && meta.Annotations[OwnerIDAnnotation] == "" {
+				t.Error("重建后应保留归属注解")
+			}
+		})
+	}
+}
+
+func TestRecreateAppWaitsForDeletion(t *testing.T) {
+	recreatePollInterval, recreateWaitTimeout = 10*time.Millisecond, 50*time.Millisecond
+	defer func() { recreatePollInterval, recreateWaitTimeout = 2*time.Second, 5*time.Minute }()
+
+	deployment := testDeployment("api", 1, 1)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: testNamespace, Labels: map[string]string{"app": "db"}},
+	}
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: testNamespace, Labels: map[string]string{"app": "db"}},
+	}
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		app            string
+		wantResource   string
+		wantClaimsGone bool // 是否已发起删除持久卷声明
+	}{
+		{"Deployment 旧 Pod 未退出", []runtime.Object{deployment, testPod("api", "api-0")}, "api", "deployments", false},
+		{"StatefulSet 旧 Pod 未退出时不删除持久卷", []runtime.Object{statefulSet, testPod("db", "db-0")}, "db", "statefulsets", false},
+		{"StatefulSet 持久卷声明未删除", []runtime.Object{statefulSet, claim}, "db", "statefulsets", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, obj := range tt.objects {
+				objects = append(objects, obj.DeepCopyObject())
+			}
+			a := newTestAdapter(objects...)
+			err := a.RecreateApp(context.Background(), tt.app, testNamespace, ContainerUpdate{})
+			if err == nil {
+				t.Fatal("旧资源未删除完成时应返回错误")
+			}
+
+			var claims bool
+			for _, action := range a.client.(*fake.Clientset).Actions() {
+				switch action := action.(type) {
+				case k8stesting.CreateActionImpl:
+					if action.GetResource().Resource == tt.wantResource {
+						t.Errorf("旧资源未删除完成时不应重建 %s", tt.wantResource)
+					}
+				case k8stesting.DeleteCollectionActionImpl:
+					claims = claims || action.GetResource().Resource == "persistentvolumeclaims"
+				}
+			}
+			if claims != tt.wantClaimsGone {
+				t.Errorf("删除持久卷声明 = %v, want %v", claims, tt.wantClaimsGone)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+	// progressDeadlineExceededReason Deployment Progressing 条件超时的原因，与 deployment controller 保持一致
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// ErrRevisionNotFound 指定的历史版本不存在
+var ErrRevisionNotFound = errors.New("历史版本不存在")
+
+// Revision 应用历史版本
+type Revision struct {
+	Revision  int64     `json:"revision"`
+	Image     string    `json:"image"`
+	CreatedAt time.Time `json:"created_at"`
+	Current   bool      `json:"current"`
+}
+
+// ListRevisions 列出应用 Deployment 的历史版本（按版本号倒序）
+func (a *ClientGoAdapter) ListRevisions(ctx context.Context, name, namespace string) ([]Revision, error) {
+	deployment, replicaSets, err := a.getRevisionHistory(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	current := deployment.Annotations[revisionAnnotation]
+	revisions := make([]Revision, 0, len(replicaSets))
+	for _, rs := range replicaSets {
+		revision := Revision{
+			Revision:  replicaSetRevision(&rs),
+			CreatedAt: rs.CreationTimestamp.Time,
+			Current:   rs.Annotations[revisionAnnotation] == current,
+		}
+		if len(rs.Spec.Template.Spec.Containers) > 0 {
+			revision.Image = rs.Spec.Template.Spec.Containers[0].Image
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// RollbackApp 将应用回滚到指定版本，toRevision 为 0 时回滚到上一个版本（同 kubectl rollout undo）
+func (a *ClientGoAdapter) RollbackApp(ctx context.Context, name, namespace string, toRevision int64) error {
+	deployment, replicaSets, err := a.getRevisionHistory(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	current := replicaSetRevisionFromString(deployment.Annotations[revisionAnnotation])
+	var target *appsv1.ReplicaSet
+	for i := range replicaSets {
+		revision := replicaSetRevision(&replicaSets[i])
+		if toRevision == 0 && revision < current || revision == toRevision {
+			target = &replicaSets[i]
+			break
+		}
+	}
+	if target == nil || replicaSetRevision(target) == current {
+		return ErrRevisionNotFound
+	}
+
+	// 使用历史 ReplicaSet 的 Pod 模板，去掉控制器生成的 pod-template-hash 标签
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deployment.Spec.Template = *template
+
+	_, err = a.client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("回滚 Deployment 失败: %w", err)
+	}
+	return nil
+}
+
+// SetRolloutPaused 暂停或继续 Deployment 的滚动更新（同 kubectl rollout pause/resume），
+// 暂停期间对 Pod 模板的修改不会触发新的发布，已创建的新旧 Pod 保持不变
+func (a *ClientGoAdapter) SetRolloutPaused(ctx context.Context, name, namespace string, paused bool) error {
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+	if deployment.Spec.Paused == paused {
+		return nil
+	}
+
+	deployment.Spec.Paused = paused
+	_, err = a.client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("更新 Deployment 发布状态失败: %w", err)
+	}
+	return nil
+}
+
+// isRolloutFailed 判断 Deployment 是否因超过 progressDeadlineSeconds 而发布失败
+func isRolloutFailed(deployment *appsv1.Deployment) bool {
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing {
+			return cond.Status == corev1.ConditionFalse && cond.Reason == progressDeadlineExceededReason
+		}
+	}
+	return false
+}
+
+// getRevisionHistory 获取 Deployment 及其所属的 ReplicaSet（按版本号倒序）
+func (a *ClientGoAdapter) getRevisionHistory(ctx context.Context, name, namespace string) (*appsv1.Deployment, []appsv1.ReplicaSet, error) {
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	list, err := a.client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelectorForApp(name, deployment.Labels),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取 ReplicaSet 列表失败: %w", err)
+	}
+
+	replicaSets := make([]appsv1.ReplicaSet, 0, len(list.Items))
+	for _, rs := range list.Items {
+		if metav1.IsControlledBy(&rs, deployment) {
+			replicaSets = append(replicaSets, rs)
+		}
+	}
+	sort.Slice(replicaSets, func(i, j int) bool {
+		return replicaSetRevision(&replicaSets[i]) > replicaSetRevision(&replicaSets[j])
+	})
+	return deployment, replicaSets, nil
+}
+
+// replicaSetRevision 读取 ReplicaSet 的版本号
+func replicaSetRevision(rs *appsv1.ReplicaSet) int64 {
+	return replicaSetRevisionFromString(rs.Annotations[revisionAnnotation])
+}
+
+// replicaSetRevisionFromString 解析版本号注解，无效时返回 0
+func replicaSetRevisionFromString(value string) int64 {
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDetermineStatus(t *testing.T) {
+	progressing := func(status corev1.ConditionStatus, reason string) appsv1.DeploymentCondition {
+		return appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: status, Reason: reason}
+	}
+	tests := []struct {
+		name       string
+		replicas   int32
+		ready      int32
+		conditions []appsv1.DeploymentCondition
+		want       string
+	}{
+		{"已停止", 0, 0, nil, "stopped"},
+		{"全部就绪", 2, 2, nil, "running"},
+		{"尚无就绪副本", 2, 0, nil, "pending"},
+		{"部分就绪", 2, 1, nil, "starting"},
+		{"发布进行中", 2, 1, []appsv1.DeploymentCondition{progressing(corev1.ConditionTrue, "ReplicaSetUpdated")}, "starting"},
+		{"发布超时", 2, 1, []appsv1.DeploymentCondition{progressing(corev1.ConditionFalse, progressDeadlineExceededReason)}, "rollout_failed"},
+		{"发布超时且旧副本仍就绪", 2, 2, []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			progressing(corev1.ConditionFalse, progressDeadlineExceededReason),
+		}, "rollout_failed"},
+		{"已停止时忽略发布超时", 0, 0, []appsv1.DeploymentCondition{progressing(corev1.ConditionFalse, progressDeadlineExceededReason)}, "stopped"},
+	}
+	a := newTestAdapter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := testDeployment("api", tt.replicas, tt.ready)
+			deployment.Status.Conditions = tt.conditions
+			if got := a.determineStatus(deployment); got != tt.want {
+				t.Errorf("determineStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AppIDLabel 平台应用 ID，与 app 标签一起组成全局唯一的选择器，避免同名应用的 Pod 互相混入
+const AppIDLabel = "astro.io/app-id"
+
+// selectorLabels 工作负载和 Service 的选择器标签，未指定 AppID 时只使用 app 标签
+func selectorLabels(spec AppSpec) map[string]string {
+	labels := map[string]string{"app": spec.Name}
+	if spec.AppID > 0 {
+		labels[AppIDLabel] = strconv.FormatUint(uint64(spec.AppID), 10)
+	}
+	return labels
+}
+
+// labelSelectorForApp 根据工作负载自身的标签生成 Pod 选择器，所有按应用筛选资源的地方都应使用它
+// 选择器创建后不可修改，早期创建的工作负载没有 app-id 标签，继续使用 app=<name>
+func labelSelectorForApp(name string, workloadLabels map[string]string) string {
+	set := labels.Set{"app": name}
+	if id := workloadLabels[AppIDLabel]; id != "" {
+		set[AppIDLabel] = id
+	}
+	return set.AsSelector().String()
+}
+
+// managedSelector 平台创建的、带指定标签的资源选择器，用于批量清理
+func managedSelector(key, value string) string {
+	return labels.Set{key: value, ManagedByLabel: ManagedByValue}.AsSelector().String()
+}
+
+// podSelector 查询应用的工作负载（Deployment/StatefulSet/CronJob）并返回其 Pod 选择器，均不存在时退回 app=<name>
+func (a *ClientGoAdapter) podSelector(ctx context.Context, name, namespace string) (string, error) {
+	deployment, err := a.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return labelSelectorForApp(name, deployment.Labels), nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 Deployment 失败: %w", err)
+	}
+
+	statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return labelSelectorForApp(name, statefulSet.Labels), nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 StatefulSet 失败: %w", err)
+	}
+
+	cronJob, err := a.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return labelSelectorForApp(name, cronJob.Labels), nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("获取 CronJob 失败: %w", err)
+	}
+	return labelSelectorForApp(name, nil), nil
+}
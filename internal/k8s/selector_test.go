@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestSelectorLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		spec AppSpec
+		want map[string]string
+	}{
+		{"无应用 ID", AppSpec{Name: "api"}, map[string]string{"app": "api"}},
+		{"带应用 ID", AppSpec{Name: "api", AppID: 42}, map[string]string{"app": "api", AppIDLabel: "42"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectorLabels(tt.spec); !labels.Equals(got, tt.want) {
+				t.Errorf("selectorLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorForApp(t *testing.T) {
+	tests := []struct {
+		name           string
+		workloadLabels map[string]string
+		want           string
+	}{
+		{"早期创建的工作负载", map[string]string{"app": "api"}, "app=api"},
+		{"带应用 ID", map[string]string{"app": "api", AppIDLabel: "42"}, "app=api," + AppIDLabel + "=42"},
+		{"忽略其他标签", map[string]string{"app": "api", "team": "ops"}, "app=api"},
+		{"无标签", nil, "app=api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := labelSelectorForApp("api", tt.workloadLabels)
+			if got != tt.want {
+				t.Errorf("labelSelectorForApp() = %q, want %q", got, tt.want)
+			}
+			if _, err := labels.Parse(got); err != nil {
+				t.Errorf("选择器无法解析: %v", err)
+			}
+		})
+	}
+}
+
+func TestManagedSelector(t *testing.T) {
+	selector, err := labels.Parse(managedSelector("app", "api"))
+	if err != nil {
+		t.Fatalf("选择器无法解析: %v", err)
+	}
+	tests := []struct {
+		name   string
+		labels labels.Set
+		want   bool
+	}{
+		{"平台创建的资源", labels.Set{"app": "api", ManagedByLabel: ManagedByValue}, true},
+		{"非平台创建的资源", labels.Set{"app": "api"}, false},
+		{"其他应用", labels.Set{"app": "web", ManagedByLabel: ManagedByValue}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selector.Matches(tt.labels); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodSelector(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "db", Namespace: testNamespace, Labels: map[string]string{"app": "db", AppIDLabel: "7"},
+	}}
+	withID := testDeployment("api", 1, 1)
+	withID.Labels[AppIDLabel] = "42"
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		app     string
+		want    string
+	}{
+		{"Deployment", []runtime.Object{withID}, "api", "app=api," + AppIDLabel + "=42"},
+		{"早期创建的 Deployment", []runtime.Object{testDeployment("api", 1, 1)}, "api", "app=api"},
+		{"StatefulSet", []runtime.Object{statefulSet}, "db", "app=db," + AppIDLabel + "=7"},
+		{"工作负载不存在", nil, "api", "app=api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestAdapter(tt.objects...).podSelector(context.Background(), tt.app, testNamespace)
+			if err != nil {
+				t.Fatalf("podSelector() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("podSelector() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
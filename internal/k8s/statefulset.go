@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// statefulSetVolumeName 每个副本独立持久卷的名称，PVC 名为 data-<name>-<ordinal>
+	statefulSetVolumeName = "data"
+	// defaultStorageMountPath 未指定挂载路径时的默认值
+	defaultStorageMountPath = "/data"
+)
+
+// deleteStatefulSet 删除 StatefulSet 及其持久卷声明（StatefulSet 删除时默认保留 PVC）。
+// 持久卷声明按 StatefulSet 的选择器标签（含 app-id）筛选，不会误删同名应用的数据；
+// 先删除持久卷声明，删除失败重试时仍能从 StatefulSet 取得选择器，使用中的 PVC 由保护终结器保留到 Pod 退出
+func (a *ClientGoAdapter) deleteStatefulSet(ctx context.Context, name, namespace string) error {
+	statefulSet, err := a.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("获取 StatefulSet 失败: %w", err)
+	}
+
+	err = a.client.CoreV1().PersistentVolumeClaims(namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: labelSelectorForApp(statefulSet.Name, statefulSet.Labels),
+	})
+	if err != nil {
+		return fmt.Errorf("删除持久卷声明失败: %w", err)
+	}
+	err = a.client.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("删除 StatefulSet 失败: %w", err)
+	}
+	return nil
+}
+
+// createStatefulSet 创建有状态应用：Headless Service + 带 volumeClaimTemplate 的 StatefulSet
+// 每个 Pod 拥有稳定的 DNS 名 <name>-<ordinal>.<name>.<namespace>.svc.cluster.local 和独立的持久卷
+func (a *ClientGoAdapter) createStatefulSet(ctx context.Context, spec AppSpec, labels map[string]string, template corev1.PodTemplateSpec) error {
+	size, err := resource.ParseQuantity(spec.StorageSize)
+	if err != nil {
+		return fmt.Errorf("无效的存储容量 %q: %w", spec.StorageSize, err)
+	}
+
+	mountPath := spec.StorageMountPath
+	if mountPath == "" {
+		mountPath = defaultStorageMountPath
+	}
+	template.Spec.Containers[0].VolumeMounts = append(template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      statefulSetVolumeName,
+		MountPath: mountPath,
+	})
+
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   statefulSetVolumeName,
+			Labels: labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if spec.StorageClass != "" {
+		claim.Spec.StorageClassName = &spec.StorageClass
+	}
+
+	// StatefulSet 依赖 Headless Service 提供 Pod DNS，需先于 StatefulSet 创建
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Labels:      labels,
+			Annotations: ownerAnnotations(spec, spec.ServiceAnnotations),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector:  selectorLabels(spec),
+			ClusterIP: corev1.ClusterIPNone,
+			// 未就绪的 Pod 也发布 DNS 记录，集群成员在启动阶段即可互相发现
+			PublishNotReadyAddresses: true,
+		},
+	}
+	if spec.Port > 0 {
+		service.Spec.Ports = []corev1.ServicePort{
+			{
+				Port:       spec.Port,
+				TargetPort: intstr.FromInt32(spec.Port),
+			},
+		}
+	}
+	_, err = a.client.CoreV1().Services(spec.Namespace).Create(ctx, service, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("创建 Service 失败: %w", err)
+	}
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        spec.Name,
+			Namespace:   spec.Namespace,
+			Labels:      labels,
+			Annotations: ownerAnnotations(spec, spec.DeploymentAnnotations),
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &spec.Replicas,
+			ServiceName: spec.Name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels(spec),
+			},
+			Template:             template,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim},
+			RevisionHistoryLimit: spec.RevisionHistoryLimit,
+			MinReadySeconds:      spec.MinReadySeconds,
+		},
+	}
+
+	_, err = a.client.AppsV1().StatefulSets(spec.Namespace).Create(ctx, statefulSet, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("创建 StatefulSet 失败: %w", err)
+	}
+	return nil
+}
+
+// scaleStatefulSet 调整有状态应用副本数
+func (a *ClientGoAdapter) scaleStatefulSet(ctx context.Context, statefulSet *appsv1.StatefulSet, replicas int32) error {
+	statefulSet.Spec.Replicas = &replicas
+	_, err := a.client.AppsV1().StatefulSets(statefulSet.Namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("更新副本数失败: %w", err)
+	}
+	return nil
+}
+
+// restartStatefulSet 通过修改 Pod 模板注解触发按序号倒序的滚动重启
+func (a *ClientGoAdapter) restartStatefulSet(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
+	if statefulSet.Spec.Template.Annotations == nil {
+		statefulSet.Spec.Template.Annotations = make(map[string]string)
+	}
+	statefulSet.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+
+	_, err := a.client.AppsV1().StatefulSets(statefulSet.Namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("重启 StatefulSet 失败: %w", err)
+	}
+	return nil
+}
+
+// getStatefulSetStatus 获取有状态应用状态，Pod 按序号排序并带上 ordinal
+func (a *ClientGoAdapter) getStatefulSetStatus(ctx context.Context, statefulSet *appsv1.StatefulSet) (*AppStatus, error) {
+	podInfos, restartCount, err := a.listPodInfos(ctx, labelSelectorForApp(statefulSet.Name, statefulSet.Labels), statefulSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := statefulSet.Name + "-"
+	for i := range podInfos {
+		if ordinal, err := strconv.Atoi(strings.TrimPrefix(podInfos[i].Name, prefix)); err == nil {
+			podInfos[i].Ordinal = &ordinal
+		}
+	}
+	sort.SliceStable(podInfos, func(i, j int) bool {
+		if podInfos[i].Ordinal == nil || podInfos[j].Ordinal == nil {
+			return podInfos[j].Ordinal == nil && podInfos[i].Ordinal != nil
+		}
+		return *podInfos[i].Ordinal < *podInfos[j].Ordinal
+	})
+
+	var replicas int32
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	return &AppStatus{
+		Status:        determineStatefulSetStatus(statefulSet),
+		ReadyReplicas: statefulSet.Status.ReadyReplicas,
+		Replicas:      replicas,
+		RestartCount:  restartCount,
+		Pods:          podInfos,
+	}, nil
+}
+
+// determineStatefulSetStatus 根据 StatefulSet 状态确定应用状态
+func determineStatefulSetStatus(statefulSet *appsv1.StatefulSet) string {
+	if statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas == 0 {
+		return "stopped"
+	}
+	if isStatefulSetReady(statefulSet) {
+		return "running"
+	}
+	if statefulSet.Status.ReadyReplicas == 0 {
+		return "pending"
+	}
+	return "starting"
+}
+
+// isStatefulSetReady 判断 StatefulSet 是否完成更新且所有副本就绪
+func isStatefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	desired := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+	status := statefulSet.Status
+	return status.ObservedGeneration >= statefulSet.Generation &&
+		status.UpdatedReplicas == desired &&
+		status.ReadyReplicas == desired
+}
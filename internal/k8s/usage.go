@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceAmounts CPU 与内存用量
+type ResourceAmounts struct {
+	CPUMillis   int64 `json:"cpu_millis"`   // CPU，单位毫核
+	MemoryBytes int64 `json:"memory_bytes"` // 内存，单位字节
+}
+
+// add 累加容器的 CPU 与内存
+func (r *ResourceAmounts) add(list corev1.ResourceList) {
+	if cpu, ok := list[corev1.ResourceCPU]; ok {
+		r.CPUMillis += cpu.MilliValue()
+	}
+	if memory, ok := list[corev1.ResourceMemory]; ok {
+		r.MemoryBytes += memory.Value()
+	}
+}
+
+// NamespaceUsage 命名空间资源汇总
+type NamespaceUsage struct {
+	Namespace   string          `json:"namespace"`
+	Pods        int             `json:"pods"`         // 未结束的 Pod 数
+	RunningPods int             `json:"running_pods"` // Running 状态的 Pod 数
+	Requests    ResourceAmounts `json:"requests"`     // 未结束 Pod 的容器 requests 之和
+	Limits      ResourceAmounts `json:"limits"`       // 未结束 Pod 的容器 limits 之和，未设置 limit 的容器不计入
+	// Usage 实时用量，来自 metrics-server；集群未安装或不可用时为空，原因见 MetricsError
+	Usage        *ResourceAmounts `json:"usage,omitempty"`
+	MetricsError string           `json:"metrics_error,omitempty"`
+}
+
+// podMetricsList metrics.k8s.io/v1beta1 PodMetricsList 中用到的字段，避免为此引入 metrics 客户端依赖
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// GetNamespaceUsage 汇总命名空间内 Pod 的 requests/limits 与实时用量，只允许查询 Astro 创建的命名空间
+func (a *ClientGoAdapter) GetNamespaceUsage(ctx context.Context, namespace string) (*NamespaceUsage, error) {
+	ns, err := a.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, ErrNamespaceNotManaged
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取命名空间失败: %w", err)
+	}
+	if ns.Labels[ManagedByLabel] != ManagedByValue {
+		return nil, ErrNamespaceNotManaged
+	}
+
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取 Pod 列表失败: %w", err)
+	}
+
+	usage := &NamespaceUsage{Namespace: namespace}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		usage.Pods++
+		if pod.Status.Phase == corev1.PodRunning {
+			usage.RunningPods++
+		}
+		// 只统计常驻容器，init 容器运行结束后不再占用资源
+		for _, container := range pod.Spec.Containers {
+			usage.Requests.add(container.Resources.Requests)
+			usage.Limits.add(container.Resources.Limits)
+		}
+	}
+
+	live, err := a.namespaceMetrics(ctx, namespace)
+	if err != nil {
+		usage.MetricsError = err.Error()
+	} else {
+		usage.Usage = live
+	}
+	return usage, nil
+}
+
+// namespaceMetrics 通过 metrics.k8s.io 获取命名空间内所有 Pod 的实时用量之和
+func (a *ClientGoAdapter) namespaceMetrics(ctx context.Context, namespace string) (*ResourceAmounts, error) {
+	restClient := a.client.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("当前客户端不支持 metrics API")
+	}
+	raw, err := restClient.Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		DoRaw(ctx)
+	if err != nil {
+		if errors.IsNotFound(err) || errors.IsServiceUnavailable(err) {
+			return nil, fmt.Errorf("metrics-server 未安装或不可用")
+		}
+		return nil, fmt.Errorf("获取实时用量失败: %w", err)
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("解析实时用量失败: %w", err)
+	}
+	total := &ResourceAmounts{}
+	for _, item := range list.Items {
+		for _, container := range item.Containers {
+			total.add(container.Usage)
+		}
+	}
+	return total, nil
+}
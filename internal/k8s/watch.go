@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// WatchApp 监听应用 Deployment 和 Pod 的变化，每次变化推送一次最新状态
+// ctx 取消、Deployment 被删除或 watch 被服务端关闭时关闭返回的 channel
+func (a *ClientGoAdapter) WatchApp(ctx context.Context, name, namespace string) (<-chan *AppStatus, error) {
+	deploymentWatcher, err := a.client.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("监听 Deployment 失败: %w", err)
+	}
+
+	selector, err := a.podSelector(ctx, name, namespace)
+	if err != nil {
+		deploymentWatcher.Stop()
+		return nil, err
+	}
+	podWatcher, err := a.client.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		deploymentWatcher.Stop()
+		return nil, fmt.Errorf("监听 Pod 失败: %w", err)
+	}
+
+	statusCh := make(chan *AppStatus)
+	go func() {
+		defer close(statusCh)
+		defer deploymentWatcher.Stop()
+		defer podWatcher.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-deploymentWatcher.ResultChan():
+				if !ok || event.Type == watch.Deleted {
+					return
+				}
+			case _, ok := <-podWatcher.ResultChan():
+				if !ok {
+					return
+				}
+			}
+
+			status, err := a.GetAppStatus(ctx, name, namespace)
+			if err != nil {
+				continue
+			}
+			select {
+			case statusCh <- status:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return statusCh, nil
+}
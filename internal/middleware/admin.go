@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Admin 管理员权限中间件，需放在 Auth 之后
+// 每次请求从数据库读取角色，角色变更即时生效；管理接口只接受登录 JWT，
+// 访问令牌的权限范围只覆盖应用操作，不能用于任何管理操作
+func Admin(c *container.Container) gin.HandlerFunc {
+	userRepo := repository.NewUserRepository(c.DB)
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			handler.ErrorWithCode(c, errcode.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+		if c.GetString(handler.ContextKeyAuthType) != handler.AuthTypeJWT {
+			handler.Forbidden(c, "管理接口需要使用登录凭证，访问令牌不能调用")
+			c.Abort()
+			return
+		}
+
+		user, err := userRepo.GetUserByID(userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			handler.ErrorWithCode(c, errcode.ErrDatabase)
+			c.Abort()
+			return
+		}
+		if err != nil || user.Role != model.RoleAdmin {
+			handler.ErrorWithCode(c, errcode.ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	admin := &model.User{Username: "admin", Password: "x", Email: "admin@example.com", Role: model.RoleAdmin}
+	user := &model.User{Username: "user", Password: "x", Email: "user@example.com", Role: model.RoleUser}
+	for _, u := range []*model.User{admin, user} {
+		if err := db.Create(u).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+	}
+	ctr := &container.Container{DB: db}
+
+	tests := []struct {
+		name     string
+		userID   uint
+		authType string
+		want     errcode.Code
+	}{
+		{"管理员登录凭证", admin.ID, handler.AuthTypeJWT, errcode.Success},
+		{"管理员访问令牌", admin.ID, handler.AuthTypePAT, errcode.ErrForbidden},
+		{"普通用户", user.ID, handler.AuthTypeJWT, errcode.ErrForbidden},
+		{"用户不存在", 9999, handler.AuthTypeJWT, errcode.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Set(contextKeyUserID, tt.userID)
+				c.Set(handler.ContextKeyAuthType, tt.authType)
+			}, Admin(ctr))
+			r.GET("/admin", func(c *gin.Context) { handler.Success(c, nil) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			var resp handler.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Code != tt.want.Int() {
+				t.Errorf("code = %d, want %d（%s）", resp.Code, tt.want.Int(), resp.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// auditActions 路由到审计动作名的映射，未列出的变更类路由使用 "方法 路径" 作为动作名
+var auditActions = map[string]string{
+	"POST /api/v1/register":                "user.register",
+	"POST /api/v1/login":                   "user.login",
+	"POST /api/v1/users/email":             "user.update_email",
+	"POST /api/v1/apps":                    "app.create",
+	"PATCH /api/v1/apps/:id":               "app.patch",
+	"DELETE /api/v1/apps/:id":              "app.delete",
+	"POST /api/v1/apps/batch/start":        "app.batch_start",
+	"POST /api/v1/apps/batch/stop":         "app.batch_stop",
+	"POST /api/v1/apps/batch/restart":      "app.batch_restart",
+	"POST /api/v1/apps/:id/start":          "app.start",
+	"POST /api/v1/apps/:id/stop":           "app.stop",
+	"POST /api/v1/apps/:id/restart":        "app.restart",
+	"POST /api/v1/apps/:id/scale":          "app.scale",
+	"POST /api/v1/apps/:id/scale-to-zero":  "app.scale_to_zero",
+	"POST /api/v1/apps/:id/wake":           "app.wake",
+	"POST /api/v1/apps/:id/suspend":        "app.suspend",
+	"POST /api/v1/apps/:id/resume":         "app.resume",
+	"POST /api/v1/apps/:id/rollback":       "app.rollback",
+	"DELETE /api/v1/apps/:id/pods/:pod":    "app.pod_delete",
+	"PUT /api/v1/apps/:id/env":             "app.env_update",
+	"POST /api/v1/apps/:id/rollout/pause":  "app.rollout_pause",
+	"POST /api/v1/apps/:id/rollout/resume": "app.rollout_resume",
+	"POST /api/v1/stacks":                  "stack.create",
+	"DELETE /api/v1/stacks/:id":            "stack.delete",
+	"POST /api/v1/stacks/:id/start":        "stack.start",
+	"POST /api/v1/stacks/:id/stop":         "stack.stop",
+	"POST /api/v1/tokens":                  "token.create",
+	"DELETE /api/v1/tokens/:id":            "token.revoke",
+	"POST /api/v1/registry/test":           "registry.test",
+	"POST /api/v1/admin/impersonate/:id":   "admin.impersonate",
+	"POST /api/v1/admin/users/import":      "admin.user_import",
+	"PUT /api/v1/admin/users/:id/team":     "admin.user_team",
+}
+
+// Audit 审计日志中间件，记录所有变更类请求（非 GET/HEAD/OPTIONS）
+func Audit(auditSvc *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		route := c.Request.Method + " " + c.FullPath()
+		action, ok := auditActions[route]
+		if !ok {
+			action = route
+		}
+
+		target := c.GetString(handler.ContextKeyAuditTarget)
+		if target == "" {
+			target = c.Param("id")
+		}
+
+		code := errcode.Success
+		if value, exists := c.Get(handler.ContextKeyResponseCode); exists {
+			if responseCode, ok := value.(errcode.Code); ok {
+				code = responseCode
+			}
+		} else if c.Writer.Status() >= http.StatusBadRequest {
+			// 未经过统一响应（如路由不存在、panic）
+			code = errcode.ErrInternal
+		}
+		result := "success"
+		if code != errcode.Success {
+			result = "failure"
+		}
+
+		log := &model.AuditLog{
+			CreatedAt: time.Now(),
+			UserID:    c.GetUint(contextKeyUserID),
+			Action:    action,
+			Target:    target,
+			Result:    result,
+			Code:      code.Int(),
+			IP:        c.ClientIP(),
+		}
+		if actorID := c.GetUint(handler.ContextKeyActorID); actorID > 0 {
+			log.ActorID = &actorID
+		}
+		auditSvc.Record(log)
+	}
+}
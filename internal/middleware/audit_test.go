@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/internal/service"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuditLoginUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		password   string
+		wantResult string
+		wantUser   bool
+	}{
+		{"登录成功记录用户", "passw0rd", "success", true},
+		{"密码错误不记录用户", "wrong-pass1", "failure", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+			if err != nil {
+				t.Fatalf("初始化数据库失败: %v", err)
+			}
+			ctr := &container.Container{DB: db, Config: &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expire: "1h"}}}
+			hash, err := bcrypt.GenerateFromPassword([]byte("passw0rd"), bcrypt.MinCost)
+			if err != nil {
+				t.Fatal(err)
+			}
+			user := &model.User{Username: "alice", Password: string(hash), Email: "alice@example.com", Role: model.RoleUser}
+			if err := db.Create(user).Error; err != nil {
+				t.Fatalf("创建用户失败: %v", err)
+			}
+
+			r := gin.New()
+			api := r.Group("/api/v1")
+			api.Use(Audit(service.NewAuditService(ctr)))
+			handler.RegisterUserRoutes(api, ctr)
+			body := `{"username":"alice","password":"` + tt.password + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			// 审计日志异步写入
+			repo := repository.NewAuditRepository(db)
+			var logs []model.AuditLog
+			for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+				if logs, _, err = repo.List(repository.AuditFilter{Action: "user.login"}, 0, 10); err == nil && len(logs) > 0 {
+					break
+				}
+			}
+			if len(logs) != 1 {
+				t.Fatalf("审计日志 %d 条, want 1", len(logs))
+			}
+			if logs[0].Result != tt.wantResult || logs[0].Target != "alice" {
+				t.Errorf("审计日志 = %s %s, want %s alice", logs[0].Result, logs[0].Target, tt.wantResult)
+			}
+			wantUserID := uint(0)
+			if tt.wantUser {
+				wantUserID = user.ID
+			}
+			if logs[0].UserID != wantUserID {
+				t.Errorf("user_id = %d, want %d", logs[0].UserID, wantUserID)
+			}
+		})
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/cuihe500/astro/internal/container"
 	"github.com/cuihe500/astro/internal/handler"
-	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/internal/service"
 	"github.com/cuihe500/astro/pkg/errcode"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -13,8 +14,18 @@ import (
 
 const contextKeyUserID = "user_id"
 
-// Auth JWT 认证中间件
-func Auth() gin.HandlerFunc {
+// Auth 认证中间件，支持登录 JWT 与个人访问令牌（以 astro_pat_ 开头）
+func Auth(ctr *container.Container) gin.HandlerFunc {
+	cfg := &ctr.Config.JWT
+	tokenSvc := service.NewTokenService(ctr)
+	// 配置了签发者与受众时要求 Token 中的 iss/aud 一致，不一致按 Token 无效处理
+	var parseOptions []jwt.ParserOption
+	if cfg.Issuer != "" {
+		parseOptions = append(parseOptions, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		parseOptions = append(parseOptions, jwt.WithAudience(cfg.Audience))
+	}
 	return func(c *gin.Context) {
 		// 获取 Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -34,13 +45,31 @@ func Auth() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
+		if service.IsPersonalAccessToken(tokenString) {
+			pat, err := tokenSvc.Authenticate(tokenString)
+			if err != nil {
+				handler.HandleError(c, err)
+				c.Abort()
+				return
+			}
+			c.Set(contextKeyUserID, pat.UserID)
+			c.Set(handler.ContextKeyAuthType, handler.AuthTypePAT)
+			c.Set(handler.ContextKeyTokenScopes, pat.Scopes)
+			c.Set(handler.ContextKeyTokenIssuedAt, pat.CreatedAt)
+			if pat.ExpiresAt != nil {
+				c.Set(handler.ContextKeyTokenExpiresAt, *pat.ExpiresAt)
+			}
+			c.Next()
+			return
+		}
+
 		// 解析并验证 token
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, jwt.ErrSignatureInvalid
 			}
-			return []byte(config.GlobalConfig.JWT.Secret), nil
-		})
+			return []byte(cfg.Secret), nil
+		}, parseOptions...)
 
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
@@ -69,6 +98,24 @@ func Auth() gin.HandlerFunc {
 		}
 
 		c.Set(contextKeyUserID, uint(userID))
+		c.Set(handler.ContextKeyAuthType, handler.AuthTypeJWT)
+		// 早期签发的 Token 没有 iat
+		if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+			c.Set(handler.ContextKeyTokenIssuedAt, iat.Time)
+		}
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			c.Set(handler.ContextKeyTokenExpiresAt, exp.Time)
+		}
+		// 模拟登录 Token 的 act 声明记录实际操作的管理员
+		if act, ok := claims["act"].(map[string]interface{}); ok {
+			actorID, ok := act["user_id"].(float64)
+			if !ok {
+				handler.ErrorWithCode(c, errcode.ErrTokenInvalid)
+				c.Abort()
+				return
+			}
+			c.Set(handler.ContextKeyActorID, uint(actorID))
+		}
 		c.Next()
 	}
 }
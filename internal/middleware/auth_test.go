@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthIssuerAudience(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	const secret = "test-secret"
+
+	sign := func(iss, aud string) string {
+		claims := jwt.MapClaims{
+			"user_id": 1,
+			"iat":     time.Now().Unix(),
+			"exp":     time.Now().Add(time.Hour).Unix(),
+		}
+		if iss != "" {
+			claims["iss"] = iss
+		}
+		if aud != "" {
+			claims["aud"] = aud
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name        string
+		cfgIssuer   string
+		cfgAudience string
+		tokenIss    string
+		tokenAud    string
+		want        errcode.Code
+	}{
+		{"未配置时接受不带 iss/aud 的 Token", "", "", "", "", errcode.Success},
+		{"未配置时接受带 iss/aud 的 Token", "", "", "astro", "astro-api", errcode.Success},
+		{"iss/aud 一致", "astro", "astro-api", "astro", "astro-api", errcode.Success},
+		{"iss 不一致", "astro", "astro-api", "other", "astro-api", errcode.ErrTokenInvalid},
+		{"aud 不一致", "astro", "astro-api", "astro", "other-api", errcode.ErrTokenInvalid},
+		{"启用后缺少 iss", "astro", "", "", "", errcode.ErrTokenInvalid},
+		{"启用后缺少 aud", "", "astro-api", "", "", errcode.ErrTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr := &container.Container{
+				DB: db,
+				Config: &config.Config{JWT: config.JWTConfig{
+					Secret:   secret,
+					Issuer:   tt.cfgIssuer,
+					Audience: tt.cfgAudience,
+				}},
+			}
+			r := gin.New()
+			r.Use(Auth(ctr))
+			r.GET("/me", func(c *gin.Context) { handler.Success(c, nil) })
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+sign(tt.tokenIss, tt.tokenAud))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var resp handler.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Code != tt.want.Int() {
+				t.Errorf("code = %d, want %d（%s）", resp.Code, tt.want.Int(), resp.Message)
+			}
+		})
+	}
+}
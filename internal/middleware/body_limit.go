@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaxBodyBytes 未配置时的请求体大小上限
+const defaultMaxBodyBytes int64 = 8 << 20
+
+// BodyLimit 请求体大小限制中间件，超出上限返回 ErrBodyTooLarge
+// 在进入 handler 前读取完整请求体，保证超限错误以统一响应格式返回，而不是混入参数绑定错误
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			handler.ErrorWithCode(c, errcode.ErrBodyTooLarge)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				handler.ErrorWithCode(c, errcode.ErrBodyTooLarge)
+			} else {
+				handler.BadRequest(c, "读取请求体失败")
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// 未配置时的压缩参数
+var (
+	defaultGzipMinSize      = 1024
+	defaultGzipContentTypes = []string{"application/json", "text/plain", "text/html", "text/css", "application/javascript"}
+)
+
+// gzipWriterPool 复用 gzip.Writer，避免每个请求分配压缩缓冲区
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// Gzip 响应压缩中间件，客户端声明支持 gzip 且响应类型在白名单内、首次写入不小于阈值时压缩
+// WebSocket 升级请求和 text/event-stream 等流式响应不压缩
+func Gzip(cfg config.GzipConfig) gin.HandlerFunc {
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = defaultGzipMinSize
+	}
+	contentTypes := cfg.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = defaultGzipContentTypes
+	}
+	allowed := make(map[string]bool, len(contentTypes))
+	for _, t := range contentTypes {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Enabled || c.Request.Method == http.MethodHead ||
+			c.GetHeader("Upgrade") != "" ||
+			!strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+
+		w := &gzipResponseWriter{ResponseWriter: c.Writer, minSize: minSize, allowed: allowed}
+		c.Writer = w
+		c.Header("Vary", "Accept-Encoding")
+		defer w.close()
+		c.Next()
+	}
+}
+
+// gzipResponseWriter 在首次写入时根据响应类型和大小决定是否压缩
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	allowed map[string]bool
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(data))
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.gz.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// decide 确定是否压缩，压缩时改写响应头，必须在写出响应头之前调用
+func (w *gzipResponseWriter) decide(size int) {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || w.Written() {
+		return
+	}
+	// 已知总长度时按总长度判断，否则按首次写入的大小判断
+	if n, err := strconv.Atoi(header.Get("Content-Length")); err == nil {
+		size = n
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if size < w.minSize || !w.allowed[mediaType] {
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.gz = gzipWriterPool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+}
+
+// Flush 先刷出压缩缓冲区再刷出底层连接
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close 写出压缩尾部并归还 gzip.Writer
+func (w *gzipResponseWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(nil)
+	gzipWriterPool.Put(w.gz)
+	w.gz = nil
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求 ID 的请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID 沿用上游（如网关）传入的请求 ID 时的格式要求，避免日志注入
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 请求 ID 中间件，沿用请求头中合法的 X-Request-ID，否则生成新的 ID；
+// 写入上下文与响应头，错误响应与访问日志中同时带上，便于按 ID 关联用户反馈与服务端日志
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(handler.ContextKeyRequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// AccessLog 访问日志中间件，格式与 gin 默认日志一致并追加请求 ID，需放在 RequestID 之后
+func AccessLog() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		requestID, _ := param.Keys[handler.ContextKeyRequestID].(string)
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			param.StatusCode,
+			param.Latency.Truncate(time.Microsecond),
+			param.ClientIP,
+			param.Method,
+			param.Path,
+			requestID,
+			param.ErrorMessage,
+		)
+	})
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/cuihe500/astro/internal/handler"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope 权限范围校验中间件，需放在 Auth 之后
+// 登录 JWT 及未设置范围的访问令牌拥有全部权限，apps:write 隐含 apps:read
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(handler.ContextKeyAuthType) != handler.AuthTypePAT {
+			c.Next()
+			return
+		}
+
+		scopes := c.GetStringSlice(handler.ContextKeyTokenScopes)
+		if len(scopes) == 0 || slices.Contains(scopes, scope) ||
+			scope == model.ScopeAppsRead && slices.Contains(scopes, model.ScopeAppsWrite) {
+			c.Next()
+			return
+		}
+
+		handler.ErrorWithCode(c, errcode.ErrForbidden)
+		c.Abort()
+	}
+}
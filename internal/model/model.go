@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +24,11 @@ type User struct {
 	Password string `gorm:"size:128;not null" json:"-"`
 	Email    string `gorm:"size:128;uniqueIndex" json:"email"`
 	Status   int    `gorm:"default:1" json:"status"`
+	Team     string `gorm:"size:64;index" json:"team"`
+	Role     string `gorm:"size:16;default:user" json:"role"` // user/admin
+	// 配额覆盖，0 表示使用全局配置
+	MaxApps     int `gorm:"default:0" json:"max_apps"`
+	MaxReplicas int `gorm:"default:0" json:"max_replicas"`
 }
 
 // BeforeCreate 创建用户前自动生成 UUID
@@ -34,10 +40,115 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 // App 应用模型
 type App struct {
 	BaseModel
-	Name      string `gorm:"size:64;not null" json:"name"`
-	Image     string `gorm:"size:256;not null" json:"image"`
-	Replicas  int    `gorm:"default:1" json:"replicas"`
-	Status    string `gorm:"size:32;default:stopped" json:"status"`
+	Name                  string            `gorm:"size:64;not null;index" json:"name"` // 唯一范围由 app.name_scope 决定，软删除后可复用，因此不建唯一索引
+	Image                 string            `gorm:"size:256;not null" json:"image"`
+	Kind                  string            `gorm:"size:16;default:deployment" json:"kind"`  // deployment/cronjob
+	Schedule              string            `gorm:"size:64" json:"schedule,omitempty"`       // cronjob 的 cron 表达式
+	RestartPolicy         string            `gorm:"size:16" json:"restart_policy,omitempty"` // cronjob 的 Pod 重启策略 OnFailure/Never
+	BackoffLimit          *int32            `json:"backoff_limit,omitempty"`                 // cronjob 单次任务失败重试次数
+	Replicas              int               `gorm:"default:1" json:"replicas"`               // 当前副本数，停止后为 0
+	DesiredReplicas       int               `gorm:"default:0" json:"desired_replicas"`       // 用户期望的副本数，停止后保留，启动时据此恢复
+	Status                string            `gorm:"size:32;default:stopped" json:"status"`
+	Suspended             bool              `gorm:"default:false" json:"suspended"`      // 挂起后平台不再同步状态
+	ScaledToZero          bool              `gorm:"default:false" json:"scaled_to_zero"` // 已缩容到零，保留期望副本数，可通过唤醒接口恢复
+	LastSyncedAt          *time.Time        `json:"last_synced_at"`                      // 最近一次从 K8s 同步状态的时间，供前端展示状态新鲜度
+	UserID                uint              `gorm:"index;not null" json:"user_id"`
+	Namespace             string            `gorm:"size:64" json:"namespace"`
+	ServiceAnnotations    map[string]string `gorm:"serializer:json;type:text" json:"service_annotations,omitempty"`
+	DeploymentAnnotations map[string]string `gorm:"serializer:json;type:text" json:"deployment_annotations,omitempty"`
+	ImagePullPolicy       string            `gorm:"size:16" json:"image_pull_policy"`
+	Headless              bool              `gorm:"default:false" json:"headless"`   // Service 是否为 Headless（ClusterIP: None）
+	Spread                string            `gorm:"size:16" json:"spread,omitempty"` // 副本打散维度 node/zone
+	// statefulset 每个副本的持久卷配置
+	StorageSize      string `gorm:"size:32" json:"storage_size,omitempty"`
+	StorageClass     string `gorm:"size:64" json:"storage_class,omitempty"`
+	StorageMountPath string `gorm:"size:256" json:"storage_mount_path,omitempty"`
+	// TerminationGracePeriodSeconds 优雅退出等待秒数，为空使用 K8s 默认值（30）
+	TerminationGracePeriodSeconds *int64   `json:"termination_grace_period_seconds,omitempty"`
+	PreStopCommand                []string `gorm:"serializer:json;type:text" json:"pre_stop_command,omitempty"` // 容器停止前执行的命令
+	// 容器工作目录与 Pod 主机名、子域名，为空使用镜像或 K8s 默认值
+	WorkingDir string `gorm:"size:256" json:"working_dir,omitempty"`
+	Hostname   string `gorm:"size:63" json:"hostname,omitempty"`
+	Subdomain  string `gorm:"size:63" json:"subdomain,omitempty"`
+	// Pod 使用的 ServiceAccount，为空使用命名空间的 default
+	ServiceAccountName string `gorm:"size:253" json:"service_account_name,omitempty"`
+	// Pod DNS 策略与自定义 DNS 配置，为空使用 K8s 默认值
+	DNSPolicy      string            `gorm:"size:32" json:"dns_policy,omitempty"`
+	DNSNameservers []string          `gorm:"serializer:json;type:text" json:"dns_nameservers,omitempty"`
+	DNSSearches    []string          `gorm:"serializer:json;type:text" json:"dns_searches,omitempty"`
+	DNSOptions     map[string]string `gorm:"serializer:json;type:text" json:"dns_options,omitempty"`
+	// 源码构建信息，从 Git 仓库构建的应用才有值
+	SourceGitURL     string `gorm:"size:512" json:"source_git_url,omitempty"`
+	SourceRef        string `gorm:"size:128" json:"source_ref,omitempty"`
+	SourceDockerfile string `gorm:"size:256" json:"source_dockerfile,omitempty"`
+	BuildJob         string `gorm:"size:128" json:"build_job,omitempty"` // 最近一次构建的 Job 名
+	// Metadata 用户自定义元数据（JSON 对象），仅用于平台内组织展示，不写入 K8s
+	Metadata json.RawMessage `gorm:"serializer:json;type:text" json:"metadata,omitempty" swaggertype:"object"`
+	// Env 容器环境变量，按顺序写入容器，后面的变量可通过 $(NAME) 引用前面的变量
+	Env     []EnvVar `gorm:"serializer:json;type:text" json:"env,omitempty"`
+	StackID *uint    `gorm:"index" json:"stack_id,omitempty"` // 所属应用组，单独创建的应用为空
+	// 重启告警基线：上次告警（或 Pod 替换后重新计数）时的重启次数与告警时间，重启次数相对基线的增量达到阈值才告警
+	AlertedRestartCount int32      `gorm:"default:0" json:"-"`
+	RestartAlertedAt    *time.Time `json:"-"`
+}
+
+// EnvVar 容器环境变量
+type EnvVar struct {
+	Name  string `json:"name" example:"LOG_LEVEL"`
+	Value string `json:"value" example:"info"`
+}
+
+// Stack 应用组，一次部署的多个应用，可整体删除
+type Stack struct {
+	BaseModel
+	Name      string `gorm:"size:64;not null;index" json:"name"` // 同一用户内唯一
 	UserID    uint   `gorm:"index;not null" json:"user_id"`
-	Namespace string `gorm:"size:64" json:"namespace"`
+	Namespace string `gorm:"size:64" json:"namespace"` // 成员应用所在的命名空间，${svc:<应用名>} 据此解析
+}
+
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// 访问令牌权限范围
+const (
+	ScopeAppsRead  = "apps:read"
+	ScopeAppsWrite = "apps:write"
+)
+
+// PersonalAccessToken 个人访问令牌，供 CI 等自动化场景长期使用
+type PersonalAccessToken struct {
+	BaseModel
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"size:64;not null" json:"name"`
+	TokenHash  string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"` // 令牌 SHA-256，不保存明文
+	Prefix     string     `gorm:"size:16" json:"prefix"`                       // 令牌前几位，便于用户辨认
+	Scopes     []string   `gorm:"serializer:json;type:text" json:"scopes"`     // 为空表示不限制
+	ExpiresAt  *time.Time `json:"expires_at"`                                  // 为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// IdempotencyKey 创建应用请求的幂等键，有效期内重复请求返回首次创建的应用
+type IdempotencyKey struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"uniqueIndex:idx_idempotency_user_key;not null" json:"user_id"`
+	Key       string    `gorm:"column:idempotency_key;size:128;uniqueIndex:idx_idempotency_user_key;not null" json:"key"`
+	AppID     uint      `json:"app_id"` // 为 0 表示首次请求仍在处理
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+}
+
+// AuditLog 审计日志
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	ActorID   *uint     `gorm:"index" json:"actor_id,omitempty"` // 模拟登录时实际操作的管理员
+	Action    string    `gorm:"size:64;index" json:"action"`
+	Target    string    `gorm:"size:128" json:"target"`
+	Result    string    `gorm:"size:16" json:"result"` // success/failure
+	Code      int       `json:"code"`
+	IP        string    `gorm:"size:64" json:"ip"`
 }
@@ -1,36 +1,42 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
 )
 
 // AppRepository 应用数据仓库
-type AppRepository struct{}
+type AppRepository struct {
+	db *gorm.DB
+}
 
 // NewAppRepository 创建应用仓库
-func NewAppRepository() *AppRepository {
-	return &AppRepository{}
+func NewAppRepository(db *gorm.DB) *AppRepository {
+	return &AppRepository{db: db}
 }
 
 // Create 创建应用记录
 func (r *AppRepository) Create(app *model.App) error {
-	return DB.Create(app).Error
+	return r.db.Create(app).Error
 }
 
 // Update 更新应用信息
 func (r *AppRepository) Update(app *model.App) error {
-	return DB.Save(app).Error
+	return r.db.Save(app).Error
 }
 
 // Delete 删除应用记录（软删除）
 func (r *AppRepository) Delete(id uint) error {
-	return DB.Delete(&model.App{}, id).Error
+	return r.db.Delete(&model.App{}, id).Error
 }
 
 // GetByID 按 ID 查询应用
 func (r *AppRepository) GetByID(id uint) (*model.App, error) {
 	var app model.App
-	if err := DB.First(&app, id).Error; err != nil {
+	if err := r.db.First(&app, id).Error; err != nil {
 		return nil, err
 	}
 	return &app, nil
@@ -39,16 +45,56 @@ func (r *AppRepository) GetByID(id uint) (*model.App, error) {
 // GetByUserID 按用户 ID 查询应用列表
 func (r *AppRepository) GetByUserID(userID uint) ([]model.App, error) {
 	var apps []model.App
-	if err := DB.Where("user_id = ?", userID).Find(&apps).Error; err != nil {
+	if err := r.db.Where("user_id = ?", userID).Find(&apps).Error; err != nil {
 		return nil, err
 	}
 	return apps, nil
 }
 
+// likeEscaper 转义 LIKE 通配符，避免用户输入被当作通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListByUser 分页查询用户的应用，keyword 非空时按应用名模糊匹配，返回当前页数据和总数
+func (r *AppRepository) ListByUser(userID uint, keyword string, offset, limit int) ([]model.App, int64, error) {
+	query := r.db.Model(&model.App{}).Where("user_id = ?", userID)
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+likeEscaper.Replace(keyword)+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var apps []model.App
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&apps).Error; err != nil {
+		return nil, 0, err
+	}
+	return apps, total, nil
+}
+
 // GetByUserAndName 按用户 ID 和应用名查询
 func (r *AppRepository) GetByUserAndName(userID uint, name string) (*model.App, error) {
 	var app model.App
-	if err := DB.Where("user_id = ? AND name = ?", userID, name).First(&app).Error; err != nil {
+	if err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&app).Error; err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// ListByStack 查询应用组内的所有应用
+func (r *AppRepository) ListByStack(stackID uint) ([]model.App, error) {
+	var apps []model.App
+	if err := r.db.Where("stack_id = ?", stackID).Order("id").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// GetByName 按应用名查询任一用户的应用
+func (r *AppRepository) GetByName(name string) (*model.App, error) {
+	var app model.App
+	if err := r.db.Where("name = ?", name).First(&app).Error; err != nil {
 		return nil, err
 	}
 	return &app, nil
@@ -56,10 +102,132 @@ func (r *AppRepository) GetByUserAndName(userID uint, name string) (*model.App,
 
 // UpdateStatus 更新应用状态
 func (r *AppRepository) UpdateStatus(id uint, status string) error {
-	return DB.Model(&model.App{}).Where("id = ?", id).Update("status", status).Error
+	return r.db.Model(&model.App{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// UpdateSyncedStatus 更新从 K8s 同步到的应用状态及同步时间
+func (r *AppRepository) UpdateSyncedStatus(id uint, status string, syncedAt time.Time) error {
+	return r.db.Model(&model.App{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         status,
+		"last_synced_at": syncedAt,
+	}).Error
+}
+
+// UpdateRestartBaseline 在基线仍为 previous 时更新重启告警基线，返回是否更新成功；
+// alertedAt 为 nil 时只重置基线不改告警时间。并发同步时只有一个调用能更新成功，据此保证同一次重启增长只告警一次
+func (r *AppRepository) UpdateRestartBaseline(id uint, previous, restartCount int32, alertedAt *time.Time) (bool, error) {
+	updates := map[string]interface{}{"alerted_restart_count": restartCount}
+	if alertedAt != nil {
+		updates["restart_alerted_at"] = *alertedAt
+	}
+	result := r.db.Model(&model.App{}).Where("id = ? AND alerted_restart_count = ?", id, previous).Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateSuspended 更新应用挂起状态
+func (r *AppRepository) UpdateSuspended(id uint, suspended bool) error {
+	return r.db.Model(&model.App{}).Where("id = ?", id).Update("suspended", suspended).Error
+}
+
+// UpdateScaledToZero 更新应用是否处于缩容到零状态
+func (r *AppRepository) UpdateScaledToZero(id uint, scaledToZero bool) error {
+	return r.db.Model(&model.App{}).Where("id = ?", id).Update("scaled_to_zero", scaledToZero).Error
+}
+
+// UpdateFields 按列名更新应用记录中的指定字段，通过结构体更新以使用字段的序列化器
+func (r *AppRepository) UpdateFields(app *model.App, columns ...string) error {
+	return r.db.Model(app).Select(columns).Updates(app).Error
+}
+
+// UpdateEnv 更新应用环境变量
+func (r *AppRepository) UpdateEnv(id uint, env []model.EnvVar) error {
+	// 通过结构体更新才会使用字段的 JSON 序列化器；Select 使空列表也能写入
+	return r.db.Model(&model.App{}).Where("id = ?", id).Select("env").Updates(&model.App{Env: env}).Error
 }
 
 // UpdateReplicas 更新应用副本数
 func (r *AppRepository) UpdateReplicas(id uint, replicas int) error {
-	return DB.Model(&model.App{}).Where("id = ?", id).Update("replicas", replicas).Error
+	return r.db.Model(&model.App{}).Where("id = ?", id).Update("replicas", replicas).Error
+}
+
+// UpdateScale 同时更新当前副本数与期望副本数
+func (r *AppRepository) UpdateScale(id uint, replicas, desired int) error {
+	return r.db.Model(&model.App{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"replicas":         replicas,
+		"desired_replicas": desired,
+	}).Error
+}
+
+// CountByUserID 统计用户的应用数（不含已删除）
+func (r *AppRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.App{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountByNamespace 统计命名空间内的应用数（不含已删除）
+func (r *AppRepository) CountByNamespace(namespace string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.App{}).Where("namespace = ?", namespace).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SumReplicasByUserID 统计用户所有应用的副本数之和（不含已删除）
+func (r *AppRepository) SumReplicasByUserID(userID uint) (int64, error) {
+	var sum int64
+	if err := r.db.Model(&model.App{}).Where("user_id = ?", userID).
+		Select("COALESCE(SUM(replicas), 0)").Scan(&sum).Error; err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+// StatusCount 按状态分组的应用数与副本数
+type StatusCount struct {
+	Status   string
+	Count    int64
+	Replicas int64
+}
+
+// CountByStatus 按状态分组统计应用数与副本数，userID 为 0 时统计所有用户
+func (r *AppRepository) CountByStatus(userID uint) ([]StatusCount, error) {
+	query := r.db.Model(&model.App{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var counts []StatusCount
+	if err := query.Select("status, COUNT(*) AS count, COALESCE(SUM(replicas), 0) AS replicas").
+		Group("status").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ListStale 查询最久未同步状态的未挂起应用，userID 为 0 时查询所有用户
+func (r *AppRepository) ListStale(userID uint, before time.Time, limit int) ([]model.App, error) {
+	query := r.db.Where("suspended = ?", false).
+		Where("last_synced_at IS NULL OR last_synced_at < ?", before)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var apps []model.App
+	if err := query.Order("last_synced_at ASC").Limit(limit).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// ListUnsuspendedAfter 按 ID 升序查询 ID 大于 afterID 的未挂起应用，用于分批遍历所有应用
+func (r *AppRepository) ListUnsuspendedAfter(afterID uint, limit int) ([]model.App, error) {
+	var apps []model.App
+	if err := r.db.Where("suspended = ? AND id > ?", false, afterID).Order("id ASC").Limit(limit).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	return apps, nil
 }
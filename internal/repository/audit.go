@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
+)
+
+// AuditRepository 审计日志数据仓库
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建审计日志仓库
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AuditFilter 审计日志查询条件，零值字段不参与过滤
+type AuditFilter struct {
+	UserID uint
+	Action string
+	Result string
+	Start  time.Time
+	End    time.Time
+}
+
+// Create 写入审计日志
+func (r *AuditRepository) Create(entry *model.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// List 分页查询审计日志，按时间倒序
+func (r *AuditRepository) List(filter AuditFilter, offset, limit int) ([]model.AuditLog, int64, error) {
+	query := r.db.Model(&model.AuditLog{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if !filter.Start.IsZero() {
+		query = query.Where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("created_at < ?", filter.End)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []model.AuditLog
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
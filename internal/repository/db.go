@@ -2,30 +2,164 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cuihe500/astro/internal/model"
 	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
+// models 需要迁移的数据模型
+var models = []interface{}{
+	&model.User{},
+	&model.App{},
+	&model.Stack{},
+	&model.AuditLog{},
+	&model.PersonalAccessToken{},
+	&model.IdempotencyKey{},
+}
+
+// NewDB 创建数据库连接，开启 auto_migrate 时同时执行自动迁移
+func NewDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.AutoMigrate {
+		logger.Info("已关闭自动迁移，跳过表结构同步")
+		return db, nil
+	}
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
 
-// Init 初始化数据库连接
-func Init(cfg *config.DatabaseConfig) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.Charset)
+// Open 仅创建数据库连接，不执行迁移
+func Open(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	var dialector func() gorm.Dialector
+	switch cfg.Driver {
+	case "", config.DBDriverMySQL:
+		dsn, err := buildMySQLDSN(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dialector = func() gorm.Dialector { return mysql.Open(dsn) }
+	case config.DBDriverPostgres:
+		dsn, err := buildPostgresDSN(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dialector = func() gorm.Dialector { return postgres.Open(dsn) }
+	case config.DBDriverSQLite:
+		return openSQLite(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", cfg.Driver)
+	}
+	return openWithRetry(dialector, cfg)
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// openSQLite 打开 SQLite 数据库，DBName 为空时使用内存数据库。
+// 每次调用得到相互隔离的内存数据库，测试中可各自建库互不干扰
+func openSQLite(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := cfg.DBName
+	if dsn == "" {
+		dsn = ":memory:"
+	}
+	db, err := connect(sqlite.Open(dsn))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 自动迁移
-	if err := db.AutoMigrate(&model.User{}, &model.App{}); err != nil {
-		return err
+	// 内存数据库按连接隔离，且 SQLite 不支持并发写，只保留一个长期连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	return db, nil
+}
+
+// Migrate 同步所有模型的表结构
+func Migrate(db *gorm.DB) error {
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return fmt.Errorf("解析模型失败: %w", err)
+		}
+		table := stmt.Schema.Table
+		existed := db.Migrator().HasTable(m)
 
-	DB = db
+		if err := db.AutoMigrate(m); err != nil {
+			return fmt.Errorf("迁移表 %s 失败: %w", table, err)
+		}
+		if existed {
+			logger.Info("已同步表结构", zap.String("table", table))
+		} else {
+			logger.Info("已创建表", zap.String("table", table))
+		}
+	}
 	return nil
 }
+
+// maxConnectInterval 重试间隔上限
+const maxConnectInterval = 30 * time.Second
+
+// openWithRetry 连接数据库并 Ping，失败时按指数退避重试，直到用尽尝试次数
+func openWithRetry(dialector func() gorm.Dialector, cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	attempts := cfg.ConnectAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	interval, err := time.ParseDuration(cfg.ConnectInterval)
+	if err != nil || interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		db, err := connect(dialector())
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("连接数据库失败（已尝试 %d 次）: %w", attempt, err)
+		}
+
+		logger.Warn("连接数据库失败，稍后重试",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("retry_in", interval),
+			zap.Error(err),
+		)
+		time.Sleep(interval)
+		interval = min(interval*2, maxConnectInterval)
+	}
+}
+
+// connect 打开数据库连接并确认可用
+func connect(dialector gorm.Dialector) (*gorm.DB, error) {
+	// TranslateError 将唯一索引冲突等驱动错误转换为 gorm.ErrDuplicatedKey 等通用错误
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.Ping(); err != nil {
+		if closeErr := sqlDB.Close(); closeErr != nil {
+			logger.Debug("关闭数据库连接失败", zap.Error(closeErr))
+		}
+		return nil, err
+	}
+	return db, nil
+}
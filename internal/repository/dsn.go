@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/go-sql-driver/mysql"
+)
+
+// tlsConfigName 注册到 MySQL 驱动的自定义 TLS 配置名
+const tlsConfigName = "astro"
+
+// mysqlParamNames MySQL 驱动区分大小写的参数名，配置加载会将 map 键转为小写，这里还原
+var mysqlParamNames = map[string]string{
+	"allowallfiles":            "allowAllFiles",
+	"allowcleartextpasswords":  "allowCleartextPasswords",
+	"allowfallbacktoplaintext": "allowFallbackToPlaintext",
+	"allownativepasswords":     "allowNativePasswords",
+	"allowoldpasswords":        "allowOldPasswords",
+	"checkconnliveness":        "checkConnLiveness",
+	"clientfoundrows":          "clientFoundRows",
+	"columnswithalias":         "columnsWithAlias",
+	"interpolateparams":        "interpolateParams",
+	"maxallowedpacket":         "maxAllowedPacket",
+	"multistatements":          "multiStatements",
+	"readtimeout":              "readTimeout",
+	"rejectreadonly":           "rejectReadOnly",
+	"serverpubkey":             "serverPubKey",
+	"writetimeout":             "writeTimeout",
+}
+
+// buildMySQLDSN 根据配置构建 MySQL DSN
+func buildMySQLDSN(cfg *config.DatabaseConfig) (string, error) {
+	params := url.Values{}
+	params.Set("charset", cfg.Charset)
+	params.Set("parseTime", "True")
+	if cfg.ParseTime != nil && !*cfg.ParseTime {
+		params.Set("parseTime", "False")
+	}
+	params.Set("loc", "Local")
+	if cfg.Loc != "" {
+		params.Set("loc", cfg.Loc)
+	}
+
+	switch cfg.TLS {
+	case "", config.DBTLSDisable:
+	case config.DBTLSRequire:
+		// 仅加密，不校验服务端证书
+		params.Set("tls", "skip-verify")
+	case config.DBTLSVerifyCA:
+		if cfg.CACert == "" {
+			params.Set("tls", "true")
+			break
+		}
+		if err := registerCACert(cfg.CACert); err != nil {
+			return "", err
+		}
+		params.Set("tls", tlsConfigName)
+	default:
+		return "", fmt.Errorf("不支持的数据库 TLS 模式: %s", cfg.TLS)
+	}
+
+	for key, value := range cfg.Params {
+		if name, ok := mysqlParamNames[strings.ToLower(key)]; ok {
+			key = name
+		}
+		params.Set(key, value)
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, params.Encode()), nil
+}
+
+// buildPostgresDSN 根据配置构建 PostgreSQL DSN（key=value 格式）
+func buildPostgresDSN(cfg *config.DatabaseConfig) (string, error) {
+	params := map[string]string{
+		"host":     cfg.Host,
+		"port":     strconv.Itoa(cfg.Port),
+		"user":     cfg.User,
+		"password": cfg.Password,
+		"dbname":   cfg.DBName,
+		"sslmode":  "disable",
+	}
+	// 默认沿用服务端会话时区，显式配置 loc 时才设置 TimeZone
+	if cfg.Loc != "" && cfg.Loc != "Local" {
+		params["TimeZone"] = cfg.Loc
+	}
+
+	switch cfg.TLS {
+	case "", config.DBTLSDisable:
+	case config.DBTLSRequire, config.DBTLSVerifyCA:
+		params["sslmode"] = cfg.TLS
+		if cfg.TLS == config.DBTLSVerifyCA && cfg.CACert != "" {
+			params["sslrootcert"] = cfg.CACert
+		}
+	default:
+		return "", fmt.Errorf("不支持的数据库 TLS 模式: %s", cfg.TLS)
+	}
+
+	for key, value := range cfg.Params {
+		params[key] = value
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+quotePostgresValue(params[key]))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// quotePostgresValue 按 libpq 规则为含空格或引号的值加引号
+func quotePostgresValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// registerCACert 使用指定 CA 证书注册 TLS 配置，用于校验云数据库的服务端证书
+func registerCACert(path string) error {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取数据库 CA 证书失败: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("解析数据库 CA 证书失败: %s", path)
+	}
+	return mysql.RegisterTLSConfig(tlsConfigName, &tls.Config{RootCAs: pool})
+}
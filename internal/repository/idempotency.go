@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve 占用幂等键，先清理该键已过期的记录；键已被占用时返回 gorm.ErrDuplicatedKey
+func (r *IdempotencyRepository) Reserve(userID uint, key string, expiresAt time.Time) (*model.IdempotencyKey, error) {
+	if err := r.db.Where("user_id = ? AND idempotency_key = ? AND expires_at < ?", userID, key, time.Now()).
+		Delete(&model.IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	record := &model.IdempotencyKey{UserID: userID, Key: key, ExpiresAt: expiresAt}
+	if err := r.db.Create(record).Error; err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Get 查询用户的幂等键
+func (r *IdempotencyRepository) Get(userID uint, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	if err := r.db.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SetAppID 记录幂等键对应创建的应用
+func (r *IdempotencyRepository) SetAppID(id, appID uint) error {
+	return r.db.Model(&model.IdempotencyKey{}).Where("id = ?", id).Update("app_id", appID).Error
+}
+
+// Delete 删除幂等键，创建失败时释放以便客户端重试
+func (r *IdempotencyRepository) Delete(id uint) error {
+	return r.db.Delete(&model.IdempotencyKey{}, id).Error
+}
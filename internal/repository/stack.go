@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
+)
+
+// StackRepository 应用组数据仓库
+type StackRepository struct {
+	db *gorm.DB
+}
+
+// NewStackRepository 创建应用组仓库
+func NewStackRepository(db *gorm.DB) *StackRepository {
+	return &StackRepository{db: db}
+}
+
+// Create 创建应用组记录
+func (r *StackRepository) Create(stack *model.Stack) error {
+	return r.db.Create(stack).Error
+}
+
+// Delete 删除应用组记录（软删除）
+func (r *StackRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Stack{}, id).Error
+}
+
+// GetByID 按 ID 查询应用组
+func (r *StackRepository) GetByID(id uint) (*model.Stack, error) {
+	var stack model.Stack
+	if err := r.db.First(&stack, id).Error; err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
+
+// GetByUserAndName 按用户 ID 和应用组名查询
+func (r *StackRepository) GetByUserAndName(userID uint, name string) (*model.Stack, error) {
+	var stack model.Stack
+	if err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&stack).Error; err != nil {
+		return nil, err
+	}
+	return &stack, nil
+}
+
+// ListByUser 分页查询用户的应用组，返回当前页数据和总数
+func (r *StackRepository) ListByUser(userID uint, offset, limit int) ([]model.Stack, int64, error) {
+	query := r.db.Model(&model.Stack{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var stacks []model.Stack
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&stacks).Error; err != nil {
+		return nil, 0, err
+	}
+	return stacks, total, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+// Create 创建访问令牌
+func (r *TokenRepository) Create(token *model.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// ListByUserID 获取用户的所有访问令牌
+func (r *TokenRepository) ListByUserID(userID uint) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	if err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// GetByHash 通过令牌哈希查询
+func (r *TokenRepository) GetByHash(hash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Delete 删除用户的访问令牌，返回是否删除了记录
+func (r *TokenRepository) Delete(id, userID uint) (bool, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&model.PersonalAccessToken{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateLastUsed 更新令牌最近使用时间
+func (r *TokenRepository) UpdateLastUsed(id uint, t time.Time) error {
+	return r.db.Model(&model.PersonalAccessToken{}).Where("id = ?", id).UpdateColumn("last_used_at", t).Error
+}
@@ -2,23 +2,26 @@ package repository
 
 import (
 	"github.com/cuihe500/astro/internal/model"
+	"gorm.io/gorm"
 )
 
-type UserRepository struct{}
+type UserRepository struct {
+	db *gorm.DB
+}
 
-func NewUserRepository() *UserRepository {
-	return &UserRepository{}
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
 }
 
 // CreateUser 创建用户
 func (r *UserRepository) CreateUser(user *model.User) error {
-	return DB.Create(user).Error
+	return r.db.Create(user).Error
 }
 
 // GetUserByUsername 通过用户名查询用户
 func (r *UserRepository) GetUserByUsername(username string) (*model.User, error) {
 	var user model.User
-	if err := DB.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -27,8 +30,43 @@ func (r *UserRepository) GetUserByUsername(username string) (*model.User, error)
 // GetUserByUUID 通过 UUID 查询用户
 func (r *UserRepository) GetUserByUUID(uuid string) (*model.User, error) {
 	var user model.User
-	if err := DB.Where("uuid = ?", uuid).First(&user).Error; err != nil {
+	if err := r.db.Where("uuid = ?", uuid).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByID 通过 ID 查询用户
+func (r *UserRepository) GetUserByID(id uint) (*model.User, error) {
+	var user model.User
+	if err := r.db.First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+// GetUserByEmail 通过邮箱查询用户
+func (r *UserRepository) GetUserByEmail(email string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateEmail 更新用户邮箱
+func (r *UserRepository) UpdateEmail(id uint, email string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("email", email).Error
+}
+
+// UpdateTeam 更新用户所属团队
+func (r *UserRepository) UpdateTeam(id uint, team string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("team", team).Error
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (r *UserRepository) Transaction(fn func(repo *UserRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&UserRepository{db: tx})
+	})
+}
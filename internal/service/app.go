@@ -1,87 +1,345 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/cuihe500/astro/internal/container"
 	"github.com/cuihe500/astro/internal/k8s"
 	"github.com/cuihe500/astro/internal/model"
 	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
 	"github.com/cuihe500/astro/pkg/errcode"
 	"gorm.io/gorm"
 )
 
 // AppService 应用服务
 type AppService struct {
-	repo    *repository.AppRepository
-	adapter k8s.AppAdapter
+	cfg             *config.Config
+	repo            *repository.AppRepository
+	userRepo        *repository.UserRepository
+	idempotencyRepo *repository.IdempotencyRepository
+	stackRepo       *repository.StackRepository
+	adapter         k8s.AppAdapter
+	breaker         *k8s.CircuitBreaker
+	// createSlots 每个用户同时进行的创建数；只有 AppHandler 持有的实例会创建应用，名额在该实例内共享
+	createSlots *userSlots
 }
 
 // NewAppService 创建应用服务
-func NewAppService() *AppService {
+func NewAppService(c *container.Container) *AppService {
 	return &AppService{
-		repo:    repository.NewAppRepository(),
-		adapter: k8s.Adapter,
+		cfg:             c.Config,
+		repo:            repository.NewAppRepository(c.DB),
+		userRepo:        repository.NewUserRepository(c.DB),
+		idempotencyRepo: repository.NewIdempotencyRepository(c.DB),
+		stackRepo:       repository.NewStackRepository(c.DB),
+		adapter:         c.Adapter,
+		breaker:         c.Breaker,
+		createSlots:     newUserSlots(c.Config.Quota.MaxConcurrentCreates),
 	}
 }
 
+// StorageOption 有状态应用持久卷选项
+type StorageOption struct {
+	Size         string
+	StorageClass string
+	MountPath    string
+}
+
+// DNSOption Pod 自定义 DNS 配置
+type DNSOption struct {
+	Nameservers []string
+	Searches    []string
+	Options     map[string]string
+}
+
 // CreateAppRequest 创建应用请求
 type CreateAppRequest struct {
-	Name     string
-	Image    string
-	Replicas int
-	Port     int
-	UserID   uint
+	Name                          string
+	Image                         string
+	Kind                          string // 留空为 deployment
+	Schedule                      string
+	RestartPolicy                 string // 仅 cronjob 生效
+	BackoffLimit                  *int32 // 仅 cronjob 生效
+	Replicas                      *int   // 为 nil 时使用配置默认值
+	Port                          int
+	Headless                      bool
+	Spread                        string         // node/zone，留空不限制
+	Storage                       *StorageOption // 仅 statefulset 使用
+	ServiceAnnotations            map[string]string
+	DeploymentAnnotations         map[string]string
+	SecurityContext               *SecurityContextOverride
+	ImagePullPolicy               string // 留空使用配置默认值
+	RevisionHistoryLimit          *int32 // 为 nil 时使用配置默认值
+	ProgressDeadlineSeconds       *int32 // 为 nil 时使用配置默认值
+	MinReadySeconds               *int32 // 为 nil 时使用配置默认值
+	TerminationGracePeriodSeconds *int64 // 为 nil 时使用 K8s 默认值
+	PreStopCommand                []string
+	Env                           []model.EnvVar
+	WorkingDir                    string
+	Hostname                      string
+	Subdomain                     string
+	ServiceAccountName            string          // 留空使用命名空间的 default
+	CreateServiceAccount          bool            // ServiceAccount 不存在时自动创建
+	DNSPolicy                     string          // 留空使用 K8s 默认值
+	DNS                           *DNSOption      // 为 nil 时不设置
+	Source                        *SourceOption   // 不为空时从 Git 仓库构建镜像，忽略 Image
+	Metadata                      json.RawMessage // 仅保存在数据库，不下发到 K8s
+	StackID                       *uint           // 所属应用组，由 CreateStack 设置
+	IdempotencyKey                string          // 不为空时重复请求返回首次创建的应用
+	UserID                        uint
 }
 
-// CreateApp 创建应用
+// CreateApp 创建应用，指定幂等键时可安全重试
 func (s *AppService) CreateApp(ctx context.Context, req CreateAppRequest) (*model.App, error) {
-	// 检查应用名是否重复
-	_, err := s.repo.GetByUserAndName(req.UserID, req.Name)
-	if err == nil {
-		return nil, errcode.New(errcode.ErrAppExists)
+	if req.IdempotencyKey != "" {
+		return s.createAppIdempotent(ctx, req)
 	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	return s.createApp(ctx, req)
+}
+
+// createApp 创建应用，单个应用与应用组中的每个应用都经由这里创建
+func (s *AppService) createApp(ctx context.Context, req CreateAppRequest) (*model.App, error) {
+	// 限制单个用户的并发创建，避免脚本批量创建压垮 K8s API
+	if !s.createSlots.acquire(req.UserID) {
+		return nil, errcode.New(errcode.ErrTooManyCreates)
+	}
+	defer s.createSlots.release(req.UserID)
+
+	if err := checkPort(req.Port); err != nil {
+		return nil, err
+	}
+
+	// 检查镜像策略，构建产物推送到平台配置的仓库，无需检查
+	if req.Source != nil {
+		if !s.cfg.Build.Enabled {
+			return nil, errcode.New(errcode.ErrBuildDisabled)
+		}
+	} else if err := checkImagePolicy(&s.cfg.Image, req.Image); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAppName(req.UserID, req.Name); err != nil {
+		return nil, err
+	}
+
+	replicas := s.cfg.App.DefaultReplicas
+	if req.Replicas != nil {
+		replicas = *req.Replicas
+	}
+	if err := s.checkReplicas(replicas); err != nil {
+		return nil, err
+	}
+
+	// 持有配额锁直到写入应用记录，避免同一用户的并发创建同时通过配额检查
+	releaseQuota := sync.OnceFunc(quotaLocks.lock(req.UserID))
+	defer releaseQuota()
+	if err := s.checkQuota(req.UserID, 1, replicas); err != nil {
+		return nil, err
 	}
 
 	// 构建命名空间
-	namespace := fmt.Sprintf("astro-user-%d", req.UserID)
+	namespace, err := s.resolveNamespace(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 资源归属注解需要用户 UUID
+	owner, err := s.userRepo.GetUserByID(req.UserID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	image, status := req.Image, "pending"
+	if req.Source != nil {
+		image, status = s.buildImage(namespace, req.Name), appStatusBuilding
+	}
+
+	kind := req.Kind
+	if kind == "" {
+		kind = k8s.KindDeployment
+	}
+
+	imagePullPolicy := req.ImagePullPolicy
+	if imagePullPolicy == "" {
+		imagePullPolicy = s.cfg.Kubernetes.ImagePullPolicy
+	}
+
+	revisionHistoryLimit := req.RevisionHistoryLimit
+	if revisionHistoryLimit == nil {
+		revisionHistoryLimit = s.cfg.Kubernetes.RevisionHistoryLimit
+	}
+	progressDeadlineSeconds := req.ProgressDeadlineSeconds
+	if progressDeadlineSeconds == nil {
+		progressDeadlineSeconds = s.cfg.Kubernetes.ProgressDeadlineSeconds
+	}
+	minReadySeconds := s.cfg.Kubernetes.MinReadySeconds
+	if req.MinReadySeconds != nil {
+		minReadySeconds = *req.MinReadySeconds
+	}
+	// K8s 要求发布期限大于 minReadySeconds，否则每次发布都会超时
+	if kind != k8s.KindCronJob && progressDeadlineSeconds != nil && minReadySeconds >= *progressDeadlineSeconds {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("min_ready_seconds（%d）必须小于滚动更新超时 progress_deadline_seconds（%d）", minReadySeconds, *progressDeadlineSeconds))
+	}
 
 	// 创建数据库记录
 	app := &model.App{
-		Name:      req.Name,
-		Image:     req.Image,
-		Replicas:  req.Replicas,
-		Status:    "pending",
-		UserID:    req.UserID,
-		Namespace: namespace,
+		Name:                          req.Name,
+		Image:                         image,
+		Kind:                          kind,
+		Schedule:                      req.Schedule,
+		RestartPolicy:                 req.RestartPolicy,
+		BackoffLimit:                  req.BackoffLimit,
+		Replicas:                      replicas,
+		DesiredReplicas:               replicas,
+		Status:                        status,
+		UserID:                        req.UserID,
+		Namespace:                     namespace,
+		ServiceAnnotations:            req.ServiceAnnotations,
+		DeploymentAnnotations:         req.DeploymentAnnotations,
+		ImagePullPolicy:               imagePullPolicy,
+		Headless:                      req.Headless || kind == k8s.KindStatefulSet,
+		Spread:                        req.Spread,
+		TerminationGracePeriodSeconds: req.TerminationGracePeriodSeconds,
+		PreStopCommand:                req.PreStopCommand,
+		Env:                           req.Env,
+		WorkingDir:                    req.WorkingDir,
+		Hostname:                      req.Hostname,
+		Subdomain:                     req.Subdomain,
+		ServiceAccountName:            req.ServiceAccountName,
+		DNSPolicy:                     req.DNSPolicy,
+		StackID:                       req.StackID,
+	}
+	if metadata := bytes.TrimSpace(req.Metadata); len(metadata) > 0 && string(metadata) != "null" {
+		app.Metadata = metadata
+	}
+	if req.DNS != nil {
+		app.DNSNameservers = req.DNS.Nameservers
+		app.DNSSearches = req.DNS.Searches
+		app.DNSOptions = req.DNS.Options
+	}
+	if req.Storage != nil {
+		app.StorageSize = req.Storage.Size
+		app.StorageClass = req.Storage.StorageClass
+		app.StorageMountPath = req.Storage.MountPath
+	}
+	if req.Source != nil {
+		app.SourceGitURL = req.Source.GitURL
+		app.SourceRef = req.Source.Ref
+		app.SourceDockerfile = req.Source.Dockerfile
 	}
 	if err := s.repo.Create(app); err != nil {
 		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
 	}
+	releaseQuota()
 
 	// 调用 K8s Adapter 创建应用
 	spec := k8s.AppSpec{
-		Name:      req.Name,
-		Namespace: namespace,
-		Image:     req.Image,
-		Replicas:  int32(req.Replicas),
-		Port:      int32(req.Port),
+		Name:                          req.Name,
+		Namespace:                     namespace,
+		Image:                         image,
+		Kind:                          kind,
+		Schedule:                      req.Schedule,
+		RestartPolicy:                 req.RestartPolicy,
+		BackoffLimit:                  req.BackoffLimit,
+		Replicas:                      int32(replicas),
+		Port:                          int32(req.Port),
+		Headless:                      req.Headless,
+		Spread:                        req.Spread,
+		ServiceAnnotations:            req.ServiceAnnotations,
+		DeploymentAnnotations:         req.DeploymentAnnotations,
+		ImagePullPolicy:               imagePullPolicy,
+		Security:                      resolveSecurityOptions(&s.cfg.Kubernetes.SecurityContext, req.SecurityContext),
+		RevisionHistoryLimit:          revisionHistoryLimit,
+		ProgressDeadlineSeconds:       progressDeadlineSeconds,
+		MinReadySeconds:               minReadySeconds,
+		TerminationMessagePolicy:      s.cfg.Kubernetes.TerminationMessagePolicy,
+		TerminationMessagePath:        s.cfg.Kubernetes.TerminationMessagePath,
+		TerminationGracePeriodSeconds: req.TerminationGracePeriodSeconds,
+		PreStopCommand:                req.PreStopCommand,
+		Env:                           *toK8sEnv(req.Env),
+		WorkingDir:                    req.WorkingDir,
+		Hostname:                      req.Hostname,
+		Subdomain:                     req.Subdomain,
+		ServiceAccountName:            req.ServiceAccountName,
+		CreateServiceAccount:          req.CreateServiceAccount,
+		DNSPolicy:                     req.DNSPolicy,
+		DNSNameservers:                app.DNSNameservers,
+		DNSSearches:                   app.DNSSearches,
+		DNSOptions:                    app.DNSOptions,
+		OwnerID:                       owner.ID,
+		OwnerUUID:                     owner.UUID,
+		AppID:                         app.ID,
+	}
+	if req.Storage != nil {
+		spec.StorageSize = req.Storage.Size
+		spec.StorageClass = req.Storage.StorageClass
+		spec.StorageMountPath = req.Storage.MountPath
 	}
+
+	// 源码构建的应用在构建完成后再创建工作负载
+	if req.Source != nil {
+		if err := s.startBuild(ctx, app, spec); err != nil {
+			_ = s.repo.Delete(app.ID)
+			return nil, k8sError(err)
+		}
+		return app, nil
+	}
+
 	if err := s.adapter.CreateApp(ctx, spec); err != nil {
 		// 创建 K8s 资源失败，删除数据库记录
 		_ = s.repo.Delete(app.ID)
+		if errors.Is(err, k8s.ErrNamespaceTerminating) {
+			return nil, errcode.NewWithMsg(errcode.ErrK8s, err.Error())
+		}
+		// 配额、权限、规格等可识别的原因返回对应错误码与详情
+		if e := errcode.FromError(k8sError(err)); e.Code != errcode.ErrK8sOperation {
+			return nil, e
+		}
 		return nil, errcode.NewWithMsg(errcode.ErrAppCreateFailed, err.Error())
 	}
 
 	// 异步同步状态
-	go s.syncAppStatus(context.Background(), app.ID, app.Name, namespace)
+	go s.syncAppStatus(context.Background(), app)
 
 	return app, nil
 }
 
+// checkAppName 检查应用名是否重复，范围由 app.name_scope 决定
+func (s *AppService) checkAppName(userID uint, name string) error {
+	var err error
+	if s.cfg.App.NameScope == config.AppNameScopeGlobal {
+		_, err = s.repo.GetByName(name)
+	} else {
+		_, err = s.repo.GetByUserAndName(userID, name)
+	}
+	if err == nil {
+		return errcode.New(errcode.ErrAppExists)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// IsAppNameAvailable 应用名是否可用，重复范围与创建时一致，由 app.name_scope 决定
+func (s *AppService) IsAppNameAvailable(ctx context.Context, userID uint, name string) (bool, error) {
+	err := s.checkAppName(userID, name)
+	if err == nil {
+		return true, nil
+	}
+	if errcode.FromError(err).Code == errcode.ErrAppExists {
+		return false, nil
+	}
+	return false, err
+}
+
 // DeleteApp 删除应用
 func (s *AppService) DeleteApp(ctx context.Context, appID, userID uint) error {
 	app, err := s.repo.GetByID(appID)
@@ -97,9 +355,13 @@ func (s *AppService) DeleteApp(ctx context.Context, appID, userID uint) error {
 		return errcode.New(errcode.ErrForbidden)
 	}
 
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
+
 	// 删除 K8s 资源
-	if err := s.adapter.DeleteApp(ctx, app.Name, app.Namespace); err != nil {
-		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+	if err := s.adapter.DeleteApp(ctx, app.Name, app.Namespace, app.Kind); err != nil {
+		return k8sError(err)
 	}
 
 	// 删除数据库记录
@@ -110,105 +372,435 @@ func (s *AppService) DeleteApp(ctx context.Context, appID, userID uint) error {
 	return nil
 }
 
-// StartApp 启动应用
-func (s *AppService) StartApp(ctx context.Context, appID, userID uint) error {
+// StartApp 启动应用，waitTimeout 大于 0 时阻塞等待应用就绪
+func (s *AppService) StartApp(ctx context.Context, appID, userID uint, waitTimeout time.Duration) error {
+	// 持有配额锁直到写入副本数，避免并发调整副本数同时通过配额检查
+	releaseQuota := sync.OnceFunc(quotaLocks.lock(userID))
+	defer releaseQuota()
 	app, err := s.getAppWithPermission(appID, userID)
 	if err != nil {
 		return err
 	}
+	if waitTimeout > 0 && app.Kind == k8s.KindCronJob {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持等待就绪")
+	}
 
-	// 恢复到原来的副本数（至少为1）
-	replicas := app.Replicas
+	// 恢复到期望副本数；早期创建的应用没有期望副本数，回退到当前副本数，至少为 1
+	replicas := app.DesiredReplicas
+	if replicas == 0 {
+		replicas = app.Replicas
+	}
 	if replicas == 0 {
 		replicas = 1
 	}
+	if err := s.checkReplicas(replicas); err != nil {
+		return err
+	}
+
+	if err := s.checkQuota(userID, 0, replicas-app.Replicas); err != nil {
+		return err
+	}
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
 
 	if err := s.adapter.ScaleApp(ctx, app.Name, app.Namespace, int32(replicas)); err != nil {
-		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+		return k8sError(err)
 	}
 
 	_ = s.repo.UpdateStatus(appID, "starting")
-	go s.syncAppStatus(context.Background(), appID, app.Name, app.Namespace)
+	_ = s.repo.UpdateScale(appID, replicas, replicas)
+	if app.ScaledToZero {
+		_ = s.repo.UpdateScaledToZero(appID, false)
+	}
+	releaseQuota()
+	if waitTimeout > 0 {
+		return s.waitForReady(ctx, app, waitTimeout)
+	}
+	go s.syncAppStatus(context.Background(), app)
 
 	return nil
 }
 
 // StopApp 停止应用
 func (s *AppService) StopApp(ctx context.Context, appID, userID uint) error {
+	return s.scaleDown(ctx, appID, userID, false)
+}
+
+// ScaleToZeroApp 将应用缩容到零，与停止相同但记录为缩容到零状态，之后可通过 WakeApp 恢复到期望副本数
+func (s *AppService) ScaleToZeroApp(ctx context.Context, appID, userID uint) error {
+	return s.scaleDown(ctx, appID, userID, true)
+}
+
+// WakeApp 唤醒已停止或缩容到零的应用，恢复到期望副本数并返回唤醒后的应用，
+// 与 StartApp 的区别是只接受处于停止状态的应用；waitTimeout 大于 0 时阻塞等待应用就绪
+func (s *AppService) WakeApp(ctx context.Context, appID, userID uint, waitTimeout time.Duration) (*model.App, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Status != "stopped" && !app.ScaledToZero {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("只能唤醒已停止的应用，当前状态为 %s", app.Status))
+	}
+	if err := s.StartApp(ctx, appID, userID, waitTimeout); err != nil {
+		return nil, err
+	}
+
+	app, err = s.repo.GetByID(appID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return app, nil
+}
+
+// scaleDown 将副本数调整为 0，scaledToZero 标记是否为可唤醒的缩容到零状态
+func (s *AppService) scaleDown(ctx context.Context, appID, userID uint, scaledToZero bool) error {
 	app, err := s.getAppWithPermission(appID, userID)
 	if err != nil {
 		return err
 	}
+	if scaledToZero && app.Kind == k8s.KindCronJob {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持缩容到零")
+	}
+
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
 
 	if err := s.adapter.ScaleApp(ctx, app.Name, app.Namespace, 0); err != nil {
-		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+		return k8sError(err)
 	}
 
+	// 只清零当前副本数，保留期望副本数供启动时恢复；
+	// 旧数据没有期望副本数时，先把停止前的副本数记为期望副本数
+	desired := app.DesiredReplicas
+	if desired == 0 {
+		desired = app.Replicas
+	}
 	_ = s.repo.UpdateStatus(appID, "stopped")
-	_ = s.repo.UpdateReplicas(appID, 0)
+	_ = s.repo.UpdateScale(appID, 0, desired)
+	if app.ScaledToZero != scaledToZero {
+		_ = s.repo.UpdateScaledToZero(appID, scaledToZero)
+	}
 
 	return nil
 }
 
-// RestartApp 重启应用
-func (s *AppService) RestartApp(ctx context.Context, appID, userID uint) error {
+// ScaleApp 调整应用副本数，同时更新当前与期望副本数；0 等同于停止但不改变期望副本数
+func (s *AppService) ScaleApp(ctx context.Context, appID, userID uint, replicas int) error {
+	// 持有配额锁直到写入副本数，避免并发调整副本数同时通过配额检查
+	defer quotaLocks.lock(userID)()
 	app, err := s.getAppWithPermission(appID, userID)
 	if err != nil {
 		return err
 	}
+	if app.Kind == k8s.KindCronJob {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持调整副本数")
+	}
+	if replicas == 0 {
+		return s.StopApp(ctx, appID, userID)
+	}
+	if err := s.checkReplicas(replicas); err != nil {
+		return err
+	}
+	if err := s.checkQuota(userID, 0, replicas-app.Replicas); err != nil {
+		return err
+	}
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
 
-	if err := s.adapter.RestartApp(ctx, app.Name, app.Namespace); err != nil {
-		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+	if err := s.adapter.ScaleApp(ctx, app.Name, app.Namespace, int32(replicas)); err != nil {
+		return k8sError(err)
+	}
+
+	if err := s.repo.UpdateScale(appID, replicas, replicas); err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if app.ScaledToZero {
+		_ = s.repo.UpdateScaledToZero(appID, false)
+	}
+	go s.syncAppStatus(context.Background(), app)
+
+	return nil
+}
+
+// 重启方式
+const (
+	// RestartRolling 滚动重启，新 Pod 就绪后才替换旧 Pod，多副本时不中断服务
+	RestartRolling = "rolling"
+	// RestartRecreate 立即删除所有 Pod 后重建，新 Pod 就绪前应用不可用
+	RestartRecreate = "recreate"
+)
+
+// RestartApp 按 mode 重启应用，waitTimeout 大于 0 时阻塞等待应用就绪
+// 仅当镜像拉取策略为 Always 时，重启才会拉取重新推送的同名 tag 镜像
+func (s *AppService) RestartApp(ctx context.Context, appID, userID uint, mode string, waitTimeout time.Duration) error {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return err
+	}
+	if app.Kind == k8s.KindCronJob {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持重启")
+	}
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
+
+	switch mode {
+	case RestartRolling:
+		err = s.adapter.RestartApp(ctx, app.Name, app.Namespace)
+	case RestartRecreate:
+		err = s.adapter.RecreateAppPods(ctx, app.Name, app.Namespace)
+	default:
+		return errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("不支持的重启方式: %s", mode))
+	}
+	if err != nil {
+		return k8sError(err)
 	}
 
 	_ = s.repo.UpdateStatus(appID, "restarting")
-	go s.syncAppStatus(context.Background(), appID, app.Name, app.Namespace)
+	if waitTimeout > 0 {
+		return s.waitForReady(ctx, app, waitTimeout)
+	}
+	go s.syncAppStatus(context.Background(), app)
 
 	return nil
 }
 
-// GetApps 获取用户的应用列表
-func (s *AppService) GetApps(ctx context.Context, userID uint) ([]model.App, error) {
-	apps, err := s.repo.GetByUserID(userID)
+// AppListItem 应用列表项
+type AppListItem struct {
+	model.App
+	StatusStale bool `json:"status_stale"` // 为 true 表示集群暂不可达，status 为上次同步结果
+}
+
+// GetApps 分页获取用户的应用列表，keyword 非空时按应用名模糊匹配
+// 列表始终返回数据库中的数据，并在后台异步同步状态；集群熔断期间不再发起同步
+func (s *AppService) GetApps(ctx context.Context, userID uint, keyword string, page, pageSize int) ([]AppListItem, int64, error) {
+	apps, total, err := s.repo.ListByUser(userID, keyword, (page-1)*pageSize, pageSize)
 	if err != nil {
-		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		return nil, 0, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
 	}
 
-	// 异步同步所有应用状态
-	for _, app := range apps {
-		go s.syncAppStatus(context.Background(), app.ID, app.Name, app.Namespace)
+	stale := s.breaker.Open()
+	items := make([]AppListItem, 0, len(apps))
+	for i := range apps {
+		go s.syncAppStatus(context.Background(), &apps[i])
+		items = append(items, AppListItem{App: apps[i], StatusStale: stale})
 	}
 
-	return apps, nil
+	return items, total, nil
+}
+
+// liveStatusTimeout 详情接口同步查询 K8s 状态的超时时间
+const liveStatusTimeout = 5 * time.Second
+
+// AppDetail 应用详情，合并数据库中的规格与 K8s 实时状态
+type AppDetail struct {
+	model.App
+	Live        *k8s.AppStatus   `json:"live,omitempty"`  // 实时状态（Pod、就绪副本数等），K8s 不可达时为空
+	StatusStale bool             `json:"status_stale"`    // 为 true 表示未能获取实时状态，status 字段为上次同步结果
+	Build       *k8s.BuildStatus `json:"build,omitempty"` // 最近一次源码构建的状态，构建 Job 已过期清理时为空
 }
 
-// GetApp 获取应用详情
-func (s *AppService) GetApp(ctx context.Context, appID, userID uint) (*model.App, error) {
+// GetApp 获取应用详情，同步查询 K8s 实时状态，K8s 不可达时返回数据库中的数据并标记 status_stale
+func (s *AppService) GetApp(ctx context.Context, appID, userID uint) (*AppDetail, error) {
 	app, err := s.getAppWithPermission(appID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 同步状态后重新查询
-	s.syncAppStatus(ctx, appID, app.Name, app.Namespace)
-	return s.repo.GetByID(appID)
+	ctx, cancel := context.WithTimeout(ctx, liveStatusTimeout)
+	defer cancel()
+
+	var build *k8s.BuildStatus
+	if app.BuildJob != "" {
+		build, _ = s.adapter.GetBuildStatus(ctx, app.BuildJob, app.Namespace)
+	}
+	// 构建阶段集群中还没有工作负载，只返回构建状态
+	if isBuildStatus(app.Status) {
+		return &AppDetail{App: *app, Build: build}, nil
+	}
+
+	status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return &AppDetail{App: *app, StatusStale: true, Build: build}, nil
+	}
+
+	// 挂起的应用只展示实时状态，不写回数据库
+	if !app.Suspended {
+		now := time.Now()
+		_ = s.repo.UpdateSyncedStatus(app.ID, status.Status, now)
+		app.Status = status.Status
+		app.LastSyncedAt = &now
+		if status.Replicas > 0 {
+			_ = s.repo.UpdateReplicas(app.ID, int(status.Replicas))
+			app.Replicas = int(status.Replicas)
+		}
+	}
+	return &AppDetail{App: *app, Live: status, Build: build}, nil
 }
 
-// GetAppLogs 获取应用日志
-func (s *AppService) GetAppLogs(ctx context.Context, appID, userID uint, lines int64) (string, error) {
+// GetAppLogs 获取应用日志，allPods 为 true 时聚合所有 Pod 的日志，否则只取第一个 Pod
+func (s *AppService) GetAppLogs(ctx context.Context, appID, userID uint, lines int64, allPods bool) (string, error) {
 	app, err := s.getAppWithPermission(appID, userID)
 	if err != nil {
 		return "", err
 	}
 
-	logs, err := s.adapter.GetAppLogs(ctx, app.Name, app.Namespace, lines)
+	getLogs := s.adapter.GetAppLogs
+	if allPods {
+		getLogs = s.adapter.GetAllPodLogs
+	}
+	logs, err := getLogs(ctx, app.Name, app.Namespace, lines)
 	if err != nil {
-		return "", errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+		return "", k8sError(err)
 	}
 
 	return logs, nil
 }
 
+// FollowAppLogs 持续跟踪应用日志，ctx 取消后停止
+func (s *AppService) FollowAppLogs(ctx context.Context, appID, userID uint, lines int64) (<-chan string, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineCh, err := s.adapter.FollowAppLogs(ctx, app.Name, app.Namespace, lines)
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	return lineCh, nil
+}
+
+// SuspendApp 挂起应用，挂起期间平台不再同步其状态，便于手动调试集群资源
+func (s *AppService) SuspendApp(ctx context.Context, appID, userID uint) error {
+	return s.setSuspended(appID, userID, true)
+}
+
+// ResumeApp 恢复已挂起的应用
+func (s *AppService) ResumeApp(ctx context.Context, appID, userID uint) error {
+	return s.setSuspended(appID, userID, false)
+}
+
+// setSuspended 更新应用挂起状态
+func (s *AppService) setSuspended(appID, userID uint, suspended bool) error {
+	if _, err := s.getAppWithPermission(appID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateSuspended(appID, suspended); err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// WatchApp 监听应用状态变化，ctx 取消后停止监听
+func (s *AppService) WatchApp(ctx context.Context, appID, userID uint) (<-chan *k8s.AppStatus, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusCh, err := s.adapter.WatchApp(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	return statusCh, nil
+}
+
+// ListRevisions 获取应用历史版本
+func (s *AppService) ListRevisions(ctx context.Context, appID, userID uint) ([]k8s.Revision, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Kind != k8s.KindDeployment {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "仅 Deployment 应用支持版本回滚")
+	}
+
+	revisions, err := s.adapter.ListRevisions(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	return revisions, nil
+}
+
+// RollbackApp 回滚应用到指定版本并返回回滚后的状态，toRevision 为 0 表示上一个版本
+func (s *AppService) RollbackApp(ctx context.Context, appID, userID uint, toRevision int64) (*k8s.AppStatus, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Kind != k8s.KindDeployment {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "仅 Deployment 应用支持版本回滚")
+	}
+
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return nil, err
+	}
+
+	if err := s.adapter.RollbackApp(ctx, app.Name, app.Namespace, toRevision); err != nil {
+		if errors.Is(err, k8s.ErrRevisionNotFound) {
+			return nil, errcode.New(errcode.ErrRevisionNotFound)
+		}
+		return nil, k8sError(err)
+	}
+
+	status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	_ = s.repo.UpdateSyncedStatus(appID, status.Status, time.Now())
+	return status, nil
+}
+
+// PauseRollout 暂停应用的滚动更新，便于在新旧版本并存时检查新 Pod
+func (s *AppService) PauseRollout(ctx context.Context, appID, userID uint) error {
+	return s.setRolloutPaused(ctx, appID, userID, true)
+}
+
+// ResumeRollout 继续已暂停的滚动更新
+func (s *AppService) ResumeRollout(ctx context.Context, appID, userID uint) error {
+	return s.setRolloutPaused(ctx, appID, userID, false)
+}
+
+// setRolloutPaused 设置 Deployment 滚动更新的暂停状态
+func (s *AppService) setRolloutPaused(ctx context.Context, appID, userID uint, paused bool) error {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return err
+	}
+	if app.Kind != k8s.KindDeployment {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "仅 Deployment 应用支持暂停发布")
+	}
+
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
+
+	if err := s.adapter.SetRolloutPaused(ctx, app.Name, app.Namespace, paused); err != nil {
+		return k8sError(err)
+	}
+	return nil
+}
+
+// GetAppManifests 获取应用在集群中的实际资源清单
+func (s *AppService) GetAppManifests(ctx context.Context, appID, userID uint) (string, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	manifests, err := s.adapter.GetAppManifests(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return "", k8sError(err)
+	}
+
+	return manifests, nil
+}
+
 // getAppWithPermission 获取应用并检查权限
 func (s *AppService) getAppWithPermission(appID, userID uint) (*model.App, error) {
 	app, err := s.repo.GetByID(appID)
@@ -226,15 +818,52 @@ func (s *AppService) getAppWithPermission(appID, userID uint) (*model.App, error
 	return app, nil
 }
 
-// syncAppStatus 同步应用状态
-func (s *AppService) syncAppStatus(ctx context.Context, appID uint, name, namespace string) {
-	status, err := s.adapter.GetAppStatus(ctx, name, namespace)
+// verifyOwnership 操作集群资源前校验其归属，防止误操作他人或手动创建的同名资源
+func (s *AppService) verifyOwnership(ctx context.Context, app *model.App) error {
+	if err := s.adapter.VerifyOwner(ctx, app.Name, app.Namespace, app.UserID); err != nil {
+		if errors.Is(err, k8s.ErrNotOwned) {
+			return errcode.NewWithMsg(errcode.ErrForbidden, err.Error())
+		}
+		return k8sError(err)
+	}
+	return nil
+}
+
+// waitForReady 等待应用就绪并将最终状态写回数据库
+func (s *AppService) waitForReady(ctx context.Context, app *model.App, timeout time.Duration) error {
+	status, err := s.adapter.WaitForReady(ctx, app.Name, app.Namespace, timeout)
+	if status != nil {
+		_ = s.repo.UpdateSyncedStatus(app.ID, status.Status, time.Now())
+	}
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrAppNotReady, err.Error())
+	}
+	return nil
+}
+
+// syncAppStatus 同步应用状态，已挂起或处于构建阶段的应用跳过同步
+func (s *AppService) syncAppStatus(ctx context.Context, app *model.App) {
+	if app.Suspended || isBuildStatus(app.Status) {
+		return
+	}
+
+	status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace)
 	if err != nil {
 		return
 	}
 
-	_ = s.repo.UpdateStatus(appID, status.Status)
+	_ = s.repo.UpdateSyncedStatus(app.ID, status.Status, time.Now())
+	// 只回写当前副本数，期望副本数仅由创建、启动和调整副本数接口修改
 	if status.Replicas > 0 {
-		_ = s.repo.UpdateReplicas(appID, int(status.Replicas))
+		_ = s.repo.UpdateReplicas(app.ID, int(status.Replicas))
 	}
+	s.checkRestarts(app, status.RestartCount)
+}
+
+// checkPort 检查端口范围，0 表示不暴露端口
+func checkPort(port int) error {
+	if port < 0 || port > 65535 {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, "端口需在 1 到 65535 之间，0 表示不暴露端口")
+	}
+	return nil
 }
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestAppService 使用内存 sqlite 与 fake clientset 构建应用服务，并创建一个普通用户；
+// objects 为集群中预置的资源
+func newTestAppService(t *testing.T, cfg *config.Config, objects ...runtime.Object) (*AppService, *model.User) {
+	t.Helper()
+	s, user, _ := newTestAppServiceWithClient(t, cfg, objects...)
+	return s, user
+}
+
+// newTestAppServiceWithClient 同 newTestAppService，同时返回 fake clientset 供检查集群资源与请求
+func newTestAppServiceWithClient(t *testing.T, cfg *config.Config, objects ...runtime.Object) (*AppService, *model.User, *fake.Clientset) {
+	t.Helper()
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	if cfg.App.MaxReplicas == 0 {
+		cfg.App.DefaultReplicas = 1
+		cfg.App.MaxReplicas = 10
+	}
+	user := &model.User{Username: "alice", Password: "x", Email: "alice@example.com", Role: model.RoleUser}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	ctr := &container.Container{
+		Config:  cfg,
+		DB:      db,
+		Adapter: k8s.NewClientGoAdapter(client, nil),
+	}
+	return NewAppService(ctr), user, client
+}
+
+// wantCode 断言错误码，want 为 Success 时要求无错误
+func wantCode(t *testing.T, err error, want errcode.Code) {
+	t.Helper()
+	if want == errcode.Success {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("error = nil, want code %d", want)
+	}
+	if got := errcode.FromError(err).Code; got != want {
+		t.Fatalf("code = %d, want %d（%v）", got, want, err)
+	}
+}
+
+func TestCreateSlots(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		held  int // 创建前已被占用的名额
+		stack bool
+		want  errcode.Code
+	}{
+		{"不限制", 0, 5, false, errcode.Success},
+		{"名额空闲", 1, 0, false, errcode.Success},
+		{"名额已满", 1, 1, false, errcode.ErrTooManyCreates},
+		{"应用组逐个占用名额", 1, 0, true, errcode.Success},
+		{"应用组名额已满", 1, 1, true, errcode.ErrTooManyCreates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user := newTestAppService(t, &config.Config{Quota: config.QuotaConfig{MaxConcurrentCreates: tt.limit}})
+			for i := 0; i < tt.held; i++ {
+				s.createSlots.acquire(user.ID)
+			}
+			ctx := context.Background()
+			var err error
+			if tt.stack {
+				_, err = s.CreateStack(ctx, CreateStackRequest{Name: "web", UserID: user.ID, Apps: []CreateAppRequest{
+					{Name: "db", Image: "postgres:16", Port: 5432},
+					{Name: "api", Image: "nginx:latest", Port: 80},
+				}})
+			} else {
+				_, err = s.CreateApp(ctx, CreateAppRequest{Name: "api", Image: "nginx:latest", Port: 80, UserID: user.ID})
+			}
+			wantCode(t, err, tt.want)
+			if got := s.createSlots.inUse[user.ID]; tt.limit > 0 && got != tt.held {
+				t.Errorf("创建结束后占用名额 = %d, want %d", got, tt.held)
+			}
+		})
+	}
+}
+
+func TestCreateAppNamespaceTerminating(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase corev1.NamespacePhase
+		want  errcode.Code
+	}{
+		{"命名空间正常", corev1.NamespaceActive, errcode.Success},
+		{"命名空间正在删除", corev1.NamespaceTerminating, errcode.ErrK8s},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "astro-user-1"},
+				Status:     corev1.NamespaceStatus{Phase: tt.phase},
+			}
+			s, user := newTestAppService(t, nil, ns)
+			_, err := s.CreateApp(context.Background(), CreateAppRequest{Name: "api", Image: "nginx:latest", Port: 80, UserID: user.ID})
+			wantCode(t, err, tt.want)
+			if err == nil {
+				return
+			}
+			// 创建失败时不应留下应用记录
+			if count, _ := s.repo.CountByUserID(user.ID); count != 0 {
+				t.Errorf("应用记录数 = %d, want 0", count)
+			}
+		})
+	}
+}
+
+// createTestApp 通过 CreateApp 创建应用，replicas 为创建时的副本数
+func createTestApp(t *testing.T, s *AppService, userID uint, name string, replicas int) *model.App {
+	t.Helper()
+	app, err := s.CreateApp(context.Background(), CreateAppRequest{
+		Name: name, Image: "nginx:latest", Port: 80, UserID: userID, Replicas: &replicas,
+	})
+	if err != nil {
+		t.Fatalf("创建应用失败: %v", err)
+	}
+	return app
+}
+
+func TestStopStartPreservesReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+		legacy   bool // 早期创建的应用没有期望副本数
+		want     int
+	}{
+		{"多副本", 3, false, 3},
+		{"单副本", 1, false, 1},
+		{"早期创建的应用", 3, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user := newTestAppService(t, nil)
+			ctx := context.Background()
+			app := createTestApp(t, s, user.ID, "api", tt.replicas)
+			if tt.legacy {
+				if err := s.repo.UpdateScale(app.ID, tt.replicas, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := s.StopApp(ctx, app.ID, user.ID); err != nil {
+				t.Fatalf("StopApp() error = %v", err)
+			}
+			// 停止后同步状态拿到的是 0 副本，不能覆盖期望副本数
+			stopped, _ := s.repo.GetByID(app.ID)
+			s.syncAppStatus(ctx, stopped)
+			stopped, _ = s.repo.GetByID(app.ID)
+			if stopped.Replicas != 0 || stopped.DesiredReplicas != tt.want {
+				t.Fatalf("停止后副本数 = %d/%d, want 0/%d", stopped.Replicas, stopped.DesiredReplicas, tt.want)
+			}
+
+			if err := s.StartApp(ctx, app.ID, user.ID, 0); err != nil {
+				t.Fatalf("StartApp() error = %v", err)
+			}
+			started, _ := s.repo.GetByID(app.ID)
+			if started.Replicas != tt.want || started.DesiredReplicas != tt.want {
+				t.Errorf("启动后副本数 = %d/%d, want %d/%d", started.Replicas, started.DesiredReplicas, tt.want, tt.want)
+			}
+			status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Replicas != int32(tt.want) {
+				t.Errorf("Deployment 副本数 = %d, want %d", status.Replicas, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPort(t *testing.T) {
+	tests := []struct {
+		port int
+		want errcode.Code
+	}{
+		{-1, errcode.ErrBadRequest},
+		{0, errcode.Success},
+		{1, errcode.Success},
+		{8080, errcode.Success},
+		{65535, errcode.Success},
+		{65536, errcode.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.port), func(t *testing.T) {
+			wantCode(t, checkPort(tt.port), tt.want)
+		})
+	}
+
+	t.Run("创建应用时拒绝越界端口", func(t *testing.T) {
+		s, user := newTestAppService(t, nil)
+		_, err := s.CreateApp(context.Background(), CreateAppRequest{Name: "api", Image: "nginx:latest", Port: 70000, UserID: user.ID})
+		wantCode(t, err, errcode.ErrBadRequest)
+		if count, _ := s.repo.CountByUserID(user.ID); count != 0 {
+			t.Errorf("应用记录数 = %d, want 0", count)
+		}
+	})
+}
+
+func TestRestartAppMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		asOther bool
+		want    errcode.Code
+	}{
+		{"滚动重启", RestartRolling, false, errcode.Success},
+		{"重建", RestartRecreate, false, errcode.Success},
+		{"不支持的方式", "force", false, errcode.ErrBadRequest},
+		{"他人的应用", RestartRecreate, true, errcode.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user := newTestAppService(t, nil)
+			app := createTestApp(t, s, user.ID, "api", 1)
+			userID := user.ID
+			if tt.asOther {
+				userID = user.ID + 1
+			}
+			wantCode(t, s.RestartApp(context.Background(), app.ID, userID, tt.mode, 0), tt.want)
+		})
+	}
+}
+
+func TestDeleteAppKind(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        string
+		wantDeleted string
+	}{
+		{"Deployment", "", "deployments"},
+		{"StatefulSet", k8s.KindStatefulSet, "statefulsets"},
+		{"CronJob", k8s.KindCronJob, "cronjobs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user, client := newTestAppServiceWithClient(t, nil)
+			ctx := context.Background()
+			req := CreateAppRequest{Name: "api", Image: "nginx:latest", UserID: user.ID, Kind: tt.kind}
+			switch tt.kind {
+			case k8s.KindStatefulSet:
+				req.Storage = &StorageOption{Size: "1Gi"}
+			case k8s.KindCronJob:
+				req.Schedule = "*/5 * * * *"
+			}
+			app, err := s.CreateApp(ctx, req)
+			if err != nil {
+				t.Fatalf("创建应用失败: %v", err)
+			}
+			client.ClearActions()
+
+			if err := s.DeleteApp(ctx, app.ID, user.ID); err != nil {
+				t.Fatalf("删除应用失败: %v", err)
+			}
+			var deleted []string
+			for _, action := range client.Actions() {
+				if action, ok := action.(k8stesting.DeleteActionImpl); ok && action.GetResource().Resource != "services" {
+					deleted = append(deleted, action.GetResource().Resource)
+				}
+			}
+			if len(deleted) != 1 || deleted[0] != tt.wantDeleted {
+				t.Errorf("删除的工作负载 = %v, want [%s]", deleted, tt.wantDeleted)
+			}
+			if _, err := s.repo.GetByID(app.ID); err == nil {
+				t.Error("删除后数据库记录仍存在")
+			}
+		})
+	}
+}
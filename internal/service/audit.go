@@ -0,0 +1,72 @@
+package service
+
+import (
+	"sync/atomic"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// auditQueueSize 审计日志异步写入队列长度
+const auditQueueSize = 1024
+
+// AuditService 审计日志服务，写入在后台协程中异步完成，不影响请求处理
+type AuditService struct {
+	repo    *repository.AuditRepository
+	queue   chan *model.AuditLog
+	dropped atomic.Int64
+}
+
+// NewAuditService 创建审计日志服务并启动后台写入协程，整个进程只应创建一个实例
+func NewAuditService(c *container.Container) *AuditService {
+	s := &AuditService{
+		repo:  repository.NewAuditRepository(c.DB),
+		queue: make(chan *model.AuditLog, auditQueueSize),
+	}
+	go s.run()
+	return s
+}
+
+// Record 异步记录审计日志，队列已满时丢弃、计数并输出告警日志
+func (s *AuditService) Record(entry *model.AuditLog) {
+	select {
+	case s.queue <- entry:
+	default:
+		dropped := s.dropped.Add(1)
+		logger.Warn("审计日志队列已满，丢弃记录",
+			zap.Uint("user_id", entry.UserID),
+			zap.String("action", entry.Action),
+			zap.String("target", entry.Target),
+			zap.Int64("dropped_total", dropped))
+	}
+}
+
+// Dropped 返回因队列已满累计丢弃的审计日志数
+func (s *AuditService) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// List 分页查询审计日志
+func (s *AuditService) List(filter repository.AuditFilter, page, pageSize int) ([]model.AuditLog, int64, error) {
+	logs, total, err := s.repo.List(filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return logs, total, nil
+}
+
+// run 后台写入审计日志，写入失败只记录错误日志
+func (s *AuditService) run() {
+	for entry := range s.queue {
+		if err := s.repo.Create(entry); err != nil {
+			logger.Error("写入审计日志失败",
+				zap.Uint("user_id", entry.UserID),
+				zap.String("action", entry.Action),
+				zap.Error(err))
+		}
+	}
+}
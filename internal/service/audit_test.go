@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/cuihe500/astro/internal/model"
+)
+
+func TestAuditRecordDropped(t *testing.T) {
+	tests := []struct {
+		name        string
+		queueSize   int
+		records     int
+		wantDropped int64
+	}{
+		{"队列未满", 4, 3, 0},
+		{"恰好填满", 4, 4, 0},
+		{"超出部分计数", 4, 7, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 不启动写入协程，队列只进不出
+			s := &AuditService{queue: make(chan *model.AuditLog, tt.queueSize)}
+			for i := 0; i < tt.records; i++ {
+				s.Record(&model.AuditLog{Action: "app.create"})
+			}
+			if got := s.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", got, tt.wantDropped)
+			}
+			if len(s.queue) != tt.records-int(tt.wantDropped) {
+				t.Errorf("队列长度 = %d, want %d", len(s.queue), tt.records-int(tt.wantDropped))
+			}
+		})
+	}
+}
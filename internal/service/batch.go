@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+// 批量操作类型
+const (
+	BatchStart   = "start"
+	BatchStop    = "stop"
+	BatchRestart = "restart"
+)
+
+// maxBatchWorkers 批量操作同时处理的应用数上限，避免瞬间向集群发起大量请求
+const maxBatchWorkers = 5
+
+// BatchResult 单个应用的批量操作结果
+type BatchResult struct {
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Code    int    `json:"code"`              // 失败时的错误码，成功为 0
+	Message string `json:"message,omitempty"` // 失败原因
+}
+
+// BatchOperateApps 对多个应用执行同一操作，逐个校验权限，单个失败不影响其他应用。
+// 结果顺序与去重后的 appIDs 一致；启动与重启不等待就绪
+func (s *AppService) BatchOperateApps(ctx context.Context, userID uint, appIDs []uint, action string) ([]BatchResult, error) {
+	var operate func(appID uint) error
+	switch action {
+	case BatchStart:
+		operate = func(appID uint) error { return s.StartApp(ctx, appID, userID, 0) }
+	case BatchStop:
+		operate = func(appID uint) error { return s.StopApp(ctx, appID, userID) }
+	case BatchRestart:
+		operate = func(appID uint) error { return s.RestartApp(ctx, appID, userID, RestartRolling, 0) }
+	default:
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("不支持的批量操作: %s", action))
+	}
+
+	ids := make([]uint, 0, len(appIDs))
+	seen := make(map[uint]bool, len(appIDs))
+	for _, id := range appIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	results := make([]BatchResult, len(ids))
+	sem := make(chan struct{}, maxBatchWorkers)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = batchResult(id, operate(id))
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// batchResult 将单个应用的操作错误转换为批量结果
+func batchResult(appID uint, err error) BatchResult {
+	if err == nil {
+		return BatchResult{ID: appID, Success: true}
+	}
+	if e := errcode.FromError(err); e != nil {
+		return BatchResult{ID: appID, Code: e.Code.Int(), Message: e.Msg}
+	}
+	return BatchResult{ID: appID, Code: errcode.ErrInternal.Int(), Message: err.Error()}
+}
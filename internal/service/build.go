@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 源码构建相关的应用状态
+const (
+	appStatusBuilding    = "building"
+	appStatusBuildFailed = "build_failed"
+	appStatusFailed      = "failed" // 构建成功但创建工作负载失败
+)
+
+const (
+	// defaultBuilderImage 未配置构建镜像时使用的 Kaniko 版本
+	defaultBuilderImage = "gcr.io/kaniko-project/executor:v1.23.2"
+	// defaultBuildTimeout 未配置时单次构建超时
+	defaultBuildTimeout = 30 * time.Minute
+	// buildPollInterval 查询构建状态的间隔
+	buildPollInterval = 5 * time.Second
+	// buildWaitGrace 在构建超时之外多等待的时间，留给 Job 自身的超时先生效
+	buildWaitGrace = time.Minute
+)
+
+// SourceOption 源码构建选项
+type SourceOption struct {
+	GitURL     string
+	Ref        string
+	Dockerfile string
+}
+
+// isBuildStatus 判断应用是否处于构建阶段，此时集群中还没有工作负载
+func isBuildStatus(status string) bool {
+	return status == appStatusBuilding || status == appStatusBuildFailed
+}
+
+// buildImage 计算构建产物的镜像名，每次构建使用新的 tag
+func (s *AppService) buildImage(namespace, name string) string {
+	registry := strings.TrimSuffix(s.cfg.Build.Registry, "/")
+	return fmt.Sprintf("%s/%s/%s:%d", registry, namespace, name, time.Now().Unix())
+}
+
+// buildTimeout 单次构建超时
+func (s *AppService) buildTimeout() time.Duration {
+	if d, err := time.ParseDuration(s.cfg.Build.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultBuildTimeout
+}
+
+// startBuild 创建构建 Job 并在后台等待构建完成后创建应用
+func (s *AppService) startBuild(ctx context.Context, app *model.App, spec k8s.AppSpec) error {
+	builderImage := s.cfg.Build.BuilderImage
+	if builderImage == "" {
+		builderImage = defaultBuilderImage
+	}
+
+	job, err := s.adapter.StartBuild(ctx, k8s.BuildSpec{
+		Name:         app.Name,
+		Namespace:    app.Namespace,
+		GitURL:       app.SourceGitURL,
+		Ref:          app.SourceRef,
+		Dockerfile:   app.SourceDockerfile,
+		Destination:  app.Image,
+		BuilderImage: builderImage,
+		PushSecret:   s.cfg.Build.PushSecret,
+		Insecure:     s.cfg.Build.Insecure,
+		Timeout:      s.buildTimeout(),
+		OwnerID:      spec.OwnerID,
+		OwnerUUID:    spec.OwnerUUID,
+	})
+	if err != nil {
+		return err
+	}
+
+	app.BuildJob = job
+	_ = s.repo.Update(app)
+
+	go s.runBuild(context.Background(), app, spec)
+	return nil
+}
+
+// runBuild 等待构建完成，成功后创建应用工作负载
+func (s *AppService) runBuild(ctx context.Context, app *model.App, spec k8s.AppSpec) {
+	ctx, cancel := context.WithTimeout(ctx, s.buildTimeout()+buildWaitGrace)
+	defer cancel()
+
+	ticker := time.NewTicker(buildPollInterval)
+	defer ticker.Stop()
+
+	for succeeded := false; !succeeded; {
+		select {
+		case <-ctx.Done():
+			logger.Warn("等待构建完成超时", zap.Uint("app_id", app.ID), zap.String("job", app.BuildJob))
+			_ = s.repo.UpdateStatus(app.ID, appStatusBuildFailed)
+			return
+		case <-ticker.C:
+		}
+
+		// 查询失败（如集群短暂不可达）时继续等待
+		status, err := s.adapter.GetBuildStatus(ctx, app.BuildJob, app.Namespace)
+		if err != nil {
+			continue
+		}
+		switch status.Phase {
+		case k8s.BuildPhaseFailed:
+			logger.Warn("应用构建失败", zap.Uint("app_id", app.ID), zap.String("job", app.BuildJob))
+			_ = s.repo.UpdateStatus(app.ID, appStatusBuildFailed)
+			return
+		case k8s.BuildPhaseSucceeded:
+			succeeded = true
+		}
+	}
+
+	// 构建期间应用可能已被删除
+	if _, err := s.repo.GetByID(app.ID); err != nil {
+		return
+	}
+	if err := s.adapter.CreateApp(ctx, spec); err != nil {
+		logger.Error("构建完成后创建应用失败", zap.Uint("app_id", app.ID), zap.Error(err))
+		_ = s.repo.UpdateStatus(app.ID, appStatusFailed)
+		return
+	}
+	app.Status = "pending"
+	_ = s.repo.UpdateStatus(app.ID, app.Status)
+	s.syncAppStatus(ctx, app)
+}
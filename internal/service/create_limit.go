@@ -0,0 +1,79 @@
+package service
+
+import "sync"
+
+// userSlots 按用户限制并发数，limit 为 0 表示不限制
+type userSlots struct {
+	limit int
+	mu    sync.Mutex
+	inUse map[uint]int
+}
+
+func newUserSlots(limit int) *userSlots {
+	return &userSlots{limit: limit, inUse: make(map[uint]int)}
+}
+
+// acquire 占用一个名额，已达上限时返回 false
+func (s *userSlots) acquire(userID uint) bool {
+	if s.limit <= 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inUse[userID] >= s.limit {
+		return false
+	}
+	s.inUse[userID]++
+	return true
+}
+
+// release 释放 acquire 占用的名额
+func (s *userSlots) release(userID uint) {
+	if s.limit <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inUse[userID] <= 1 {
+		delete(s.inUse, userID)
+		return
+	}
+	s.inUse[userID]--
+}
+
+// userLocks 按用户的互斥锁，无人持有或等待时回收
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[uint]*userLock
+}
+
+type userLock struct {
+	sync.Mutex
+	refs int // 持有与等待该锁的请求数
+}
+
+func newUserLocks() *userLocks {
+	return &userLocks{locks: make(map[uint]*userLock)}
+}
+
+// lock 获取用户的锁，返回解锁函数
+func (l *userLocks) lock(userID uint) func() {
+	l.mu.Lock()
+	ul, ok := l.locks[userID]
+	if !ok {
+		ul = &userLock{}
+		l.locks[userID] = ul
+	}
+	ul.refs++
+	l.mu.Unlock()
+
+	ul.Lock()
+	return func() {
+		ul.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if ul.refs--; ul.refs == 0 {
+			delete(l.locks, userID)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+const (
+	// dashboardSyncLimit 统计前最多同步的应用数，避免应用很多时拖慢接口
+	dashboardSyncLimit = 20
+	// dashboardStaleAfter 超过该时间未同步的应用视为状态过期
+	dashboardStaleAfter = time.Minute
+)
+
+// DashboardStats 首页统计数据
+type DashboardStats struct {
+	TotalApps     int64            `json:"total_apps"`
+	TotalReplicas int64            `json:"total_replicas"`
+	ByStatus      map[string]int64 `json:"by_status"`    // 各状态的应用数，如 running/stopped/pending
+	StatusStale   bool             `json:"status_stale"` // 为 true 表示集群暂不可达，状态为上次同步结果
+}
+
+// DashboardStats 统计用户的应用概况，userID 为 0 时统计全平台
+// 统计前同步一批最久未更新的应用状态，数量和耗时均有上限
+func (s *AppService) DashboardStats(ctx context.Context, userID uint) (*DashboardStats, error) {
+	stale := s.breaker.Open()
+	if !stale {
+		s.syncStaleApps(ctx, userID)
+	}
+
+	counts, err := s.repo.CountByStatus(userID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	stats := &DashboardStats{
+		ByStatus:    make(map[string]int64, len(counts)),
+		StatusStale: stale,
+	}
+	for _, c := range counts {
+		stats.TotalApps += c.Count
+		stats.TotalReplicas += c.Replicas
+		stats.ByStatus[c.Status] = c.Count
+	}
+	return stats, nil
+}
+
+// syncStaleApps 并发同步最久未更新的应用状态，超时后不再等待
+func (s *AppService) syncStaleApps(ctx context.Context, userID uint) {
+	apps, err := s.repo.ListStale(userID, time.Now().Add(-dashboardStaleAfter), dashboardSyncLimit)
+	if err != nil || len(apps) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, liveStatusTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.syncAppStatus(ctx, &apps[i])
+		}()
+	}
+	wg.Wait()
+}
+
+// NamespaceUsage 命名空间资源用量汇总
+type NamespaceUsage struct {
+	k8s.NamespaceUsage
+	Apps int64 `json:"apps"` // 平台记录中位于该命名空间的应用数
+}
+
+// NamespaceUsage 汇总命名空间的应用数、Pod 数、资源 requests/limits 与实时用量，仅供管理员使用
+func (s *AppService) NamespaceUsage(ctx context.Context, namespace string) (*NamespaceUsage, error) {
+	usage, err := s.adapter.GetNamespaceUsage(ctx, namespace)
+	if err != nil {
+		if errors.Is(err, k8s.ErrNamespaceNotManaged) {
+			return nil, errcode.NewWithMsg(errcode.ErrNotFound, err.Error())
+		}
+		return nil, k8sError(err)
+	}
+
+	apps, err := s.repo.CountByNamespace(namespace)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return &NamespaceUsage{NamespaceUsage: *usage, Apps: apps}, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+)
+
+// AppDescription 应用诊断信息，合并规格、实时状态、事件和最近日志
+// 各部分独立获取，失败的部分为空并在 errors 中给出原因
+type AppDescription struct {
+	App    model.App         `json:"app"`
+	Live   *k8s.AppStatus    `json:"live,omitempty"`
+	Events []k8s.Event       `json:"events"`
+	Logs   string            `json:"logs"`
+	Errors map[string]string `json:"errors,omitempty"` // 获取失败的部分，键为 live/events/logs
+}
+
+// DescribeApp 并发获取应用的实时状态、事件和最近日志，共用一个超时，部分失败不影响其他部分
+func (s *AppService) DescribeApp(ctx context.Context, appID, userID uint, lines int64) (*AppDescription, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, liveStatusTimeout)
+	defer cancel()
+
+	desc := &AppDescription{App: *app, Events: []k8s.Event{}}
+	var mu sync.Mutex
+	fail := func(part string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if desc.Errors == nil {
+			desc.Errors = make(map[string]string)
+		}
+		desc.Errors[part] = err.Error()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace)
+		if err != nil {
+			fail("live", err)
+			return
+		}
+		desc.Live = status
+	}()
+	go func() {
+		defer wg.Done()
+		events, err := s.adapter.GetAppEvents(ctx, app.Name, app.Namespace)
+		if err != nil {
+			fail("events", err)
+			return
+		}
+		desc.Events = events
+	}()
+	go func() {
+		defer wg.Done()
+		logs, err := s.adapter.GetAppLogs(ctx, app.Name, app.Namespace, lines)
+		if err != nil {
+			fail("logs", err)
+			return
+		}
+		desc.Logs = logs
+	}()
+	wg.Wait()
+
+	return desc, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AppEnv 应用环境变量及更新后的发布状态
+type AppEnv struct {
+	Env    []model.EnvVar `json:"env"`
+	Status *k8s.AppStatus `json:"status,omitempty"` // 更新后的实时状态，集群暂不可达时为空
+}
+
+// GetAppEnv 获取应用环境变量
+func (s *AppService) GetAppEnv(ctx context.Context, appID, userID uint) ([]model.EnvVar, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Env == nil {
+		return []model.EnvVar{}, nil
+	}
+	return app.Env, nil
+}
+
+// SetAppEnv 替换应用的全部环境变量，返回更新后的发布状态；
+// 先更新应用记录再写入集群，不在数据库事务中等待集群响应；写入集群失败时将记录恢复为原环境变量。
+// Deployment 与 StatefulSet 会滚动更新所有 Pod，定时任务从下一次调度开始生效
+func (s *AppService) SetAppEnv(ctx context.Context, appID, userID uint, env []model.EnvVar) (*AppEnv, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isBuildStatus(app.Status) {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "应用构建完成前不能修改环境变量")
+	}
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateEnv(appID, env); err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if err := s.adapter.SetAppEnv(ctx, app.Name, app.Namespace, *toK8sEnv(env)); err != nil {
+		if revertErr := s.repo.UpdateEnv(appID, app.Env); revertErr != nil {
+			logger.Error("恢复应用环境变量记录失败", zap.Uint("app_id", app.ID), zap.Error(revertErr))
+		}
+		return nil, k8sError(err)
+	}
+
+	result := &AppEnv{Env: env}
+	if status, err := s.adapter.GetAppStatus(ctx, app.Name, app.Namespace); err == nil {
+		result.Status = status
+	}
+	go s.syncAppStatus(context.Background(), app)
+	return result, nil
+}
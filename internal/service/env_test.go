@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSetAppEnv(t *testing.T) {
+	original := []model.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}
+	updated := []model.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "PORT", Value: "8080"}}
+	tests := []struct {
+		name      string
+		status    string
+		otherUser bool
+		k8sFail   bool
+		want      errcode.Code
+		wantEnv   []model.EnvVar
+	}{
+		{"替换环境变量", "", false, false, errcode.Success, updated},
+		{"写入集群失败时回滚数据库", "", false, true, errcode.ErrK8sOperation, original},
+		{"构建中不能修改", appStatusBuilding, false, false, errcode.ErrBadRequest, original},
+		{"不能修改他人的应用", "", true, false, errcode.ErrForbidden, original},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user, client := newTestAppServiceWithClient(t, nil)
+			ctx := context.Background()
+			app := createTestApp(t, s, user.ID, "api", 1)
+			if _, err := s.SetAppEnv(ctx, app.ID, user.ID, original); err != nil {
+				t.Fatalf("设置初始环境变量失败: %v", err)
+			}
+			if tt.status != "" {
+				if err := s.repo.UpdateStatus(app.ID, tt.status); err != nil {
+					t.Fatalf("更新状态失败: %v", err)
+				}
+			}
+			if tt.k8sFail {
+				client.PrependReactor("update", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("connection reset")
+				})
+			}
+			userID := user.ID
+			if tt.otherUser {
+				userID = user.ID + 1
+			}
+
+			_, err := s.SetAppEnv(ctx, app.ID, userID, updated)
+			wantCode(t, err, tt.want)
+
+			stored, err := s.repo.GetByID(app.ID)
+			if err != nil {
+				t.Fatalf("查询应用失败: %v", err)
+			}
+			if !envEqual(stored.Env, tt.wantEnv) {
+				t.Errorf("数据库环境变量 = %v, want %v", stored.Env, tt.wantEnv)
+			}
+			deployment, err := client.AppsV1().Deployments(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Deployment 不存在: %v", err)
+			}
+			got := deployment.Spec.Template.Spec.Containers[0].Env
+			if len(got) != len(tt.wantEnv) {
+				t.Fatalf("Deployment 环境变量 = %v, want %v", got, tt.wantEnv)
+			}
+			for i, e := range tt.wantEnv {
+				if got[i].Name != e.Name || got[i].Value != e.Value {
+					t.Errorf("Deployment 环境变量[%d] = %s=%s, want %s=%s", i, got[i].Name, got[i].Value, e.Name, e.Value)
+				}
+			}
+		})
+	}
+}
+
+// TestSetAppEnvOutsideTransaction 写入集群期间不持有数据库事务，其他请求仍可读写应用记录
+func TestSetAppEnvOutsideTransaction(t *testing.T) {
+	s, user, client := newTestAppServiceWithClient(t, nil)
+	app := createTestApp(t, s, user.ID, "api", 1)
+	env := []model.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}
+
+	var visible []model.EnvVar
+	client.PrependReactor("update", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if stored, err := s.repo.GetByID(app.ID); err == nil {
+				visible = stored.Env
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("写入集群期间无法读取应用记录")
+		}
+		return false, nil, nil
+	})
+
+	if _, err := s.SetAppEnv(context.Background(), app.ID, user.ID, env); err != nil {
+		t.Fatalf("SetAppEnv() error = %v", err)
+	}
+	if !envEqual(visible, env) {
+		t.Errorf("写入集群时数据库环境变量 = %v, want %v", visible, env)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"gorm.io/gorm"
+)
+
+// idempotencyTTL 幂等键有效期
+const idempotencyTTL = 24 * time.Hour
+
+// createAppIdempotent 带幂等键创建应用：首次请求占用键并创建，重复请求返回首次创建的应用
+// 首次请求尚未完成时返回 ErrInProgress，创建失败会释放键以便重试
+func (s *AppService) createAppIdempotent(ctx context.Context, req CreateAppRequest) (*model.App, error) {
+	record, err := s.idempotencyRepo.Reserve(req.UserID, req.IdempotencyKey, time.Now().Add(idempotencyTTL))
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		existing, err := s.idempotencyRepo.Get(req.UserID, req.IdempotencyKey)
+		if err != nil {
+			return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		}
+		if existing.AppID == 0 {
+			return nil, errcode.New(errcode.ErrInProgress)
+		}
+		return s.getAppWithPermission(existing.AppID, req.UserID)
+	}
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	app, err := s.createApp(ctx, req)
+	if err != nil {
+		_ = s.idempotencyRepo.Delete(record.ID)
+		return nil, err
+	}
+	_ = s.idempotencyRepo.SetAppID(record.ID, app.ID)
+	return app, nil
+}
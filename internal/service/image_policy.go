@@ -0,0 +1,65 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+// checkImagePolicy 检查镜像是否符合配置的镜像策略，禁止规则使用加载配置时编译好的正则。
+// 禁止规则同时匹配原始引用与补全默认仓库后的引用，避免用短名（如 nginx:latest）绕过针对完整地址的规则
+func checkImagePolicy(cfg *config.ImageConfig, image string) error {
+	normalized := normalizeImageRef(image)
+	for _, re := range cfg.DeniedRegexps() {
+		if re.MatchString(image) || re.MatchString(normalized) {
+			return errcode.NewWithMsg(errcode.ErrImageNotAllowed, "镜像被禁止使用: "+image)
+		}
+	}
+
+	if len(cfg.AllowedRepos) == 0 {
+		return nil
+	}
+
+	repo := normalizeImageRepo(image)
+	for _, allowed := range cfg.AllowedRepos {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if allowed == "*" || repo == allowed || strings.HasPrefix(repo, allowed+"/") {
+			return nil
+		}
+	}
+	return errcode.NewWithMsg(errcode.ErrImageNotAllowed, "镜像仓库不在允许范围内: "+image)
+}
+
+// normalizeImageRef 补全镜像的默认仓库并保留 tag 和 digest
+// 例如 nginx:latest -> docker.io/library/nginx:latest
+func normalizeImageRef(image string) string {
+	return normalizeImageRepo(image) + image[len(stripImageRef(image)):]
+}
+
+// normalizeImageRepo 去掉镜像的 tag 和 digest，并补全默认仓库
+// 例如 nginx:latest -> docker.io/library/nginx
+func normalizeImageRepo(image string) string {
+	repo := stripImageRef(image)
+	first, _, found := strings.Cut(repo, "/")
+	if !found {
+		return "docker.io/library/" + repo
+	}
+	// 首段包含 "." 或 ":" 或为 localhost 时视为仓库地址
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return repo
+	}
+	return "docker.io/" + repo
+}
+
+// stripImageRef 去掉镜像的 tag 和 digest
+func stripImageRef(image string) string {
+	repo := image
+	if i := strings.Index(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo
+}
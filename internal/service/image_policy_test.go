@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+func TestCheckImagePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		image   string
+		want    errcode.Code
+	}{
+		{"未配置策略", nil, nil, "nginx:latest", errcode.Success},
+		{"官方镜像在允许范围内", []string{"docker.io/library"}, nil, "nginx:1.25", errcode.Success},
+		{"私有仓库在允许范围内", []string{"registry.example.com/"}, nil, "registry.example.com/team/api@sha256:abc", errcode.Success},
+		{"通配允许", []string{"*"}, nil, "ghcr.io/org/app:v1", errcode.Success},
+		{"仓库前缀不完整不算匹配", []string{"registry.example.com/team"}, nil, "registry.example.com/teamx/api", errcode.ErrImageNotAllowed},
+		{"仓库不在允许范围内", []string{"docker.io/library"}, nil, "ghcr.io/org/app:v1", errcode.ErrImageNotAllowed},
+		{"命中禁止规则", nil, []string{":latest$"}, "nginx:latest", errcode.ErrImageNotAllowed},
+		{"禁止规则优先于允许", []string{"*"}, []string{"^docker\\.io/", "xmrig"}, "someone/xmrig:1.0", errcode.ErrImageNotAllowed},
+		{"未命中禁止规则", nil, []string{":latest$"}, "nginx:1.25", errcode.Success},
+		{"短名不能绕过完整地址的禁止规则", nil, []string{"^docker\\.io/library/"}, "nginx:latest", errcode.ErrImageNotAllowed},
+		{"用户镜像短名补全后匹配", nil, []string{"^docker\\.io/someone/"}, "someone/app:v1", errcode.ErrImageNotAllowed},
+		{"私有仓库不补全", nil, []string{"^docker\\.io/"}, "registry.example.com/team/api:v1", errcode.Success},
+		{"补全后的完整引用保留 digest", nil, []string{"^docker\\.io/library/nginx@sha256:"}, "nginx@sha256:abc", errcode.ErrImageNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.ImageConfig{AllowedRepos: tt.allowed, DeniedPatterns: tt.denied}
+			if err := cfg.Compile(); err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			wantCode(t, checkImagePolicy(cfg, tt.image), tt.want)
+		})
+	}
+}
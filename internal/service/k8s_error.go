@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/pkg/errcode"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// K8sErrorDetail K8s API 错误详情，随错误响应的 data 返回
+type K8sErrorDetail struct {
+	Reason string          `json:"reason"`           // K8s 错误原因，如 Forbidden、Invalid、AlreadyExists
+	Kind   string          `json:"kind,omitempty"`   // 出错的资源类型
+	Name   string          `json:"name,omitempty"`   // 出错的资源名称
+	Causes []K8sErrorCause `json:"causes,omitempty"` // 字段级错误，规格无效时给出
+}
+
+// K8sErrorCause 字段级错误
+type K8sErrorCause struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// k8sError 将适配器错误转换为错误码：熔断期间返回 ErrK8sConnect，
+// API Server 明确拒绝时按原因区分配额、权限、冲突与规格错误并附带详情，其余返回 ErrK8sOperation
+func k8sError(err error) error {
+	if errors.Is(err, k8s.ErrCircuitOpen) {
+		return errcode.NewWithMsg(errcode.ErrK8sConnect, err.Error())
+	}
+
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+	}
+
+	var code errcode.Code
+	switch {
+	// ResourceQuota 拒绝时原因同为 Forbidden，需按消息区分
+	case apierrors.IsForbidden(err) && strings.Contains(apiStatus.Status().Message, "exceeded quota"):
+		code = errcode.ErrK8sQuota
+	case apierrors.IsForbidden(err):
+		code = errcode.ErrK8sForbidden
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		code = errcode.ErrK8sConflict
+	case apierrors.IsInvalid(err):
+		code = errcode.ErrK8sInvalid
+	default:
+		return errcode.NewWithMsg(errcode.ErrK8sOperation, err.Error())
+	}
+	return errcode.NewWithData(code, err.Error(), k8sErrorDetail(apiStatus.Status()))
+}
+
+// k8sErrorDetail 从 K8s Status 提取错误详情
+func k8sErrorDetail(status metav1.Status) *K8sErrorDetail {
+	detail := &K8sErrorDetail{Reason: string(status.Reason)}
+	if status.Details == nil {
+		return detail
+	}
+	detail.Kind = status.Details.Kind
+	detail.Name = status.Details.Name
+	for _, cause := range status.Details.Causes {
+		detail.Causes = append(detail.Causes, K8sErrorCause{Field: cause.Field, Message: cause.Message})
+	}
+	return detail
+}
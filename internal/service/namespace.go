@@ -0,0 +1,87 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// defaultNamespacePrefix 未配置时 per-user 策略使用的命名空间前缀
+const defaultNamespacePrefix = "astro-user-"
+
+// teamNamespacePrefix per-team 策略的命名空间前缀
+const teamNamespacePrefix = "astro-team-"
+
+// resolveNamespace 根据配置的策略计算用户应用所在的命名空间
+// 解析结果会保存到应用记录中，后续操作直接使用记录中的命名空间，修改前缀不影响已有应用
+func (s *AppService) resolveNamespace(userID uint) (string, error) {
+	namespace, err := s.deriveNamespace(userID)
+	if err != nil {
+		return "", err
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", errcode.NewWithMsg(errcode.ErrInternal, "无效的命名空间 "+namespace+": "+strings.Join(errs, "; "))
+	}
+	return namespace, nil
+}
+
+// deriveNamespace 按策略拼接命名空间名称
+func (s *AppService) deriveNamespace(userID uint) (string, error) {
+	cfg := s.cfg.Kubernetes
+
+	switch cfg.NamespaceStrategy {
+	case "", config.NamespacePerUser:
+		return s.userNamespace(userID), nil
+	case config.NamespaceSingle:
+		if cfg.Namespace == "" {
+			return "astro-apps", nil
+		}
+		return cfg.Namespace, nil
+	case config.NamespacePerTeam:
+		user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			return "", errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		}
+		// 未加入团队的用户回退到独立命名空间
+		team := teamNamespaceSuffix(user.Team)
+		if team == "" {
+			return s.userNamespace(userID), nil
+		}
+		return teamNamespacePrefix + team, nil
+	default:
+		return "", errcode.NewWithMsg(errcode.ErrInternal, "未知的命名空间策略: "+cfg.NamespaceStrategy)
+	}
+}
+
+// userNamespace 用户独立命名空间：<前缀><用户 ID>
+func (s *AppService) userNamespace(userID uint) string {
+	prefix := s.cfg.Kubernetes.NamespacePrefix
+	if prefix == "" {
+		prefix = defaultNamespacePrefix
+	}
+	return prefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// teamNamespaceSuffix 将团队名转换为命名空间后缀：转小写，非字母数字的连续字符替换为一个 -，
+// 去掉首尾的 -，并截断到与前缀拼接后不超过 DNS 标签的 63 个字符；团队名为空或不含字母数字时返回空
+func teamNamespaceSuffix(team string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(team) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	suffix := b.String()
+	if max := validation.DNS1123LabelMaxLength - len(teamNamespacePrefix); len(suffix) > max {
+		suffix = suffix[:max]
+	}
+	return strings.TrimRight(suffix, "-")
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+func TestTeamNamespaceSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		team string
+		want string
+	}{
+		{"小写字母", "platform", "platform"},
+		{"大写转小写", "Platform", "platform"},
+		{"空格与符号替换为 -", "Data & AI_Team", "data-ai-team"},
+		{"去掉首尾的 -", "--ops--", "ops"},
+		{"非 ASCII 字符被忽略", "平台组", ""},
+		{"空团队", "", ""},
+		{"截断到 DNS 标签长度", strings.Repeat("a", 70), strings.Repeat("a", 52)},
+		{"截断后去掉结尾的 -", strings.Repeat("a", 51) + "-b", strings.Repeat("a", 51)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teamNamespaceSuffix(tt.team); got != tt.want {
+				t.Errorf("teamNamespaceSuffix(%q) = %q, want %q", tt.team, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNamespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		team     string
+		want     string
+		wantErr  errcode.Code
+	}{
+		{"默认按用户", "", "", "astro-user-1", errcode.Success},
+		{"共用命名空间", config.NamespaceSingle, "", "astro-apps", errcode.Success},
+		{"按团队", config.NamespacePerTeam, "Data & AI", "astro-team-data-ai", errcode.Success},
+		{"未加入团队回退到用户", config.NamespacePerTeam, "", "astro-user-1", errcode.Success},
+		{"团队名不含字母数字回退到用户", config.NamespacePerTeam, "平台组", "astro-user-1", errcode.Success},
+		{"超长团队名被截断", config.NamespacePerTeam, strings.Repeat("x", 64), "astro-team-" + strings.Repeat("x", 52), errcode.Success},
+		{"未知策略", "per-region", "", "", errcode.ErrInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Kubernetes: config.KubernetesConfig{NamespaceStrategy: tt.strategy}}
+			s, user := newTestAppService(t, cfg)
+			if err := s.userRepo.UpdateTeam(user.ID, tt.team); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.resolveNamespace(user.ID)
+			wantCode(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("namespace = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTeam(t *testing.T) {
+	tests := []struct {
+		name     string
+		team     string
+		wantTeam string
+		want     errcode.Code
+	}{
+		{"设置团队", " Platform ", "Platform", errcode.Success},
+		{"移出团队", "", "", errcode.Success},
+		{"不含字母数字", "---", "", errcode.ErrBadRequest},
+		{"超过长度", strings.Repeat("a", 65), "", errcode.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestUserService(t)
+			if err := s.Register("alice", "password123", "alice@example.com"); err != nil {
+				t.Fatal(err)
+			}
+			existing, err := s.repo.GetUserByUsername("alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := s.SetTeam(existing.ID, tt.team)
+			wantCode(t, err, tt.want)
+			if err != nil {
+				return
+			}
+			stored, _ := s.repo.GetUserByID(existing.ID)
+			if user.Team != tt.wantTeam || stored.Team != tt.wantTeam {
+				t.Errorf("team = %q（存储 %q），want %q", user.Team, stored.Team, tt.wantTeam)
+			}
+		})
+	}
+
+	t.Run("用户不存在", func(t *testing.T) {
+		_, err := newTestUserService(t).SetTeam(9999, "ops")
+		wantCode(t, err, errcode.ErrUserNotFound)
+	})
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+// maxPasswordBytes bcrypt 只处理前 72 字节，更长的密码会被拒绝
+const maxPasswordBytes = 72
+
+// checkPasswordPolicy 检查密码是否符合配置的强度策略，不满足时列出所有未满足的要求
+func checkPasswordPolicy(policy *config.PasswordPolicy, password string) error {
+	if len(password) > maxPasswordBytes {
+		return errcode.NewWithMsg(errcode.ErrInvalidPassword, fmt.Sprintf("密码不能超过 %d 字节", maxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var unmet []string
+	if n := len([]rune(password)); n < policy.MinLength {
+		unmet = append(unmet, fmt.Sprintf("长度至少 %d 位", policy.MinLength))
+	}
+	if policy.RequireUpper && !hasUpper {
+		unmet = append(unmet, "包含大写字母")
+	}
+	if policy.RequireLower && !hasLower {
+		unmet = append(unmet, "包含小写字母")
+	}
+	if policy.RequireDigit && !hasDigit {
+		unmet = append(unmet, "包含数字")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		unmet = append(unmet, "包含特殊字符")
+	}
+	if len(unmet) > 0 {
+		return errcode.NewWithMsg(errcode.ErrInvalidPassword, "密码需要"+strings.Join(unmet, "、"))
+	}
+	return nil
+}
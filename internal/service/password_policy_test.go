@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	defaults := config.PasswordPolicy{MinLength: 8, RequireLower: true, RequireDigit: true}
+	strict := config.PasswordPolicy{MinLength: 12, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		policy   config.PasswordPolicy
+		password string
+		want     errcode.Code
+		wantMsg  string // 错误消息应包含的内容
+	}{
+		{"默认策略通过", defaults, "password123", errcode.Success, ""},
+		{"刚好满足最小长度", defaults, "abcdefg1", errcode.Success, ""},
+		{"长度不足", defaults, "abc1234", errcode.ErrInvalidPassword, "长度至少 8 位"},
+		{"缺少数字", defaults, "password", errcode.ErrInvalidPassword, "包含数字"},
+		{"缺少小写字母", defaults, "PASSWORD123", errcode.ErrInvalidPassword, "包含小写字母"},
+		{"列出所有未满足的要求", defaults, "ABC", errcode.ErrInvalidPassword, "长度至少 8 位、包含小写字母、包含数字"},
+		{"按字符计算长度", defaults, "密码密码密码a1", errcode.Success, ""},
+		{"严格策略通过", strict, "Str0ng!Passw0rd", errcode.Success, ""},
+		{"严格策略缺少大写字母", strict, "str0ng!passw0rd", errcode.ErrInvalidPassword, "包含大写字母"},
+		{"严格策略缺少特殊字符", strict, "Str0ngPassw0rd", errcode.ErrInvalidPassword, "包含特殊字符"},
+		{"超过 bcrypt 上限", defaults, strings.Repeat("a1", 37), errcode.ErrInvalidPassword, "不能超过 72 字节"},
+		{"空策略不限制", config.PasswordPolicy{}, "x", errcode.Success, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPasswordPolicy(&tt.policy, tt.password)
+			wantCode(t, err, tt.want)
+			if err != nil && !strings.Contains(errcode.FromError(err).Msg, tt.wantMsg) {
+				t.Errorf("message = %q, want contains %q", errcode.FromError(err).Msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRegisterPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     errcode.Code
+	}{
+		{"符合策略", "password123", errcode.Success},
+		{"不符合策略", "short", errcode.ErrInvalidPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestUserService(t)
+			wantCode(t, s.Register("alice", tt.password, "alice@example.com"), tt.want)
+		})
+	}
+}
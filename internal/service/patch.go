@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaxMetadataBytes 应用元数据序列化后的最大字节数
+const MaxMetadataBytes = 4096
+
+// 应用更新方式
+const (
+	// UpdateInPlace 原地修改 Pod 模板，由工作负载滚动替换 Pod（默认）
+	UpdateInPlace = "in-place"
+	// UpdateRecreate 删除工作负载后按新配置重建，旧 Pod 全部删除后才创建新 Pod，期间应用不可用；
+	// 有状态应用的持久卷声明一并删除，数据不可恢复。数据库记录保留，应用 ID 不变
+	UpdateRecreate = "recreate"
+)
+
+// AppPatch 应用部分更新，为 nil 的字段保持不变
+type AppPatch struct {
+	// Strategy 更新方式，为空时原地更新
+	Strategy        string
+	Replicas        *int
+	Image           *string
+	ImagePullPolicy *string
+	Env             *[]model.EnvVar // 非 nil 时整体替换环境变量
+	// Metadata JSON Merge Patch（RFC 7386），按键合并到现有元数据，值为 null 的键被删除；整体为 null 时清空元数据
+	Metadata json.RawMessage
+}
+
+// PatchApp 部分更新应用：在当前配置上应用补丁并整体校验，通过后只同步发生变化的字段，
+// 镜像、拉取策略与环境变量合并为一次容器更新，副本数变化与调整副本数接口语义相同。
+// 所有校验在写入前完成；先写入数据库再修改 K8s，K8s 修改失败时撤销已完成的副本数调整并恢复数据库记录
+func (s *AppService) PatchApp(ctx context.Context, appID, userID uint, patch AppPatch) (*model.App, error) {
+	// 持有配额锁直到写入数据库，避免并发调整副本数同时通过配额检查
+	releaseQuota := sync.OnceFunc(quotaLocks.lock(userID))
+	defer releaseQuota()
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isBuildStatus(app.Status) {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "应用构建完成前不能修改")
+	}
+	recreate := false
+	switch patch.Strategy {
+	case "", UpdateInPlace:
+	case UpdateRecreate:
+		if app.Kind == k8s.KindCronJob {
+			return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持重建")
+		}
+		recreate = true
+	default:
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("不支持的更新方式: %s", patch.Strategy))
+	}
+	// original 为修改前的记录，K8s 修改失败时据此恢复数据库
+	original := *app
+
+	var update k8s.ContainerUpdate
+	var columns []string
+	if patch.Image != nil && *patch.Image != app.Image {
+		if err := checkImagePolicy(&s.cfg.Image, *patch.Image); err != nil {
+			return nil, err
+		}
+		update.Image = patch.Image
+		app.Image = *patch.Image
+		columns = append(columns, "image")
+	}
+	if patch.ImagePullPolicy != nil && *patch.ImagePullPolicy != app.ImagePullPolicy {
+		update.ImagePullPolicy = patch.ImagePullPolicy
+		app.ImagePullPolicy = *patch.ImagePullPolicy
+		columns = append(columns, "image_pull_policy")
+	}
+	if patch.Env != nil && !envEqual(*patch.Env, app.Env) {
+		update.Env = toK8sEnv(*patch.Env)
+		app.Env = *patch.Env
+		columns = append(columns, "env")
+	}
+	if len(patch.Metadata) > 0 {
+		metadata, err := mergeMetadata(app.Metadata, patch.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		app.Metadata = metadata
+		columns = append(columns, "metadata")
+	}
+
+	scale := patch.Replicas != nil && *patch.Replicas != app.Replicas
+	if scale {
+		if app.Kind == k8s.KindCronJob {
+			return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "定时任务应用不支持调整副本数")
+		}
+		replicas := *patch.Replicas
+		if replicas > 0 {
+			if err := s.checkReplicas(replicas); err != nil {
+				return nil, err
+			}
+			if err := s.checkQuota(userID, 0, replicas-app.Replicas); err != nil {
+				return nil, err
+			}
+			app.DesiredReplicas = replicas
+		} else {
+			// 与停止相同：保留期望副本数供启动时恢复，旧数据没有期望副本数时记为当前副本数
+			if app.DesiredReplicas == 0 {
+				app.DesiredReplicas = app.Replicas
+			}
+			app.Status = "stopped"
+			columns = append(columns, "status")
+		}
+		app.Replicas = replicas
+		app.ScaledToZero = false
+		columns = append(columns, "replicas", "desired_replicas", "scaled_to_zero")
+	}
+
+	containerChanged := update.Image != nil || update.ImagePullPolicy != nil || update.Env != nil
+	if scale || containerChanged || recreate {
+		if err := s.verifyOwnership(ctx, app); err != nil {
+			return nil, err
+		}
+	}
+
+	// 先写入数据库，再依次调整副本数（可撤销）、执行容器更新或重建，不在数据库事务中等待集群；
+	// 任一步失败时撤销已完成的副本数调整，并将数据库记录恢复为修改前的值
+	if len(columns) > 0 {
+		if err := s.repo.UpdateFields(app, columns...); err != nil {
+			return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		}
+	}
+	releaseQuota()
+	var k8sErr error
+	if scale {
+		k8sErr = s.adapter.ScaleApp(ctx, app.Name, app.Namespace, int32(app.Replicas))
+	}
+	if k8sErr == nil {
+		if recreate {
+			// 重建不要求配置有变化，也可用于清空有状态应用的数据
+			k8sErr = s.adapter.RecreateApp(ctx, app.Name, app.Namespace, update)
+		} else if containerChanged {
+			k8sErr = s.adapter.UpdateAppContainer(ctx, app.Name, app.Namespace, update)
+		}
+		if k8sErr != nil && scale {
+			s.revertScale(ctx, app, original.Replicas)
+		}
+	}
+	if k8sErr != nil {
+		s.restoreFields(&original, columns)
+		return nil, k8sError(k8sErr)
+	}
+	if scale || containerChanged || recreate {
+		go s.syncAppStatus(context.Background(), app)
+	}
+
+	app, err = s.repo.GetByID(appID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return app, nil
+}
+
+// revertScale 撤销 PatchApp 中已完成的副本数调整，失败时只记录日志
+func (s *AppService) revertScale(ctx context.Context, app *model.App, replicas int) {
+	if err := s.adapter.ScaleApp(ctx, app.Name, app.Namespace, int32(replicas)); err != nil {
+		logger.Error("撤销应用副本数调整失败", zap.Uint("app_id", app.ID), zap.Int("replicas", replicas), zap.Error(err))
+	}
+}
+
+// restoreFields 将数据库记录中的 columns 恢复为修改前的值，失败时只记录日志
+func (s *AppService) restoreFields(original *model.App, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+	if err := s.repo.UpdateFields(original, columns...); err != nil {
+		logger.Error("恢复应用记录失败", zap.Uint("app_id", original.ID), zap.Strings("columns", columns), zap.Error(err))
+	}
+}
+
+// toK8sEnv 转换为 K8s 环境变量
+func toK8sEnv(env []model.EnvVar) *[]k8s.EnvVar {
+	vars := make([]k8s.EnvVar, 0, len(env))
+	for _, e := range env {
+		vars = append(vars, k8s.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	return &vars
+}
+
+// envEqual 判断两组环境变量是否完全相同（含顺序）
+func envEqual(a, b []model.EnvVar) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeMetadata 按 JSON Merge Patch 将补丁合并到现有元数据，结果为空对象时清空
+func mergeMetadata(current, patch json.RawMessage) (json.RawMessage, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "metadata 不是合法的 JSON")
+	}
+	if patchValue == nil {
+		return nil, nil
+	}
+
+	var currentValue interface{}
+	if len(bytes.TrimSpace(current)) > 0 {
+		if err := json.Unmarshal(current, &currentValue); err != nil {
+			return nil, errcode.NewWithMsg(errcode.ErrInternal, fmt.Sprintf("解析现有元数据失败: %s", err))
+		}
+	}
+
+	merged, ok := mergePatch(currentValue, patchValue).(map[string]interface{})
+	if !ok {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "metadata 必须为 JSON 对象")
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+	}
+	if len(data) > MaxMetadataBytes {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("合并后的 metadata 不能超过 %d 字节", MaxMetadataBytes))
+	}
+	return data, nil
+}
+
+// mergePatch RFC 7386 合并：补丁为对象时逐键递归合并，值为 null 的键被删除；否则补丁整体替换目标
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/pkg/errcode"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPatchAppStrategy(t *testing.T) {
+	image := "nginx:1.27"
+	tests := []struct {
+		name        string
+		kind        string
+		strategy    string
+		image       *string
+		want        errcode.Code
+		wantImage   string
+		wantRecreat bool
+	}{
+		{"默认原地更新", "", "", &image, errcode.Success, image, false},
+		{"显式原地更新", "", UpdateInPlace, &image, errcode.Success, image, false},
+		{"重建并更新镜像", "", UpdateRecreate, &image, errcode.Success, image, true},
+		{"无修改时重建", "", UpdateRecreate, nil, errcode.Success, "nginx:latest", true},
+		{"不支持的更新方式", "", "replace", &image, errcode.ErrBadRequest, "nginx:latest", false},
+		{"定时任务不支持重建", k8s.KindCronJob, UpdateRecreate, nil, errcode.ErrBadRequest, "nginx:latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user, client := newTestAppServiceWithClient(t, nil)
+			ctx := context.Background()
+			req := CreateAppRequest{Name: "api", Image: "nginx:latest", Port: 80, UserID: user.ID, Kind: tt.kind}
+			if tt.kind == k8s.KindCronJob {
+				req.Schedule = "*/5 * * * *"
+			}
+			app, err := s.CreateApp(ctx, req)
+			if err != nil {
+				t.Fatalf("创建应用失败: %v", err)
+			}
+			client.ClearActions()
+
+			patched, err := s.PatchApp(ctx, app.ID, user.ID, AppPatch{Strategy: tt.strategy, Image: tt.image})
+			wantCode(t, err, tt.want)
+
+			recreated := false
+			for _, action := range client.Actions() {
+				if action, ok := action.(k8stesting.DeleteActionImpl); ok && action.GetResource().Resource == "deployments" {
+					recreated = true
+				}
+			}
+			if recreated != tt.wantRecreat {
+				t.Errorf("重建 = %v, want %v", recreated, tt.wantRecreat)
+			}
+			if err != nil {
+				stored, _ := s.repo.GetByID(app.ID)
+				if stored.Image != tt.wantImage {
+					t.Errorf("失败后数据库镜像 = %q, want %q", stored.Image, tt.wantImage)
+				}
+				return
+			}
+
+			if patched.ID != app.ID || patched.Image != tt.wantImage {
+				t.Errorf("应用 = %d %q, want %d %q", patched.ID, patched.Image, app.ID, tt.wantImage)
+			}
+			deployment, err := client.AppsV1().Deployments(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Deployment 不存在: %v", err)
+			}
+			if got := deployment.Spec.Template.Spec.Containers[0].Image; got != tt.wantImage {
+				t.Errorf("Deployment 镜像 = %q, want %q", got, tt.wantImage)
+			}
+		})
+	}
+}
+
+func TestPatchAppRollback(t *testing.T) {
+	image := "nginx:1.27"
+	two, zero, tooMany := 2, 0, 11
+	tests := []struct {
+		name         string
+		patch        AppPatch
+		failUpdate   int // 第几次更新 Deployment 时失败，0 表示不失败
+		want         errcode.Code
+		wantImage    string
+		wantReplicas int
+		wantDesired  int
+		wantStatus   string
+		wantUpdates  int // 对 Deployment 发起的更新次数
+	}{
+		{"镜像与副本数同时修改", AppPatch{Image: &image, Replicas: &two}, 0, errcode.Success, image, 2, 2, "", 2},
+		{"副本数校验失败不做任何修改", AppPatch{Image: &image, Replicas: &tooMany}, 0, errcode.ErrBadRequest, "nginx:latest", 1, 1, "", 0},
+		{"调整副本数失败回滚数据库", AppPatch{Image: &image, Replicas: &two}, 1, errcode.ErrK8sOperation, "nginx:latest", 1, 1, "", 1},
+		{"容器更新失败撤销副本数并回滚数据库", AppPatch{Image: &image, Replicas: &two}, 2, errcode.ErrK8sOperation, "nginx:latest", 1, 1, "", 3},
+		{"副本数为 0 等同于停止", AppPatch{Replicas: &zero}, 0, errcode.Success, "nginx:latest", 0, 1, "stopped", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user, client := newTestAppServiceWithClient(t, nil)
+			ctx := context.Background()
+			app := createTestApp(t, s, user.ID, "api", 1)
+			updates := 0
+			client.PrependReactor("update", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+				updates++
+				if updates == tt.failUpdate {
+					return true, nil, errors.New("connection reset")
+				}
+				return false, nil, nil
+			})
+
+			_, err := s.PatchApp(ctx, app.ID, user.ID, tt.patch)
+			wantCode(t, err, tt.want)
+			if updates != tt.wantUpdates {
+				t.Errorf("更新 Deployment %d 次, want %d", updates, tt.wantUpdates)
+			}
+
+			stored, err := s.repo.GetByID(app.ID)
+			if err != nil {
+				t.Fatalf("查询应用失败: %v", err)
+			}
+			if stored.Image != tt.wantImage || stored.Replicas != tt.wantReplicas || stored.DesiredReplicas != tt.wantDesired {
+				t.Errorf("数据库 = %q %d/%d, want %q %d/%d", stored.Image, stored.Replicas, stored.DesiredReplicas,
+					tt.wantImage, tt.wantReplicas, tt.wantDesired)
+			}
+			if tt.wantStatus != "" && stored.Status != tt.wantStatus {
+				t.Errorf("状态 = %q, want %q", stored.Status, tt.wantStatus)
+			}
+
+			deployment, err := client.AppsV1().Deployments(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Deployment 不存在: %v", err)
+			}
+			if got := deployment.Spec.Template.Spec.Containers[0].Image; got != tt.wantImage {
+				t.Errorf("Deployment 镜像 = %q, want %q", got, tt.wantImage)
+			}
+			if got := int(*deployment.Spec.Replicas); got != tt.wantReplicas {
+				t.Errorf("Deployment 副本数 = %d, want %d", got, tt.wantReplicas)
+			}
+		})
+	}
+}
+
+func TestPatchAppRecreateFailure(t *testing.T) {
+	s, user, client := newTestAppServiceWithClient(t, nil)
+	ctx := context.Background()
+	app := createTestApp(t, s, user.ID, "api", 1)
+	client.PrependReactor("create", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection reset")
+	})
+
+	image, two := "nginx:1.27", 2
+	_, err := s.PatchApp(ctx, app.ID, user.ID, AppPatch{Strategy: UpdateRecreate, Image: &image, Replicas: &two})
+	wantCode(t, err, errcode.ErrK8sOperation)
+
+	stored, err := s.repo.GetByID(app.ID)
+	if err != nil {
+		t.Fatalf("查询应用失败: %v", err)
+	}
+	if stored.Image != "nginx:latest" || stored.Replicas != 1 || stored.DesiredReplicas != 1 {
+		t.Errorf("重建失败后数据库 = %q %d/%d, want 恢复为 nginx:latest 1/1", stored.Image, stored.Replicas, stored.DesiredReplicas)
+	}
+}
+
+// TestPatchAppOutsideTransaction 修改 K8s 期间不持有数据库事务，其他请求仍可读取已写入的记录
+func TestPatchAppOutsideTransaction(t *testing.T) {
+	s, user, client := newTestAppServiceWithClient(t, nil)
+	app := createTestApp(t, s, user.ID, "api", 1)
+	image := "nginx:1.27"
+
+	var visible string
+	client.PrependReactor("update", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if stored, err := s.repo.GetByID(app.ID); err == nil {
+				visible = stored.Image
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("修改 K8s 期间无法读取应用记录")
+		}
+		return false, nil, nil
+	})
+
+	if _, err := s.PatchApp(context.Background(), app.ID, user.ID, AppPatch{Image: &image}); err != nil {
+		t.Fatalf("PatchApp() error = %v", err)
+	}
+	if visible != image {
+		t.Errorf("修改 K8s 时数据库镜像 = %q, want %q", visible, image)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+// ListAppPods 列出应用的所有 Pod
+func (s *AppService) ListAppPods(ctx context.Context, appID, userID uint) ([]k8s.PodInfo, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := s.adapter.ListAppPods(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return nil, k8sError(err)
+	}
+	return pods, nil
+}
+
+// GetAppPod 获取应用的指定 Pod
+func (s *AppService) GetAppPod(ctx context.Context, appID, userID uint, podName string) (*k8s.PodDetail, error) {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := s.adapter.GetAppPod(ctx, app.Name, app.Namespace, podName)
+	if err != nil {
+		return nil, podError(err)
+	}
+	return pod, nil
+}
+
+// DeleteAppPod 删除应用的指定 Pod，由工作负载重新创建，用于恢复卡住的单个副本
+func (s *AppService) DeleteAppPod(ctx context.Context, appID, userID uint, podName string) error {
+	app, err := s.getAppWithPermission(appID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyOwnership(ctx, app); err != nil {
+		return err
+	}
+
+	if err := s.adapter.DeleteAppPod(ctx, app.Name, app.Namespace, podName); err != nil {
+		return podError(err)
+	}
+	return nil
+}
+
+// podError 将 Pod 不存在或不属于应用转换为 ErrNotFound，其余按 K8s 错误处理
+func podError(err error) error {
+	if errors.Is(err, k8s.ErrPodNotFound) {
+		return errcode.NewWithMsg(errcode.ErrNotFound, err.Error())
+	}
+	return k8sError(err)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+// quotaLocks 按用户串行化"检查配额—写入数据库"，避免同一用户的并发请求都通过检查后各自写入而超出配额。
+// 包级共享，所有 AppService 实例使用同一组锁；多个 Astro 实例之间不互斥
+var quotaLocks = newUserLocks()
+
+// checkQuota 检查用户新增应用数和副本数后是否超出配额
+// 用户表中的配额字段大于 0 时覆盖全局配置；调用方须持有 quotaLocks 中该用户的锁直到写入数据库
+func (s *AppService) checkQuota(userID uint, addApps, addReplicas int) error {
+	cfg := s.cfg.Quota
+	maxApps := cfg.MaxAppsPerUser
+	maxReplicas := cfg.MaxReplicasPerUser
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if user.MaxApps > 0 {
+		maxApps = user.MaxApps
+	}
+	if user.MaxReplicas > 0 {
+		maxReplicas = user.MaxReplicas
+	}
+
+	if maxApps > 0 && addApps > 0 {
+		count, err := s.repo.CountByUserID(userID)
+		if err != nil {
+			return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		}
+		if count+int64(addApps) > int64(maxApps) {
+			return errcode.NewWithMsg(errcode.ErrQuotaExceeded, fmt.Sprintf("应用数量超出配额限制（最多 %d 个）", maxApps))
+		}
+	}
+
+	if maxReplicas > 0 && addReplicas > 0 {
+		sum, err := s.repo.SumReplicasByUserID(userID)
+		if err != nil {
+			return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		}
+		if sum+int64(addReplicas) > int64(maxReplicas) {
+			return errcode.NewWithMsg(errcode.ErrQuotaExceeded, fmt.Sprintf("副本总数超出配额限制（最多 %d 个）", maxReplicas))
+		}
+	}
+
+	return nil
+}
+
+// checkReplicas 检查单个应用的副本数是否在配置范围内
+func (s *AppService) checkReplicas(replicas int) error {
+	cfg := s.cfg.App
+	if replicas < cfg.MinReplicas || replicas > cfg.MaxReplicas {
+		return errcode.NewWithMsg(errcode.ErrBadRequest,
+			fmt.Sprintf("副本数需在 %d 到 %d 之间", cfg.MinReplicas, cfg.MaxReplicas))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"gorm.io/gorm"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newSlowWriteAppService 同 newTestAppService，但写入应用记录前等待一段时间，
+// 放大配额检查与写入之间的窗口，使未串行化的并发请求都能通过检查
+func newSlowWriteAppService(t *testing.T, cfg *config.Config) (*AppService, *model.User) {
+	t.Helper()
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	cfg.App.DefaultReplicas = 1
+	cfg.App.MaxReplicas = 10
+	user := &model.User{Username: "alice", Password: "x", Email: "alice@example.com", Role: model.RoleUser}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	slow := func(tx *gorm.DB) {
+		if tx.Statement.Table == "apps" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	if err := db.Callback().Create().Before("gorm:create").Register("test:slow_create", slow); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("test:slow_update", slow); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+	ctr := &container.Container{
+		Config:  cfg,
+		DB:      db,
+		Adapter: k8s.NewClientGoAdapter(fake.NewSimpleClientset(), nil),
+	}
+	return NewAppService(ctr), user
+}
+
+// TestQuotaConcurrent 同一用户的并发请求不能同时通过配额检查而超出配额
+func TestQuotaConcurrent(t *testing.T) {
+	const workers = 8
+	tests := []struct {
+		name string
+		run  func(s *AppService, userID uint, i int) error
+		// 配额：应用数与副本总数
+		maxApps, maxReplicas int
+		setup                int // 预先创建的单副本应用数
+		minOK, maxOK         int // 成功次数范围
+	}{
+		{"并发创建应用", func(s *AppService, userID uint, i int) error {
+			_, err := s.CreateApp(context.Background(), CreateAppRequest{
+				Name: fmt.Sprintf("app-%d", i), Image: "nginx:latest", Port: 80, UserID: userID,
+			})
+			return err
+		}, 3, 0, 0, 3, 3},
+		// 配额逐个应用检查，并发的应用组可能都只创建了部分应用而整体回滚，但不会超出配额
+		{"并发创建应用组", func(s *AppService, userID uint, i int) error {
+			_, err := s.CreateStack(context.Background(), CreateStackRequest{Name: fmt.Sprintf("stack-%d", i), UserID: userID, Apps: []CreateAppRequest{
+				{Name: fmt.Sprintf("web-%d", i), Image: "nginx:latest", Port: 80},
+				{Name: fmt.Sprintf("db-%d", i), Image: "postgres:16", Port: 5432},
+			}})
+			return err
+		}, 4, 0, 0, 1, 2},
+		// 两个应用各扩到 3 副本只能成功一个，先成功的应用后续请求副本数不变仍然成功
+		{"并发扩容", func(s *AppService, userID uint, i int) error {
+			return s.ScaleApp(context.Background(), uint(i%2+1), userID, 3)
+		}, 0, 5, 2, workers / 2, workers / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Quota: config.QuotaConfig{MaxAppsPerUser: tt.maxApps, MaxReplicasPerUser: tt.maxReplicas}}
+			s, user := newSlowWriteAppService(t, cfg)
+			for i := 0; i < tt.setup; i++ {
+				createTestApp(t, s, user.ID, fmt.Sprintf("base-%d", i), 1)
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			ok := 0
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := tt.run(s, user.ID, i); err == nil {
+						mu.Lock()
+						ok++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			if ok < tt.minOK || ok > tt.maxOK {
+				t.Errorf("成功 %d 次, want [%d, %d]", ok, tt.minOK, tt.maxOK)
+			}
+
+			apps, err := s.repo.CountByUserID(user.ID)
+			if err != nil {
+				t.Fatalf("统计应用数失败: %v", err)
+			}
+			replicas, err := s.repo.SumReplicasByUserID(user.ID)
+			if err != nil {
+				t.Fatalf("统计副本数失败: %v", err)
+			}
+			if tt.maxApps > 0 && apps > int64(tt.maxApps) {
+				t.Errorf("应用数 = %d, 超出配额 %d", apps, tt.maxApps)
+			}
+			if tt.maxReplicas > 0 && replicas > int64(tt.maxReplicas) {
+				t.Errorf("副本总数 = %d, 超出配额 %d", replicas, tt.maxReplicas)
+			}
+		})
+	}
+}
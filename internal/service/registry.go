@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// registryTestTimeout 镜像仓库连通性检查的总超时时间
+const registryTestTimeout = 10 * time.Second
+
+// dockerHubRegistry Docker Hub 的 API 地址，其认证服务固定为 dockerHubAuth
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubAuth     = "auth.docker.io"
+)
+
+// blockedRegistryNets 除回环、私有、链路本地外，不允许连接的其他保留网段
+var blockedRegistryNets = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级 NAT，部分云厂商的元数据服务位于此网段
+	"192.0.0.0/24",  // IETF 协议分配
+)
+
+// RegistryService 镜像仓库服务
+type RegistryService struct {
+	client *http.Client
+}
+
+// NewRegistryService 创建镜像仓库服务
+// 检查地址由用户提供，连接时拒绝回环、私有、链路本地（含云元数据）等内部地址，
+// 在 DNS 解析之后按实际连接的 IP 判断，重定向同样受限；不使用环境变量中的代理，避免绕过检查
+func NewRegistryService() *RegistryService {
+	dialer := &net.Dialer{Timeout: registryTestTimeout, Control: refuseInternalAddress}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: registryTestTimeout,
+		ForceAttemptHTTP2:   true,
+	}
+	return &RegistryService{
+		client: &http.Client{Timeout: registryTestTimeout, Transport: transport},
+	}
+}
+
+// errInternalAddress 目标解析到内部地址
+var errInternalAddress = errors.New("不允许访问内部地址")
+
+// refuseInternalAddress 在建立连接前检查目标 IP，拒绝内部地址
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", errInternalAddress, host)
+	}
+	return nil
+}
+
+// isInternalIP 判断 IP 是否为回环、私有、链路本地（含 169.254.169.254 元数据地址）、未指定、组播或其他保留地址
+func isInternalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, n := range blockedRegistryNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// mustParseCIDRs 解析固定的网段列表
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// registryUnreachable 返回不含上游细节的无法访问错误，具体原因只记录在服务端日志中，
+// 避免接口被用于探测内部网络
+func registryUnreachable(registry string, cause error) error {
+	logger.Info("镜像仓库连通性检查失败", zap.String("registry", registry), zap.Error(cause))
+	return errcode.New(errcode.ErrRegistryUnreachable)
+}
+
+// TestConnection 使用给定凭证访问仓库的 /v2/ 接口验证连通性和认证，凭证仅用于本次请求，不做保存
+// 支持 Basic 认证与 Docker Registry Token 认证（Bearer challenge）
+func (s *RegistryService) TestConnection(ctx context.Context, registry, username, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, registryTestTimeout)
+	defer cancel()
+
+	base, err := normalizeRegistryURL(registry)
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrBadRequest, err.Error())
+	}
+
+	resp, err := s.get(ctx, base+"/v2/", "", "")
+	if err != nil {
+		return registryUnreachable(base, err)
+	}
+	if resp.StatusCode == http.StatusOK && username == "" {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return registryUnreachable(base, fmt.Errorf("仓库返回异常状态码 %d", resp.StatusCode))
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	scheme, params := parseAuthChallenge(challenge)
+	switch {
+	case strings.EqualFold(scheme, "bearer"):
+		return s.checkTokenAuth(ctx, base, params, username, password)
+	case strings.EqualFold(scheme, "basic"):
+		return s.checkBasicAuth(ctx, base, username, password)
+	case resp.StatusCode == http.StatusOK:
+		// 仓库允许匿名访问且未提示认证方式，无法进一步校验凭证
+		return nil
+	default:
+		return registryUnreachable(base, fmt.Errorf("无法识别仓库的认证方式: %s", challenge))
+	}
+}
+
+// checkTokenAuth 按 Bearer challenge 向认证服务申请 Token
+// 认证地址来自仓库响应，只有与仓库同一主机且使用 https 时才发送凭证
+func (s *RegistryService) checkTokenAuth(ctx context.Context, base string, params map[string]string, username, password string) error {
+	realm := params["realm"]
+	if realm == "" {
+		return registryUnreachable(base, errors.New("仓库认证信息缺少 realm"))
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return registryUnreachable(base, fmt.Errorf("无效的认证地址 %s: %w", realm, err))
+	}
+	if username != "" && !realmAllowed(base, tokenURL) {
+		logger.Warn("镜像仓库认证地址与仓库不一致或未使用 https，未发送凭证",
+			zap.String("registry", base), zap.String("realm", realm))
+		return errcode.NewWithMsg(errcode.ErrRegistryUnreachable, "仓库的认证地址与仓库主机不一致或未使用 https，为保护凭证未发送")
+	}
+	if service := params["service"]; service != "" {
+		query := tokenURL.Query()
+		query.Set("service", service)
+		tokenURL.RawQuery = query.Encode()
+	}
+
+	resp, err := s.get(ctx, tokenURL.String(), username, password)
+	if err != nil {
+		return registryUnreachable(base, err)
+	}
+	return checkAuthStatus(base, resp.StatusCode)
+}
+
+// realmAllowed 认证地址是否可以接收凭证：必须为 https，且与仓库同一主机；Docker Hub 的认证服务为固定的 auth.docker.io
+func realmAllowed(base string, realm *url.URL) bool {
+	if realm.Scheme != "https" {
+		return false
+	}
+	registry, err := url.Parse(base)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(realm.Hostname(), registry.Hostname()) {
+		return true
+	}
+	return registry.Host == dockerHubRegistry && strings.EqualFold(realm.Host, dockerHubAuth)
+}
+
+// checkBasicAuth 使用 Basic 认证重新访问 /v2/
+func (s *RegistryService) checkBasicAuth(ctx context.Context, base, username, password string) error {
+	if username == "" {
+		return errcode.NewWithMsg(errcode.ErrRegistryAuthFailed, "仓库需要认证，请提供用户名和密码")
+	}
+	resp, err := s.get(ctx, base+"/v2/", username, password)
+	if err != nil {
+		return registryUnreachable(base, err)
+	}
+	return checkAuthStatus(base, resp.StatusCode)
+}
+
+// get 发送 GET 请求并丢弃响应体，username 非空时附带 Basic 认证
+func (s *RegistryService) get(ctx context.Context, endpoint, username, password string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkAuthStatus 将认证请求的状态码转换为错误
+func checkAuthStatus(base string, status int) error {
+	switch {
+	case status == http.StatusOK:
+		return nil
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return errcode.New(errcode.ErrRegistryAuthFailed)
+	default:
+		return registryUnreachable(base, fmt.Errorf("认证服务返回异常状态码 %d", status))
+	}
+}
+
+// normalizeRegistryURL 将仓库地址规范化为 scheme://host，未指定 scheme 时使用 https
+func normalizeRegistryURL(registry string) (string, error) {
+	registry = strings.TrimSpace(registry)
+	if !strings.Contains(registry, "://") {
+		registry = "https://" + registry
+	}
+	u, err := url.Parse(registry)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("无效的仓库地址: %s", registry)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("仓库地址仅支持 http/https: %s", registry)
+	}
+	// Docker Hub 的 API 地址与镜像名中的域名不同
+	if u.Host == "docker.io" || u.Host == "index.docker.io" {
+		u.Host = dockerHubRegistry
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// parseAuthChallenge 解析 WWW-Authenticate 头，如 Bearer realm="...",service="..."
+func parseAuthChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+	for _, part := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return scheme, params
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cuihe500/astro/pkg/errcode"
+)
+
+func TestIsInternalIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.100.100.200", true},
+		{"0.0.0.0", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"::ffff:127.0.0.1", true},
+		{"224.0.0.1", true},
+		{"8.8.8.8", false},
+		{"104.18.0.1", false},
+		{"2606:4700::1111", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isInternalIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("isInternalIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealmAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		realm string
+		want  bool
+	}{
+		{"同主机 https", "https://registry.example.com", "https://registry.example.com/token", true},
+		{"同主机不同端口", "https://registry.example.com", "https://registry.example.com:5001/token", true},
+		{"同主机 http", "https://registry.example.com", "http://registry.example.com/token", false},
+		{"不同主机", "https://registry.example.com", "https://evil.example.net/token", false},
+		{"子域名", "https://registry.example.com", "https://auth.registry.example.com/token", false},
+		{"Docker Hub", "https://registry-1.docker.io", "https://auth.docker.io/token", true},
+		{"非 Docker Hub 使用 Docker 认证", "https://registry.example.com", "https://auth.docker.io/token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realm, err := url.Parse(tt.realm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := realmAllowed(tt.base, realm); got != tt.want {
+				t.Errorf("realmAllowed(%s, %s) = %v, want %v", tt.base, tt.realm, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestConnectionRefusesInternalAddress(t *testing.T) {
+	requested := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	err := NewRegistryService().TestConnection(context.Background(), srv.URL, "", "")
+	e := errcode.FromError(err)
+	if e.Code != errcode.ErrRegistryUnreachable {
+		t.Fatalf("code = %d, want %d", e.Code, errcode.ErrRegistryUnreachable)
+	}
+	// 只返回通用错误，不暴露上游地址或网络错误
+	if e.Msg != errcode.ErrRegistryUnreachable.Message() {
+		t.Errorf("msg = %q, want generic message", e.Msg)
+	}
+	if requested {
+		t.Error("请求不应到达回环地址上的服务")
+	}
+}
+
+func TestTestConnectionCredentialRealm(t *testing.T) {
+	realmHits := 0
+	realm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		realmHits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer realm.Close()
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+realm.URL+`/token",service="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer registry.Close()
+
+	// 测试服务位于回环地址，使用不限制地址的客户端只验证凭证发送规则
+	s := &RegistryService{client: registry.Client()}
+
+	tests := []struct {
+		name     string
+		username string
+		wantCode errcode.Code
+		wantHits int
+	}{
+		// http 认证地址不接收凭证
+		{"带凭证", "robot", errcode.ErrRegistryUnreachable, 0},
+		// 匿名检查不发送凭证，可以访问认证地址
+		{"匿名", "", errcode.Success, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realmHits = 0
+			err := s.TestConnection(context.Background(), registry.URL, tt.username, "secret")
+			code := errcode.Success
+			if err != nil {
+				code = errcode.FromError(err).Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("err = %v, want code %d", err, tt.wantCode)
+			}
+			if realmHits != tt.wantHits {
+				t.Errorf("realm hits = %d, want %d", realmHits, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// defaultRestartAlertInterval 同一应用两次重启告警的默认最短间隔
+const defaultRestartAlertInterval = 10 * time.Minute
+
+// defaultRestartCheckInterval 后台检查重启次数的默认周期
+const defaultRestartCheckInterval = time.Minute
+
+// restartCheckBatch 后台检查时每批同步的应用数
+const restartCheckBatch = 20
+
+// restartAlertTimeout 发送告警 Webhook 的超时时间
+const restartAlertTimeout = 5 * time.Second
+
+// restartAlertClient 发送告警 Webhook 的客户端，地址由管理员配置，不做内部地址限制
+var restartAlertClient = &http.Client{Timeout: restartAlertTimeout}
+
+// RestartAlert 容器重启告警内容，即 Webhook 的请求体
+type RestartAlert struct {
+	AppID        uint      `json:"app_id"`
+	AppName      string    `json:"app_name"`
+	Namespace    string    `json:"namespace"`
+	UserID       uint      `json:"user_id"`
+	RestartCount int32     `json:"restart_count"` // 当前所有 Pod 容器重启次数之和
+	Increase     int32     `json:"increase"`      // 自上次告警以来新增的重启次数
+	Time         time.Time `json:"time"`
+}
+
+// RunRestartChecks 按 check_interval 定期同步所有未挂起应用的状态以检查重启告警，ctx 取消后返回。
+// 未开启重启告警时直接返回
+func (s *AppService) RunRestartChecks(ctx context.Context) {
+	cfg := s.cfg.App.RestartAlert
+	if cfg.Threshold <= 0 {
+		return
+	}
+	interval := defaultRestartCheckInterval
+	if d, err := time.ParseDuration(cfg.CheckInterval); err == nil && d > 0 {
+		interval = d
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkAllRestarts(ctx)
+		}
+	}
+}
+
+// checkAllRestarts 分批同步所有未挂起应用的状态，同步时检查重启告警；每批并发同步，超时后不再等待
+func (s *AppService) checkAllRestarts(ctx context.Context) {
+	var afterID uint
+	for ctx.Err() == nil {
+		apps, err := s.repo.ListUnsuspendedAfter(afterID, restartCheckBatch)
+		if err != nil {
+			logger.Warn("查询待检查重启的应用失败", zap.Error(err))
+			return
+		}
+		if len(apps) == 0 {
+			return
+		}
+		afterID = apps[len(apps)-1].ID
+
+		batchCtx, cancel := context.WithTimeout(ctx, liveStatusTimeout)
+		var wg sync.WaitGroup
+		for i := range apps {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.syncAppStatus(batchCtx, &apps[i])
+			}()
+		}
+		wg.Wait()
+		cancel()
+	}
+}
+
+// checkRestarts 同步状态时检查容器重启次数，相对基线的增量达到阈值且距上次告警超过间隔时告警。
+// 重启次数小于基线说明 Pod 已被替换，重新计数
+func (s *AppService) checkRestarts(app *model.App, restartCount int32) {
+	cfg := s.cfg.App.RestartAlert
+	if cfg.Threshold <= 0 {
+		return
+	}
+
+	baseline := app.AlertedRestartCount
+	if restartCount < baseline {
+		_, _ = s.repo.UpdateRestartBaseline(app.ID, baseline, restartCount, nil)
+		return
+	}
+	increase := restartCount - baseline
+	if int(increase) < cfg.Threshold {
+		return
+	}
+	now := time.Now()
+	interval := defaultRestartAlertInterval
+	if d, err := time.ParseDuration(cfg.Interval); err == nil && d > 0 {
+		interval = d
+	}
+	if app.RestartAlertedAt != nil && now.Sub(*app.RestartAlertedAt) < interval {
+		return
+	}
+	// 基线已被其他同步更新时放弃，避免重复告警
+	if ok, err := s.repo.UpdateRestartBaseline(app.ID, baseline, restartCount, &now); err != nil || !ok {
+		return
+	}
+
+	alert := RestartAlert{
+		AppID:        app.ID,
+		AppName:      app.Name,
+		Namespace:    app.Namespace,
+		UserID:       app.UserID,
+		RestartCount: restartCount,
+		Increase:     increase,
+		Time:         now,
+	}
+	logger.Warn("应用容器频繁重启",
+		zap.Uint("app_id", alert.AppID),
+		zap.String("app", alert.AppName),
+		zap.String("namespace", alert.Namespace),
+		zap.Int32("restart_count", alert.RestartCount),
+		zap.Int32("increase", alert.Increase))
+	if cfg.Webhook != "" {
+		go func() {
+			if err := sendRestartAlert(context.Background(), cfg.Webhook, alert); err != nil {
+				logger.Warn("发送重启告警失败", zap.Uint("app_id", alert.AppID), zap.Error(err))
+			}
+		}()
+	}
+}
+
+// sendRestartAlert 以 JSON POST 发送告警，非 2xx 响应视为失败
+func sendRestartAlert(ctx context.Context, webhook string, alert RestartAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := restartAlertClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
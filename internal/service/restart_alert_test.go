@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncAppStatusRestartAlert(t *testing.T) {
+	recent := time.Now().Add(-time.Minute)
+	stale := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name         string
+		threshold    int
+		baseline     int32
+		alertedAt    *time.Time
+		restarts     int32
+		wantAlert    bool
+		wantBaseline int32
+	}{
+		{"未开启告警", 0, 0, nil, 10, false, 0},
+		{"增量未达阈值", 3, 0, nil, 2, false, 0},
+		{"增量达到阈值", 3, 0, nil, 3, true, 3},
+		{"相对基线计算增量", 3, 5, &stale, 7, false, 5},
+		{"间隔内不重复告警", 3, 0, &recent, 5, false, 0},
+		{"超过间隔再次告警", 3, 2, &stale, 6, true, 6},
+		{"Pod 替换后重新计数", 3, 8, &stale, 1, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := make(chan RestartAlert, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var alert RestartAlert
+				if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+					t.Errorf("解析告警失败: %v", err)
+				}
+				alerts <- alert
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{App: config.AppConfig{RestartAlert: config.RestartAlertConfig{
+				Threshold: tt.threshold, Interval: "10m", Webhook: server.URL,
+			}}}
+			s, user, client := newTestAppServiceWithClient(t, cfg)
+			ctx := context.Background()
+			app := createTestApp(t, s, user.ID, "api", 1)
+			app.AlertedRestartCount = tt.baseline
+			app.RestartAlertedAt = tt.alertedAt
+			if err := s.repo.UpdateFields(app, "alerted_restart_count", "restart_alerted_at"); err != nil {
+				t.Fatalf("更新基线失败: %v", err)
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: app.Namespace, Labels: map[string]string{
+					"app": app.Name, k8s.AppIDLabel: strconv.FormatUint(uint64(app.ID), 10),
+				}},
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: app.Name, RestartCount: tt.restarts}},
+				},
+			}
+			if _, err := client.CoreV1().Pods(app.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("创建 Pod 失败: %v", err)
+			}
+
+			s.syncAppStatus(ctx, app)
+
+			select {
+			case alert := <-alerts:
+				if !tt.wantAlert {
+					t.Fatalf("不应告警，收到 %+v", alert)
+				}
+				if alert.AppID != app.ID || alert.RestartCount != tt.restarts || alert.Increase != tt.restarts-tt.baseline {
+					t.Errorf("告警 = %+v, want app %d restart_count %d increase %d", alert, app.ID, tt.restarts, tt.restarts-tt.baseline)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tt.wantAlert {
+					t.Fatal("未收到告警")
+				}
+			}
+			stored, err := s.repo.GetByID(app.ID)
+			if err != nil {
+				t.Fatalf("查询应用失败: %v", err)
+			}
+			if stored.AlertedRestartCount != tt.wantBaseline {
+				t.Errorf("基线 = %d, want %d", stored.AlertedRestartCount, tt.wantBaseline)
+			}
+		})
+	}
+}
+
+func TestRunRestartChecks(t *testing.T) {
+	alerts := make(chan RestartAlert, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert RestartAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("解析告警失败: %v", err)
+		}
+		alerts <- alert
+	}))
+	defer server.Close()
+
+	// 创建应用时的异步同步不开启告警，确保告警只能由后台检查触发
+	s, user, client := newTestAppServiceWithClient(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 挂起的应用不同步状态，不应告警
+	active := createTestApp(t, s, user.ID, "api", 1)
+	suspended := createTestApp(t, s, user.ID, "worker", 1)
+	if err := s.repo.UpdateSuspended(suspended.ID, true); err != nil {
+		t.Fatalf("挂起应用失败: %v", err)
+	}
+	for _, app := range []*model.App{active, suspended} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: app.Name + "-0", Namespace: app.Namespace, Labels: map[string]string{
+				"app": app.Name, k8s.AppIDLabel: strconv.FormatUint(uint64(app.ID), 10),
+			}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: app.Name, RestartCount: 5}}},
+		}
+		if _, err := client.CoreV1().Pods(app.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("创建 Pod 失败: %v", err)
+		}
+	}
+
+	alertCfg := *s.cfg
+	alertCfg.App.RestartAlert = config.RestartAlertConfig{Threshold: 3, CheckInterval: "10ms", Webhook: server.URL}
+	checker := *s
+	checker.cfg = &alertCfg
+	go checker.RunRestartChecks(ctx)
+	select {
+	case alert := <-alerts:
+		if alert.AppID != active.ID || alert.RestartCount != 5 {
+			t.Errorf("告警 = %+v, want app %d restart_count 5", alert, active.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("后台检查未触发告警")
+	}
+	select {
+	case alert := <-alerts:
+		t.Errorf("不应再次告警，收到 %+v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/cuihe500/astro/internal/k8s"
+	"github.com/cuihe500/astro/pkg/config"
+)
+
+// SecurityContextOverride 创建应用时对默认安全上下文的覆盖，nil 字段沿用配置默认值
+type SecurityContextOverride struct {
+	RunAsNonRoot           *bool
+	RunAsUser              *int64
+	ReadOnlyRootFilesystem *bool
+	DropCapabilities       []string
+}
+
+// resolveSecurityOptions 合并配置默认值与请求覆盖，均未设置时返回 nil
+func resolveSecurityOptions(defaults *config.SecurityContextConfig, override *SecurityContextOverride) *k8s.SecurityOptions {
+	opts := &k8s.SecurityOptions{
+		RunAsNonRoot:           defaults.RunAsNonRoot,
+		RunAsUser:              defaults.RunAsUser,
+		ReadOnlyRootFilesystem: defaults.ReadOnlyRootFilesystem,
+		DropCapabilities:       defaults.DropCapabilities,
+	}
+
+	if override != nil {
+		if override.RunAsNonRoot != nil {
+			opts.RunAsNonRoot = *override.RunAsNonRoot
+		}
+		if override.RunAsUser != nil {
+			opts.RunAsUser = override.RunAsUser
+		}
+		if override.ReadOnlyRootFilesystem != nil {
+			opts.ReadOnlyRootFilesystem = *override.ReadOnlyRootFilesystem
+		}
+		if override.DropCapabilities != nil {
+			opts.DropCapabilities = override.DropCapabilities
+		}
+	}
+
+	if !opts.RunAsNonRoot && opts.RunAsUser == nil && !opts.ReadOnlyRootFilesystem && len(opts.DropCapabilities) == 0 {
+		return nil
+	}
+	return opts
+}
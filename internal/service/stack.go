@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// stackRefPattern 应用组内引用其他应用的占位符，如 ${svc:db}，创建时替换为该应用 Service 的集群内域名
+var stackRefPattern = regexp.MustCompile(`\$\{svc:([^}]*)\}`)
+
+// StackReferences 返回字符串中引用的应用名，供 handler 在创建前校验引用目标
+func StackReferences(value string) []string {
+	matches := stackRefPattern.FindAllStringSubmatch(value, -1)
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// CreateStackRequest 创建应用组请求
+type CreateStackRequest struct {
+	Name   string
+	UserID uint
+	Apps   []CreateAppRequest // 按顺序创建，被引用的应用无需排在前面
+}
+
+// StackDetail 应用组及其应用
+type StackDetail struct {
+	model.Stack
+	Apps []model.App `json:"apps"`
+}
+
+// CreateStack 创建应用组，所有应用校验通过后按顺序创建，任一应用失败时删除已创建的应用与应用组
+func (s *AppService) CreateStack(ctx context.Context, req CreateStackRequest) (*StackDetail, error) {
+	if err := s.checkStack(req); err != nil {
+		return nil, err
+	}
+
+	namespace, err := s.resolveNamespace(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make(map[string]string, len(req.Apps))
+	for _, app := range req.Apps {
+		hosts[app.Name] = fmt.Sprintf("%s.%s.svc.cluster.local", app.Name, namespace)
+	}
+
+	stack := &model.Stack{Name: req.Name, UserID: req.UserID, Namespace: namespace}
+	if err := s.stackRepo.Create(stack); err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	detail := &StackDetail{Stack: *stack, Apps: make([]model.App, 0, len(req.Apps))}
+	for _, appReq := range req.Apps {
+		appReq.UserID = req.UserID
+		appReq.StackID = &stack.ID
+		resolveStackReferences(&appReq, hosts)
+
+		app, err := s.createApp(ctx, appReq)
+		if err != nil {
+			s.rollbackStack(ctx, stack.ID, detail.Apps)
+			e := errcode.FromError(err)
+			return nil, &errcode.Error{Code: e.Code, Msg: fmt.Sprintf("创建应用 %s 失败: %s", appReq.Name, e.Msg), Data: e.Data}
+		}
+		detail.Apps = append(detail.Apps, *app)
+	}
+	return detail, nil
+}
+
+// checkStack 创建前整体检查：应用组名、所有应用名与配额，避免创建到一半才失败。
+// 这里的配额检查只是预检，并发请求仍可能同时通过；逐个创建应用时会在配额锁内再次检查，超出时整组回滚
+func (s *AppService) checkStack(req CreateStackRequest) error {
+	_, err := s.stackRepo.GetByUserAndName(req.UserID, req.Name)
+	if err == nil {
+		return errcode.New(errcode.ErrStackExists)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	totalReplicas := 0
+	for _, app := range req.Apps {
+		if err := s.checkAppName(req.UserID, app.Name); err != nil {
+			e := errcode.FromError(err)
+			return errcode.NewWithMsg(e.Code, fmt.Sprintf("%s: %s", app.Name, e.Msg))
+		}
+		replicas := s.cfg.App.DefaultReplicas
+		if app.Replicas != nil {
+			replicas = *app.Replicas
+		}
+		if err := s.checkReplicas(replicas); err != nil {
+			return err
+		}
+		totalReplicas += replicas
+	}
+	return s.checkQuota(req.UserID, len(req.Apps), totalReplicas)
+}
+
+// rollbackStack 删除已创建的应用与应用组记录，尽力而为，K8s 资源删除失败时记录日志
+func (s *AppService) rollbackStack(ctx context.Context, stackID uint, created []model.App) {
+	for i := len(created) - 1; i >= 0; i-- {
+		app := created[i]
+		if err := s.adapter.DeleteApp(ctx, app.Name, app.Namespace, app.Kind); err != nil {
+			logger.Warn("回滚应用组时删除应用失败",
+				zap.String("app", app.Name),
+				zap.String("namespace", app.Namespace),
+				zap.Error(err))
+		}
+		_ = s.repo.Delete(app.ID)
+	}
+	_ = s.stackRepo.Delete(stackID)
+}
+
+// resolveStackReferences 将请求中的 ${svc:<应用名>} 替换为对应 Service 的集群内域名
+func resolveStackReferences(req *CreateAppRequest, hosts map[string]string) {
+	replace := func(value string) string {
+		return stackRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			if host, ok := hosts[stackRefPattern.FindStringSubmatch(ref)[1]]; ok {
+				return host
+			}
+			return ref
+		})
+	}
+
+	if len(req.PreStopCommand) > 0 {
+		command := make([]string, len(req.PreStopCommand))
+		for i, arg := range req.PreStopCommand {
+			command[i] = replace(arg)
+		}
+		req.PreStopCommand = command
+	}
+	if len(req.Env) > 0 {
+		env := make([]model.EnvVar, len(req.Env))
+		for i, e := range req.Env {
+			env[i] = model.EnvVar{Name: e.Name, Value: replace(e.Value)}
+		}
+		req.Env = env
+	}
+	req.ServiceAnnotations = replaceMapValues(req.ServiceAnnotations, replace)
+	req.DeploymentAnnotations = replaceMapValues(req.DeploymentAnnotations, replace)
+	// 域名只包含字母、数字、点和连字符，直接替换 JSON 文本不会破坏其结构
+	if strings.Contains(string(req.Metadata), "${svc:") {
+		req.Metadata = []byte(replace(string(req.Metadata)))
+	}
+}
+
+// replaceMapValues 返回替换了所有值的新 map，不修改原 map
+func replaceMapValues(m map[string]string, replace func(string) string) map[string]string {
+	if len(m) == 0 {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = replace(v)
+	}
+	return out
+}
+
+// GetStack 获取应用组及其应用
+func (s *AppService) GetStack(ctx context.Context, stackID, userID uint) (*StackDetail, error) {
+	stack, err := s.getStackWithPermission(stackID, userID)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := s.repo.ListByStack(stack.ID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return &StackDetail{Stack: *stack, Apps: apps}, nil
+}
+
+// GetStacks 分页获取用户的应用组
+func (s *AppService) GetStacks(ctx context.Context, userID uint, page, pageSize int) ([]model.Stack, int64, error) {
+	stacks, total, err := s.stackRepo.ListByUser(userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return stacks, total, nil
+}
+
+// ListStackApps 获取应用组内的应用，与应用列表一样在后台异步同步状态
+func (s *AppService) ListStackApps(ctx context.Context, stackID, userID uint) ([]AppListItem, error) {
+	stack, err := s.getStackWithPermission(stackID, userID)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := s.repo.ListByStack(stack.ID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	stale := s.breaker.Open()
+	items := make([]AppListItem, 0, len(apps))
+	for i := range apps {
+		go s.syncAppStatus(context.Background(), &apps[i])
+		items = append(items, AppListItem{App: apps[i], StatusStale: stale})
+	}
+	return items, nil
+}
+
+// OperateStack 对应用组内所有应用执行批量操作，语义与 BatchOperateApps 相同，单个应用失败不影响其他应用
+func (s *AppService) OperateStack(ctx context.Context, stackID, userID uint, action string) ([]BatchResult, error) {
+	stack, err := s.getStackWithPermission(stackID, userID)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := s.repo.ListByStack(stack.ID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	ids := make([]uint, 0, len(apps))
+	for _, app := range apps {
+		ids = append(ids, app.ID)
+	}
+	return s.BatchOperateApps(ctx, userID, ids, action)
+}
+
+// DeleteStack 删除应用组及其所有应用；某个应用删除失败时保留应用组，已删除的应用不恢复，可重试
+func (s *AppService) DeleteStack(ctx context.Context, stackID, userID uint) error {
+	stack, err := s.getStackWithPermission(stackID, userID)
+	if err != nil {
+		return err
+	}
+	apps, err := s.repo.ListByStack(stack.ID)
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	for _, app := range apps {
+		if err := s.DeleteApp(ctx, app.ID, userID); err != nil {
+			e := errcode.FromError(err)
+			return &errcode.Error{Code: e.Code, Msg: fmt.Sprintf("删除应用 %s 失败: %s", app.Name, e.Msg), Data: e.Data}
+		}
+	}
+
+	if err := s.stackRepo.Delete(stack.ID); err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// getStackWithPermission 获取应用组并检查归属
+func (s *AppService) getStackWithPermission(stackID, userID uint) (*model.Stack, error) {
+	stack, err := s.stackRepo.GetByID(stackID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrStackNotFound)
+		}
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if stack.UserID != userID {
+		return nil, errcode.New(errcode.ErrForbidden)
+	}
+	return stack, nil
+}
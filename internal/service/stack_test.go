@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuihe500/astro/internal/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateStackEnvReferences(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"整个值为引用", "${svc:db}", "db.astro-user-1.svc.cluster.local"},
+		{"引用嵌在连接串中", "postgres://${svc:db}:5432/shop", "postgres://db.astro-user-1.svc.cluster.local:5432/shop"},
+		{"不含引用", "info", "info"},
+		{"引用不在应用组中保持原样", "${svc:cache}", "${svc:cache}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, user, client := newTestAppServiceWithClient(t, nil)
+			ctx := context.Background()
+			_, err := s.CreateStack(ctx, CreateStackRequest{Name: "shop", UserID: user.ID, Apps: []CreateAppRequest{
+				{Name: "api", Image: "nginx:latest", Port: 80, Env: []model.EnvVar{{Name: "TARGET", Value: tt.value}}},
+				{Name: "db", Image: "postgres:16", Port: 5432},
+			}})
+			if err != nil {
+				t.Fatalf("创建应用组失败: %v", err)
+			}
+
+			app, err := s.repo.GetByUserAndName(user.ID, "api")
+			if err != nil {
+				t.Fatalf("查询应用失败: %v", err)
+			}
+			if len(app.Env) != 1 || app.Env[0].Value != tt.want {
+				t.Errorf("数据库环境变量 = %v, want TARGET=%s", app.Env, tt.want)
+			}
+			deployment, err := client.AppsV1().Deployments(app.Namespace).Get(ctx, "api", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Deployment 不存在: %v", err)
+			}
+			env := deployment.Spec.Template.Spec.Containers[0].Env
+			if len(env) != 1 || env[0].Name != "TARGET" || env[0].Value != tt.want {
+				t.Errorf("Deployment 环境变量 = %v, want TARGET=%s", env, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/astro/internal/container"
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TokenPrefix 个人访问令牌前缀，用于和 JWT 区分
+const TokenPrefix = "astro_pat_"
+
+type TokenService struct {
+	repo *repository.TokenRepository
+}
+
+func NewTokenService(c *container.Container) *TokenService {
+	return &TokenService{
+		repo: repository.NewTokenRepository(c.DB),
+	}
+}
+
+// IsPersonalAccessToken 判断凭证是否为个人访问令牌
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
+
+// Create 创建访问令牌，返回仅此一次可见的明文令牌；expiresIn 为 0 表示永不过期
+func (s *TokenService) Create(userID uint, name string, scopes []string, expiresIn time.Duration) (string, *model.PersonalAccessToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+	}
+	plaintext := TokenPrefix + hex.EncodeToString(buf)
+
+	token := &model.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashToken(plaintext),
+		Prefix:    plaintext[:len(TokenPrefix)+4],
+		Scopes:    scopes,
+	}
+	if expiresIn > 0 {
+		expiresAt := time.Now().Add(expiresIn)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.Create(token); err != nil {
+		return "", nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return plaintext, token, nil
+}
+
+// List 获取用户的访问令牌列表
+func (s *TokenService) List(userID uint) ([]model.PersonalAccessToken, error) {
+	tokens, err := s.repo.ListByUserID(userID)
+	if err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return tokens, nil
+}
+
+// Revoke 吊销访问令牌
+func (s *TokenService) Revoke(id, userID uint) error {
+	deleted, err := s.repo.Delete(id, userID)
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if !deleted {
+		return errcode.New(errcode.ErrNotFound)
+	}
+	return nil
+}
+
+// Authenticate 校验明文令牌，返回对应的令牌记录
+func (s *TokenService) Authenticate(plaintext string) (*model.PersonalAccessToken, error) {
+	token, err := s.repo.GetByHash(hashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrTokenInvalid)
+		}
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, errcode.New(errcode.ErrTokenExpired)
+	}
+
+	// 最近使用时间仅用于展示，更新失败不影响认证
+	if err := s.repo.UpdateLastUsed(token.ID, now); err != nil {
+		logger.Warn("更新令牌使用时间失败", zap.Uint("token_id", token.ID), zap.Error(err))
+	}
+	return token, nil
+}
+
+// hashToken 计算令牌的 SHA-256 摘要
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,44 +2,62 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/cuihe500/astro/internal/container"
 	"github.com/cuihe500/astro/internal/model"
 	"github.com/cuihe500/astro/internal/repository"
 	"github.com/cuihe500/astro/pkg/config"
 	"github.com/cuihe500/astro/pkg/errcode"
+	"github.com/cuihe500/astro/pkg/logger"
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserService struct {
+	cfg  *config.Config
 	repo *repository.UserRepository
 }
 
-func NewUserService() *UserService {
+func NewUserService(c *container.Container) *UserService {
 	return &UserService{
-		repo: repository.NewUserRepository(),
+		cfg:  c.Config,
+		repo: repository.NewUserRepository(c.DB),
 	}
 }
 
 // Register 用户注册
+// 开启 HideUserEnumeration 时，用户名或邮箱已存在也返回成功，避免被用于探测已注册账号
 func (s *UserService) Register(username, password, email string) error {
+	hide := s.cfg.Security.HideUserEnumeration
+
+	if err := checkPasswordPolicy(&s.cfg.Security.PasswordPolicy, password); err != nil {
+		return err
+	}
+
+	// 先加密密码，使用户已存在与否的响应时间一致
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+	}
+
 	// 检查用户是否已存在
-	_, err := s.repo.GetUserByUsername(username)
+	_, err = s.repo.GetUserByUsername(username)
 	if err == nil {
+		if hide {
+			return nil
+		}
 		return errcode.New(errcode.ErrUserExists)
 	}
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return errcode.NewWithMsg(errcode.ErrInternal, err.Error())
-	}
-
 	// 创建用户
 	user := &model.User{
 		Username: username,
@@ -47,52 +65,182 @@ func (s *UserService) Register(username, password, email string) error {
 		Email:    email,
 	}
 	if err := s.repo.CreateUser(user); err != nil {
+		// 邮箱已被占用时唯一索引冲突
+		if hide && errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil
+		}
 		return errcode.NewWithMsg(errcode.ErrRegisterFailed, err.Error())
 	}
 	return nil
 }
 
-// Login 用户登录，返回 token 和用户信息
-func (s *UserService) Login(username, password string) (string, *model.User, error) {
-	// 查询用户
-	user, err := s.repo.GetUserByUsername(username)
+// dummyPasswordHash 用户不存在时用于比对的哈希，使响应时间与密码错误一致，首次使用时生成
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("astro-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Login 用户登录，identifier 可以是用户名或邮箱，返回 token、过期时间和用户信息；remember 为 true 时使用更长的有效期
+func (s *UserService) Login(identifier, password string, remember bool) (string, time.Time, *model.User, error) {
+	user, err := s.findLoginUser(identifier)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil, errcode.New(errcode.ErrLoginFailed)
+			// 用户不存在时同样执行一次哈希比对，避免通过响应时间探测用户名是否存在
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+			return "", time.Time{}, nil, errcode.New(errcode.ErrLoginFailed)
 		}
-		return "", nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+		return "", time.Time{}, nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
 	}
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", nil, errcode.New(errcode.ErrLoginFailed)
+		return "", time.Time{}, nil, errcode.New(errcode.ErrLoginFailed)
 	}
 
 	// 生成 JWT
-	token, err := s.generateToken(user.ID, user.UUID)
+	expire := parseExpire(s.cfg.JWT.Expire, 24*time.Hour)
+	if remember {
+		expire = parseExpire(s.cfg.JWT.RememberExpire, 720*time.Hour)
+	}
+	expiresAt := time.Now().Add(expire)
+	token, err := s.generateToken(user.ID, user.UUID, expiresAt, 0)
 	if err != nil {
-		return "", nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+		return "", time.Time{}, nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
 	}
 
-	return token, user, nil
+	return token, expiresAt, user, nil
 }
 
-// generateToken 生成 JWT token
-func (s *UserService) generateToken(userID uint, uuid string) (string, error) {
-	cfg := config.GlobalConfig.JWT
+// findLoginUser 按登录标识查找用户：包含 @ 时先按邮箱查找，找不到再按用户名查找，
+// 兼容用户名禁止包含 @ 之前注册的账号
+func (s *UserService) findLoginUser(identifier string) (*model.User, error) {
+	if !strings.Contains(identifier, "@") {
+		return s.repo.GetUserByUsername(identifier)
+	}
+	user, err := s.repo.GetUserByEmail(identifier)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.repo.GetUserByUsername(identifier)
+	}
+	return user, err
+}
 
-	// 解析过期时间
-	expire, err := time.ParseDuration(cfg.Expire)
+// GetUser 获取用户信息
+func (s *UserService) GetUser(userID uint) (*model.User, error) {
+	user, err := s.repo.GetUserByID(userID)
 	if err != nil {
-		expire = 24 * time.Hour
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errcode.New(errcode.ErrUserNotFound)
+		}
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
 	}
+	return user, nil
+}
 
+// UpdateEmail 修改用户邮箱
+func (s *UserService) UpdateEmail(userID uint, email string) error {
+	existing, err := s.repo.GetUserByEmail(email)
+	if err == nil {
+		if existing.ID == userID {
+			return nil
+		}
+		return errcode.New(errcode.ErrEmailExists)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+
+	if err := s.repo.UpdateEmail(userID, email); err != nil {
+		// 并发修改时可能在唯一索引上冲突
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errcode.New(errcode.ErrEmailExists)
+		}
+		return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// maxTeamLength 团队名最大长度，与 users.team 列长度一致
+const maxTeamLength = 64
+
+// SetTeam 设置用户所属团队，team 为空表示移出团队。per-team 策略下团队决定新建应用的命名空间，
+// 已有应用仍留在创建时的命名空间
+func (s *UserService) SetTeam(userID uint, team string) (*model.User, error) {
+	team = strings.TrimSpace(team)
+	if len(team) > maxTeamLength {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, fmt.Sprintf("团队名不能超过 %d 个字符", maxTeamLength))
+	}
+	if team != "" && teamNamespaceSuffix(team) == "" {
+		return nil, errcode.NewWithMsg(errcode.ErrBadRequest, "团队名需包含字母或数字")
+	}
+
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTeam(userID, team); err != nil {
+		return nil, errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	user.Team = team
+	return user, nil
+}
+
+// impersonationExpire 模拟登录 Token 的有效期
+const impersonationExpire = time.Hour
+
+// Impersonate 管理员以目标用户身份签发短期 Token，act 声明记录实际操作的管理员，供审计追溯。
+// 不允许模拟自己或其他管理员，避免借此获得他人的管理员权限
+func (s *UserService) Impersonate(actorID, userID uint) (string, time.Time, *model.User, error) {
+	if actorID == userID {
+		return "", time.Time{}, nil, errcode.NewWithMsg(errcode.ErrBadRequest, "不能模拟自己")
+	}
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return "", time.Time{}, nil, err
+	}
+	if user.Role == model.RoleAdmin {
+		return "", time.Time{}, nil, errcode.NewWithMsg(errcode.ErrForbidden, "不能模拟管理员")
+	}
+
+	expiresAt := time.Now().Add(impersonationExpire)
+	token, err := s.generateToken(user.ID, user.UUID, expiresAt, actorID)
+	if err != nil {
+		return "", time.Time{}, nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+	}
+	logger.Warn("管理员签发模拟登录 Token",
+		zap.Uint("actor_id", actorID),
+		zap.Uint("user_id", user.ID),
+		zap.Time("expires_at", expiresAt),
+	)
+	return token, expiresAt, user, nil
+}
+
+// generateToken 生成 JWT token，actorID 不为 0 时写入 act 声明，表示由该管理员模拟登录
+func (s *UserService) generateToken(userID uint, uuid string, expiresAt time.Time, actorID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"uuid":    uuid,
-		"exp":     time.Now().Add(expire).Unix(),
+		"iat":     time.Now().Unix(),
+		"exp":     expiresAt.Unix(),
+	}
+	if actorID > 0 {
+		claims["act"] = map[string]interface{}{"user_id": actorID}
+	}
+	if s.cfg.JWT.Issuer != "" {
+		claims["iss"] = s.cfg.JWT.Issuer
+	}
+	if s.cfg.JWT.Audience != "" {
+		claims["aud"] = s.cfg.JWT.Audience
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.Secret))
+	return token.SignedString([]byte(s.cfg.JWT.Secret))
+}
+
+// parseExpire 解析 Token 有效期，留空或无效时使用默认值（配置加载时已校验）
+func parseExpire(value string, fallback time.Duration) time.Duration {
+	expire, err := time.ParseDuration(value)
+	if err != nil || expire <= 0 {
+		return fallback
+	}
+	return expire
 }
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 批量导入中单行的处理结果
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportError   = "error"
+)
+
+// tempPasswordLength 生成的临时密码长度，密码策略要求更长时使用策略的最小长度
+const tempPasswordLength = 16
+
+// ImportUserRow 批量导入的一行，Password 为空时生成临时密码
+type ImportUserRow struct {
+	Index    int
+	Username string
+	Email    string
+	Role     string
+	Password string
+}
+
+// ImportUserResult 单行导入结果
+type ImportUserResult struct {
+	Index        int    `json:"index" example:"0"` // 在请求 users 中的下标
+	Username     string `json:"username" example:"johndoe"`
+	Email        string `json:"email" example:"john@example.com"`
+	Status       string `json:"status" example:"created" enums:"created,skipped,error"`
+	Reason       string `json:"reason,omitempty" example:"用户名已存在"`
+	UserID       uint   `json:"user_id,omitempty" example:"1"`
+	TempPassword string `json:"temp_password,omitempty" example:"xK3#pQ9!mZ2$wR7a"` // 仅在未提供密码时返回，只显示这一次
+}
+
+// ImportUsers 管理员批量创建用户，所有用户在同一事务中写入；
+// 用户名或邮箱已存在（包括同一批次中重复）的行跳过，不满足密码策略的行记为错误，均不影响其他行。
+// 写入数据库失败时整个事务回滚，不返回逐行结果
+func (s *UserService) ImportUsers(rows []ImportUserRow) ([]ImportUserResult, error) {
+	results := make([]ImportUserResult, len(rows))
+	// users 与 rows 一一对应，nil 表示该行已跳过或出错
+	users := make([]*model.User, len(rows))
+	usernames := make(map[string]bool, len(rows))
+	emails := make(map[string]bool, len(rows))
+
+	// 校验与密码哈希在事务外完成：bcrypt 每次需要数十毫秒，放在事务内会长时间占用连接与锁
+	for i, row := range rows {
+		results[i] = ImportUserResult{Index: row.Index, Username: row.Username, Email: row.Email}
+
+		reason, err := s.importDuplicate(s.repo, row, usernames, emails)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			results[i].Status = ImportSkipped
+			results[i].Reason = reason
+			continue
+		}
+
+		password := row.Password
+		if password == "" {
+			if password, err = generateTempPassword(&s.cfg.Security.PasswordPolicy); err != nil {
+				return nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+			}
+			results[i].TempPassword = password
+		} else if err := checkPasswordPolicy(&s.cfg.Security.PasswordPolicy, password); err != nil {
+			results[i].Status = ImportError
+			results[i].Reason = errcode.FromError(err).Msg
+			continue
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, errcode.NewWithMsg(errcode.ErrInternal, err.Error())
+		}
+		role := row.Role
+		if role == "" {
+			role = model.RoleUser
+		}
+		users[i] = &model.User{
+			Username: row.Username,
+			Password: string(hashedPassword),
+			Email:    row.Email,
+			Role:     role,
+		}
+		usernames[row.Username] = true
+		emails[row.Email] = true
+	}
+
+	err := s.repo.Transaction(func(repo *repository.UserRepository) error {
+		for i, user := range users {
+			if user == nil {
+				continue
+			}
+			// 事务外检查之后可能已有用户通过注册占用了用户名或邮箱
+			reason, err := s.importDuplicate(repo, rows[i], nil, nil)
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				results[i].Status = ImportSkipped
+				results[i].Reason = reason
+				results[i].TempPassword = ""
+				continue
+			}
+			if err := repo.CreateUser(user); err != nil {
+				return errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+			}
+			results[i].Status = ImportCreated
+			results[i].UserID = user.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errcode.FromError(err)
+	}
+	return results, nil
+}
+
+// importDuplicate 检查用户名与邮箱是否已在本批次或数据库中被占用，返回跳过原因；未占用时返回空字符串，
+// usernames/emails 为 nil 时只检查数据库
+func (s *UserService) importDuplicate(repo *repository.UserRepository, row ImportUserRow, usernames, emails map[string]bool) (string, error) {
+	if usernames[row.Username] {
+		return "用户名在本次导入中重复", nil
+	}
+	if emails[row.Email] {
+		return "邮箱在本次导入中重复", nil
+	}
+	if _, err := repo.GetUserByUsername(row.Username); err == nil {
+		return errcode.ErrUserExists.Message(), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	if _, err := repo.GetUserByEmail(row.Email); err == nil {
+		return errcode.ErrEmailExists.Message(), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errcode.NewWithMsg(errcode.ErrDatabase, err.Error())
+	}
+	return "", nil
+}
+
+// 临时密码字符集，去掉了容易混淆的 0/O、1/l/I
+const (
+	tempPasswordUpper  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	tempPasswordLower  = "abcdefghijkmnopqrstuvwxyz"
+	tempPasswordDigit  = "23456789"
+	tempPasswordSymbol = "!@#$%^&*-_=+"
+)
+
+// generateTempPassword 生成满足密码策略的随机临时密码，每类字符至少包含一个
+func generateTempPassword(policy *config.PasswordPolicy) (string, error) {
+	length := tempPasswordLength
+	if policy.MinLength > length {
+		length = min(policy.MinLength, maxPasswordBytes)
+	}
+
+	sets := []string{tempPasswordUpper, tempPasswordLower, tempPasswordDigit, tempPasswordSymbol}
+	all := tempPasswordUpper + tempPasswordLower + tempPasswordDigit + tempPasswordSymbol
+	password := make([]byte, length)
+	for i := range password {
+		charset := all
+		if i < len(sets) {
+			charset = sets[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// 打乱顺序，避免固定位置的字符类型
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+// randomChar 从字符集中随机取一个字符
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return charset[n.Int64()], nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/internal/repository"
+	"github.com/cuihe500/astro/pkg/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestUserService 创建使用独立内存数据库的 UserService
+func newTestUserService(t *testing.T) *UserService {
+	t.Helper()
+	db, err := repository.NewDB(&config.DatabaseConfig{Driver: config.DBDriverSQLite, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.Security.PasswordPolicy = config.PasswordPolicy{MinLength: 8, RequireLower: true, RequireDigit: true}
+	return &UserService{cfg: cfg, repo: repository.NewUserRepository(db)}
+}
+
+func TestImportUsers(t *testing.T) {
+	s := newTestUserService(t)
+	if err := s.repo.CreateUser(&model.User{Username: "existing", Password: "x", Email: "existing@example.com"}); err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	rows := []ImportUserRow{
+		{Index: 0, Username: "alice", Email: "alice@example.com"},
+		{Index: 1, Username: "bob", Email: "bob@example.com", Password: "password123", Role: model.RoleAdmin},
+		{Index: 2, Username: "alice", Email: "alice2@example.com"},
+		{Index: 3, Username: "carol", Email: "alice@example.com"},
+		{Index: 4, Username: "existing", Email: "new@example.com"},
+		{Index: 5, Username: "dave", Email: "existing@example.com"},
+		{Index: 6, Username: "erin", Email: "erin@example.com", Password: "short"},
+		// 密码不合规的行不占用用户名，后续同名行仍可创建
+		{Index: 7, Username: "erin", Email: "erin2@example.com", Password: "password456"},
+	}
+	results, err := s.ImportUsers(rows)
+	if err != nil {
+		t.Fatalf("ImportUsers() error = %v", err)
+	}
+
+	want := []struct {
+		status   string
+		tempPass bool
+	}{
+		{ImportCreated, true},
+		{ImportCreated, false},
+		{ImportSkipped, false},
+		{ImportSkipped, false},
+		{ImportSkipped, false},
+		{ImportSkipped, false},
+		{ImportError, false},
+		{ImportCreated, false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Index != i || r.Status != w.status {
+			t.Errorf("results[%d] = {index: %d, status: %s, reason: %s}, want status %s", i, r.Index, r.Status, r.Reason, w.status)
+		}
+		if (r.TempPassword != "") != w.tempPass {
+			t.Errorf("results[%d].TempPassword = %q, want set = %v", i, r.TempPassword, w.tempPass)
+		}
+		if w.status != ImportCreated && r.Reason == "" {
+			t.Errorf("results[%d] 缺少原因", i)
+		}
+	}
+
+	// 生成的临时密码满足密码策略且可用于登录
+	alice, err := s.repo.GetUserByUsername("alice")
+	if err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if err := checkPasswordPolicy(&s.cfg.Security.PasswordPolicy, results[0].TempPassword); err != nil {
+		t.Errorf("临时密码不满足密码策略: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(alice.Password), []byte(results[0].TempPassword)); err != nil {
+		t.Errorf("临时密码与保存的哈希不匹配: %v", err)
+	}
+	if alice.Role != model.RoleUser {
+		t.Errorf("alice.Role = %q, want %q", alice.Role, model.RoleUser)
+	}
+	bob, err := s.repo.GetUserByUsername("bob")
+	if err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if bob.Role != model.RoleAdmin {
+		t.Errorf("bob.Role = %q, want %q", bob.Role, model.RoleAdmin)
+	}
+}
+
+func TestGenerateTempPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  config.PasswordPolicy
+		wantLen int
+	}{
+		{"默认长度", config.PasswordPolicy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}, tempPasswordLength},
+		{"策略要求更长", config.PasswordPolicy{MinLength: 24, RequireSymbol: true}, 24},
+		{"不超过 bcrypt 上限", config.PasswordPolicy{MinLength: 100}, maxPasswordBytes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := generateTempPassword(&tt.policy)
+			if err != nil {
+				t.Fatalf("generateTempPassword() error = %v", err)
+			}
+			if len(password) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(password), tt.wantLen)
+			}
+			if tt.policy.MinLength <= maxPasswordBytes {
+				if err := checkPasswordPolicy(&tt.policy, password); err != nil {
+					t.Errorf("不满足密码策略: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/cuihe500/astro/internal/model"
+	"github.com/cuihe500/astro/pkg/errcode"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLogin(t *testing.T) {
+	s := newTestUserService(t)
+	s.cfg.JWT.Secret = "test-secret"
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := []*model.User{
+		{Username: "alice", Password: string(hash), Email: "alice@example.com"},
+		// 用户名禁止包含 @ 之前注册的账号
+		{Username: "legacy@corp", Password: string(hash), Email: "legacy@example.com"},
+	}
+	for _, u := range users {
+		if err := s.repo.CreateUser(u); err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		identifier string
+		password   string
+		wantUser   string
+		wantErr    errcode.Code
+	}{
+		{"用户名登录", "alice", "password123", "alice", errcode.Success},
+		{"邮箱登录", "alice@example.com", "password123", "alice", errcode.Success},
+		{"包含 @ 的旧用户名", "legacy@corp", "password123", "legacy@corp", errcode.Success},
+		{"旧用户的邮箱登录", "legacy@example.com", "password123", "legacy@corp", errcode.Success},
+		{"用户名密码错误", "alice", "wrong", "", errcode.ErrLoginFailed},
+		{"邮箱密码错误", "alice@example.com", "wrong", "", errcode.ErrLoginFailed},
+		{"用户不存在", "nobody", "password123", "", errcode.ErrLoginFailed},
+		{"邮箱不存在", "nobody@example.com", "password123", "", errcode.ErrLoginFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, user, err := s.Login(tt.identifier, tt.password, false)
+			if tt.wantErr != errcode.Success {
+				if err == nil || errcode.FromError(err).Code != tt.wantErr {
+					t.Fatalf("Login() error = %v, want code %d", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			if user.Username != tt.wantUser {
+				t.Errorf("user = %q, want %q", user.Username, tt.wantUser)
+			}
+			if token == "" {
+				t.Error("token 为空")
+			}
+		})
+	}
+}
@@ -1,44 +1,285 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// 运行模式
+const (
+	// ModeDemo 演示模式：使用内存 SQLite 与模拟 K8s 集群，无需任何外部依赖
+	ModeDemo = "demo"
 )
 
 type Config struct {
+	// Mode 运行模式，留空为正常模式，demo 为演示模式；也可通过环境变量 ASTRO_MODE 指定
+	Mode       string           `mapstructure:"mode"`
 	Server     ServerConfig     `mapstructure:"server"`
 	Database   DatabaseConfig   `mapstructure:"database"`
 	JWT        JWTConfig        `mapstructure:"jwt"`
 	Log        LogConfig        `mapstructure:"log"`
 	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
+	Image      ImageConfig      `mapstructure:"image"`
+	Quota      QuotaConfig      `mapstructure:"quota"`
+	Build      BuildConfig      `mapstructure:"build"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	App        AppConfig        `mapstructure:"app"`
+}
+
+// AppConfig 应用创建参数限制
+type AppConfig struct {
+	// DefaultReplicas 创建时未指定副本数的默认值，默认 1
+	DefaultReplicas int `mapstructure:"default_replicas"`
+	// MinReplicas/MaxReplicas 单个应用副本数范围，默认 0~10，创建和启动时校验
+	MinReplicas int `mapstructure:"min_replicas"`
+	MaxReplicas int `mapstructure:"max_replicas"`
+	// NameScope 应用名唯一范围：user（默认，同一用户内唯一）/global（全平台唯一）。
+	// single、per-team 命名空间策略下不同用户共用命名空间，同名应用会在集群中冲突，建议使用 global
+	NameScope string `mapstructure:"name_scope"`
+	// RestartAlert 容器重启告警
+	RestartAlert RestartAlertConfig `mapstructure:"restart_alert"`
+}
+
+// RestartAlertConfig 容器重启告警配置，同步应用状态时发现重启次数增长达到阈值即告警。
+// 开启后后台按 CheckInterval 定期同步所有未挂起应用的状态，无人访问的应用也能触发告警
+type RestartAlertConfig struct {
+	// Threshold 自上次告警以来所有 Pod 新增的重启次数达到该值时告警，0 表示关闭
+	Threshold int `mapstructure:"threshold"`
+	// Interval 同一应用两次告警的最短间隔（如 10m），留空为 10m
+	Interval string `mapstructure:"interval"`
+	// CheckInterval 后台检查所有应用重启次数的周期（如 1m），留空为 1m
+	CheckInterval string `mapstructure:"check_interval"`
+	// Webhook 告警时以 JSON POST 通知的地址，留空只记录日志
+	Webhook string `mapstructure:"webhook"`
+}
+
+// 应用名唯一范围
+const (
+	AppNameScopeUser   = "user"
+	AppNameScopeGlobal = "global"
+)
+
+// SecurityConfig 账号安全配置
+type SecurityConfig struct {
+	// HideUserEnumeration 防止通过注册探测用户是否存在：注册已存在的用户名或邮箱时同样返回成功（实际不创建）。
+	// 代价是用户重复注册时得不到明确提示，默认关闭便于开发调试；登录的响应时间始终拉平，不受此开关影响
+	HideUserEnumeration bool `mapstructure:"hide_user_enumeration"`
+	// PasswordPolicy 密码强度要求
+	PasswordPolicy PasswordPolicy `mapstructure:"password_policy"`
+}
+
+// PasswordPolicy 密码强度策略，默认要求至少 8 位且包含字母和数字
+type PasswordPolicy struct {
+	MinLength     int  `mapstructure:"min_length"`
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
 }
 
 // KubernetesConfig K8s 客户端配置
 type KubernetesConfig struct {
 	// Kubeconfig 文件路径，留空则使用集群内配置 (InClusterConfig)
 	Kubeconfig string `mapstructure:"kubeconfig"`
+	// NamespaceStrategy 命名空间分配策略: per-user（默认，每用户独立）、single（所有应用共用）、per-team（按团队共用）
+	NamespaceStrategy string `mapstructure:"namespace_strategy"`
+	// Namespace single 策略下使用的命名空间
+	Namespace string `mapstructure:"namespace"`
+	// NamespacePrefix per-user 策略的命名空间前缀，命名空间为 <前缀><用户 ID>，留空为 astro-user-
+	NamespacePrefix string `mapstructure:"namespace_prefix"`
+	// ImagePullPolicy 默认镜像拉取策略（Always/IfNotPresent/Never），留空由 K8s 根据 tag 决定
+	ImagePullPolicy string `mapstructure:"image_pull_policy"`
+	// SecurityContext 应用容器默认安全上下文，可被创建请求覆盖
+	SecurityContext SecurityContextConfig `mapstructure:"security_context"`
+	// DefaultLabels 附加到平台创建的命名空间与应用资源（Deployment/StatefulSet/CronJob/Service）上的标签，如成本中心、环境；
+	// 优先级低于应用自身标签，app、managed-by 等平台标签不会被覆盖。配置加载会将标签名转为小写
+	DefaultLabels map[string]string `mapstructure:"default_labels"`
+	// AllowDegraded 启动时集群不可达是否仍启动服务（应用相关接口不可用，/ready 返回 503），默认直接退出
+	AllowDegraded bool `mapstructure:"allow_degraded"`
+	// BreakerThreshold 连续多少次连接失败后熔断 K8s 调用，0 使用默认值 5
+	BreakerThreshold int `mapstructure:"breaker_threshold"`
+	// BreakerCooldown 熔断持续时间（如 30s），期间直接返回 K8s 连接失败，结束后放行一次探测
+	BreakerCooldown string `mapstructure:"breaker_cooldown"`
+	// RevisionHistoryLimit Deployment 默认保留的历史版本数，供回滚使用，留空使用 K8s 默认值（10）
+	RevisionHistoryLimit *int32 `mapstructure:"revision_history_limit"`
+	// ProgressDeadlineSeconds Deployment 默认滚动更新超时秒数，超时视为发布失败，留空使用 K8s 默认值（600）
+	ProgressDeadlineSeconds *int32 `mapstructure:"progress_deadline_seconds"`
+	// MinReadySeconds 新 Pod 持续就绪多少秒后才视为可用，滚动更新据此决定何时替换下一个旧 Pod，0 表示就绪即可用
+	MinReadySeconds int32 `mapstructure:"min_ready_seconds"`
+	// TerminationMessagePolicy 容器终止消息策略（File/FallbackToLogsOnError），默认 FallbackToLogsOnError：
+	// 容器异常退出且未写终止消息文件时，以日志末尾作为终止消息，便于在 Pod 状态中直接看到崩溃原因
+	TerminationMessagePolicy string `mapstructure:"termination_message_policy"`
+	// TerminationMessagePath 容器终止消息文件路径，留空使用 K8s 默认值（/dev/termination-log）
+	TerminationMessagePath string `mapstructure:"termination_message_path"`
+}
+
+// 命名空间分配策略
+const (
+	NamespacePerUser = "per-user"
+	NamespaceSingle  = "single"
+	NamespacePerTeam = "per-team"
+)
+
+// SecurityContextConfig 容器安全上下文配置
+type SecurityContextConfig struct {
+	RunAsNonRoot           bool     `mapstructure:"run_as_non_root"`
+	RunAsUser              *int64   `mapstructure:"run_as_user"` // 留空不指定
+	ReadOnlyRootFilesystem bool     `mapstructure:"read_only_root_filesystem"`
+	DropCapabilities       []string `mapstructure:"drop_capabilities"` // 如 ["ALL"]
+}
+
+// ImageConfig 镜像策略配置
+type ImageConfig struct {
+	// AllowedRepos 允许的镜像仓库前缀（如 docker.io/library、registry.example.com），为空或包含 "*" 时不限制
+	AllowedRepos []string `mapstructure:"allowed_repos"`
+	// DeniedPatterns 禁止的镜像正则表达式，优先于 AllowedRepos；同时匹配原始引用与补全默认仓库后的引用
+	DeniedPatterns []string `mapstructure:"denied_patterns"`
+
+	// deniedRegexps DeniedPatterns 编译后的正则，由 Compile 生成
+	deniedRegexps []*regexp.Regexp
+}
+
+// Compile 编译 DeniedPatterns，任一正则无效时返回错误
+func (c *ImageConfig) Compile() error {
+	regexps := make([]*regexp.Regexp, 0, len(c.DeniedPatterns))
+	for _, pattern := range c.DeniedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("image.denied_patterns 中的正则 %q 无效: %w", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	c.deniedRegexps = regexps
+	return nil
+}
+
+// DeniedRegexps 返回编译后的禁止镜像正则，需先调用 Compile（Load 已调用）
+func (c *ImageConfig) DeniedRegexps() []*regexp.Regexp {
+	return c.deniedRegexps
+}
+
+// BuildConfig 源码构建配置，启用后可从 Git 仓库构建镜像并部署
+type BuildConfig struct {
+	// Enabled 是否启用源码构建，默认关闭
+	Enabled bool `mapstructure:"enabled"`
+	// BuilderImage 构建镜像，留空使用 Kaniko 官方镜像
+	BuilderImage string `mapstructure:"builder_image"`
+	// Registry 构建产物推送的仓库前缀（如 registry.example.com/astro），镜像名为 <registry>/<namespace>/<app>:<时间戳>
+	Registry string `mapstructure:"registry"`
+	// PushSecret 推送凭证，dockerconfigjson 类型 Secret 的名称，需预先存在于应用命名空间
+	PushSecret string `mapstructure:"push_secret"`
+	// Insecure 目标仓库是否使用 HTTP
+	Insecure bool `mapstructure:"insecure"`
+	// Timeout 单次构建超时（如 30m），留空为 30m
+	Timeout string `mapstructure:"timeout"`
+}
+
+// QuotaConfig 用户配额配置，0 表示不限制
+type QuotaConfig struct {
+	MaxAppsPerUser     int `mapstructure:"max_apps_per_user"`     // 每个用户最多应用数
+	MaxReplicasPerUser int `mapstructure:"max_replicas_per_user"` // 每个用户所有应用副本数之和上限
+	// MaxConcurrentCreates 每个用户同时进行的创建应用请求数，超出时直接拒绝，默认 3
+	MaxConcurrentCreates int `mapstructure:"max_concurrent_creates"`
 }
 
 type ServerConfig struct {
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
+	// TimeZone 日志时间使用的时区（IANA 名称，如 Asia/Shanghai），留空为 UTC；不影响数据库时间解析（见 database.loc）
+	TimeZone string `mapstructure:"time_zone"`
+	// MaxBodyBytes 请求体大小上限（字节），0 使用默认值 8MB
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
+	// MaxLogLines 单次查询应用日志的最大行数，0 使用默认值 10000
+	MaxLogLines int64 `mapstructure:"max_log_lines"`
+	// Gzip 响应压缩
+	Gzip GzipConfig `mapstructure:"gzip"`
+	// TrustedProxies 受信任的反向代理 IP 或 CIDR，仅来自这些地址的请求才采信 X-Forwarded-For/X-Real-IP 作为客户端 IP。
+	// 留空表示不信任任何代理，客户端 IP 取连接对端地址；配置过宽（如 0.0.0.0/0）会让客户端伪造 IP 绕过审计与限流
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// EnableSwagger 是否开放 /swagger 接口文档，留空时 release 模式关闭、其他模式开启
+	EnableSwagger *bool `mapstructure:"enable_swagger"`
+	// SwaggerAuth 开放接口文档时的 Basic Auth 凭证，留空不校验
+	SwaggerAuth BasicAuthConfig `mapstructure:"swagger_auth"`
+}
+
+// BasicAuthConfig HTTP Basic Auth 凭证
+type BasicAuthConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
 }
 
+// GzipConfig 响应压缩配置
+type GzipConfig struct {
+	// Enabled 是否启用 gzip 压缩（需客户端声明 Accept-Encoding: gzip）
+	Enabled bool `mapstructure:"enabled"`
+	// MinSize 小于该字节数的响应不压缩，0 使用默认值 1024
+	MinSize int `mapstructure:"min_size"`
+	// ContentTypes 允许压缩的响应类型，留空为 JSON、文本、HTML、CSS、JS
+	ContentTypes []string `mapstructure:"content_types"`
+}
+
+// 数据库 TLS 模式
+const (
+	DBTLSDisable  = "disable"
+	DBTLSRequire  = "require"
+	DBTLSVerifyCA = "verify-ca"
+)
+
+// 数据库驱动
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	// Driver 数据库驱动: mysql（默认）/postgres/sqlite，sqlite 的数据库文件为 DBName，留空使用内存数据库
+	Driver   string `mapstructure:"driver"`
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	Charset  string `mapstructure:"charset"`
+	// TLS 连接加密模式: disable（默认）/require（加密但不校验证书）/verify-ca（校验服务端证书）
+	TLS string `mapstructure:"tls"`
+	// CACert verify-ca 模式下的 CA 证书路径，留空使用系统根证书
+	CACert string `mapstructure:"ca_cert"`
+	// ParseTime 是否将 DATETIME 解析为 time.Time，默认 true
+	ParseTime *bool `mapstructure:"parse_time"`
+	// Loc 时间解析使用的时区，默认 Local（进程所在时区）
+	Loc string `mapstructure:"loc"`
+	// Params 追加到 DSN 的额外参数，如 timeout、readTimeout
+	Params map[string]string `mapstructure:"params"`
+	// AutoMigrate 启动时是否自动同步表结构，生产环境建议关闭并使用 cmd/migrate 显式迁移
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+	// ConnectAttempts 启动时连接数据库的最大尝试次数，0 表示仅尝试一次
+	ConnectAttempts int `mapstructure:"connect_attempts"`
+	// ConnectInterval 首次重试间隔（如 2s），之后每次翻倍，最长 30s
+	ConnectInterval string `mapstructure:"connect_interval"`
 }
 
 type JWTConfig struct {
 	Secret string `mapstructure:"secret"`
 	Expire string `mapstructure:"expire"`
+	// RememberExpire 登录勾选"记住我"时的 Token 有效期，留空为 720h
+	RememberExpire string `mapstructure:"remember_expire"`
+	// Issuer/Audience 签发时写入 iss/aud 并在认证时校验，避免共用密钥的其他服务签发的 Token 被接受；
+	// 默认留空不写入也不校验，启用后此前签发的不带 iss/aud 的 Token 全部失效
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
 }
 
 type LogConfig struct {
 	Level      string `mapstructure:"level"`
+	Format     string `mapstructure:"format"` // 控制台输出格式: console（默认，人类可读）/ json（便于日志采集）
 	File       string `mapstructure:"file"`
 	MaxSize    int    `mapstructure:"max_size"`    // 单个日志文件最大大小(MB)
 	MaxBackups int    `mapstructure:"max_backups"` // 保留旧日志文件数量
@@ -46,11 +287,21 @@ type LogConfig struct {
 	Compress   bool   `mapstructure:"compress"`    // 是否压缩归档日志
 }
 
-var GlobalConfig *Config
-
 // Load 加载配置文件
 func Load(path string) (*Config, error) {
 	viper.SetConfigFile(path)
+	if err := viper.BindEnv("mode", "ASTRO_MODE"); err != nil {
+		return nil, err
+	}
+	viper.SetDefault("database.auto_migrate", true)
+	viper.SetDefault("kubernetes.termination_message_policy", "FallbackToLogsOnError")
+	viper.SetDefault("app.default_replicas", 1)
+	viper.SetDefault("app.min_replicas", 0)
+	viper.SetDefault("app.max_replicas", 10)
+	viper.SetDefault("quota.max_concurrent_creates", 3)
+	viper.SetDefault("security.password_policy.min_length", 8)
+	viper.SetDefault("security.password_policy.require_lower", true)
+	viper.SetDefault("security.password_policy.require_digit", true)
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
@@ -61,6 +312,118 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
-	GlobalConfig = &cfg
+	for key, value := range map[string]string{
+		"jwt.expire":                       cfg.JWT.Expire,
+		"jwt.remember_expire":              cfg.JWT.RememberExpire,
+		"kubernetes.breaker_cooldown":      cfg.Kubernetes.BreakerCooldown,
+		"build.timeout":                    cfg.Build.Timeout,
+		"app.restart_alert.interval":       cfg.App.RestartAlert.Interval,
+		"app.restart_alert.check_interval": cfg.App.RestartAlert.CheckInterval,
+	} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s 不是有效的时长: %q", key, value)
+		}
+	}
+	switch cfg.Mode {
+	case "":
+	case ModeDemo:
+		// 演示模式固定使用内存数据库，进程退出后数据即丢失
+		cfg.Database = DatabaseConfig{Driver: DBDriverSQLite, AutoMigrate: true}
+	default:
+		return nil, fmt.Errorf("mode 仅支持留空或 demo: %s", cfg.Mode)
+	}
+	switch cfg.Database.Driver {
+	case "", DBDriverMySQL, DBDriverPostgres, DBDriverSQLite:
+	default:
+		return nil, fmt.Errorf("database.driver 仅支持 mysql/postgres/sqlite: %s", cfg.Database.Driver)
+	}
+	switch cfg.Database.TLS {
+	case "", DBTLSDisable, DBTLSRequire, DBTLSVerifyCA:
+	default:
+		return nil, fmt.Errorf("database.tls 仅支持 disable/require/verify-ca: %s", cfg.Database.TLS)
+	}
+	if v := cfg.Kubernetes.RevisionHistoryLimit; v != nil && *v < 0 {
+		return nil, fmt.Errorf("kubernetes.revision_history_limit 不能为负数: %d", *v)
+	}
+	if v := cfg.Kubernetes.ProgressDeadlineSeconds; v != nil && *v <= 0 {
+		return nil, fmt.Errorf("kubernetes.progress_deadline_seconds 必须为正整数: %d", *v)
+	}
+	if v := cfg.Kubernetes.MinReadySeconds; v < 0 {
+		return nil, fmt.Errorf("kubernetes.min_ready_seconds 不能为负数: %d", v)
+	}
+	switch cfg.Kubernetes.TerminationMessagePolicy {
+	case "", "File", "FallbackToLogsOnError":
+	default:
+		return nil, fmt.Errorf("kubernetes.termination_message_policy 仅支持 File/FallbackToLogsOnError: %s", cfg.Kubernetes.TerminationMessagePolicy)
+	}
+	if v := cfg.Kubernetes.TerminationMessagePath; v != "" && !strings.HasPrefix(v, "/") {
+		return nil, fmt.Errorf("kubernetes.termination_message_path 必须为绝对路径: %s", v)
+	}
+	if cfg.Server.EnableSwagger == nil {
+		enabled := cfg.Server.Mode != "release"
+		cfg.Server.EnableSwagger = &enabled
+	}
+	if a := cfg.Server.SwaggerAuth; (a.Username == "") != (a.Password == "") {
+		return nil, fmt.Errorf("server.swagger_auth 的 username 与 password 需同时配置")
+	}
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return nil, fmt.Errorf("server.trusted_proxies 不是有效的 IP 或 CIDR: %s", proxy)
+			}
+		}
+	}
+	for key, value := range cfg.Kubernetes.DefaultLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("kubernetes.default_labels 标签名 %q 无效: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("kubernetes.default_labels 标签 %q 的值无效: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	switch cfg.Kubernetes.NamespaceStrategy {
+	case "", NamespacePerUser, NamespacePerTeam:
+	case NamespaceSingle:
+		if ns := cfg.Kubernetes.Namespace; ns != "" {
+			if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+				return nil, fmt.Errorf("kubernetes.namespace 无效: %s", strings.Join(errs, "; "))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("kubernetes.namespace_strategy 仅支持 per-user/single/per-team: %s", cfg.Kubernetes.NamespaceStrategy)
+	}
+	// 前缀拼接用户 ID 后必须是合法的 DNS 标签
+	if p := cfg.Kubernetes.NamespacePrefix; p != "" {
+		if errs := validation.IsDNS1123Label(p + "1"); len(errs) > 0 {
+			return nil, fmt.Errorf("kubernetes.namespace_prefix 无效: %s", strings.Join(errs, "; "))
+		}
+	}
+	if a := cfg.App; a.MinReplicas < 0 || a.MinReplicas > a.MaxReplicas ||
+		a.DefaultReplicas < a.MinReplicas || a.DefaultReplicas > a.MaxReplicas {
+		return nil, fmt.Errorf("app 副本数配置无效，需满足 0 <= min_replicas <= default_replicas <= max_replicas")
+	}
+	switch cfg.App.NameScope {
+	case "", AppNameScopeUser, AppNameScopeGlobal:
+	default:
+		return nil, fmt.Errorf("app.name_scope 仅支持 user/global: %s", cfg.App.NameScope)
+	}
+	if cfg.App.RestartAlert.Threshold < 0 {
+		return nil, fmt.Errorf("app.restart_alert.threshold 不能为负数: %d", cfg.App.RestartAlert.Threshold)
+	}
+	if w := cfg.App.RestartAlert.Webhook; w != "" {
+		if u, err := url.Parse(w); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("app.restart_alert.webhook 不是有效的 http(s) 地址: %s", w)
+		}
+	}
+	if err := cfg.Image.Compile(); err != nil {
+		return nil, err
+	}
+	if cfg.Build.Enabled && cfg.Build.Registry == "" {
+		return nil, fmt.Errorf("启用源码构建时必须配置 build.registry")
+	}
+
 	return &cfg, nil
 }
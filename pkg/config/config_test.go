@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageConfigCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		denied  []string
+		wantErr bool
+	}{
+		{"无规则", nil, false},
+		{"有效正则", []string{"^docker\\.io/", ":latest$"}, false},
+		{"无效正则", []string{"("}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ImageConfig{DeniedPatterns: tt.denied}
+			err := cfg.Compile()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(cfg.DeniedRegexps()) != len(tt.denied) {
+				t.Errorf("编译后的正则数量 = %d, want %d", len(cfg.DeniedRegexps()), len(tt.denied))
+			}
+		})
+	}
+}
+
+func TestLoadNamespaceStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		namespace string
+		wantErr   bool
+	}{
+		{"默认", "", "", false},
+		{"按用户", NamespacePerUser, "", false},
+		{"按团队", NamespacePerTeam, "", false},
+		{"共用命名空间", NamespaceSingle, "astro-apps", false},
+		{"共用命名空间名称无效", NamespaceSingle, "Astro_Apps", true},
+		{"未知策略", "per-region", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := fmt.Sprintf("kubernetes:\n  namespace_strategy: %q\n  namespace: %q\n", tt.strategy, tt.namespace)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRestartAlert(t *testing.T) {
+	tests := []struct {
+		name          string
+		threshold     int
+		interval      string
+		checkInterval string
+		webhook       string
+		wantErr       bool
+	}{
+		{"关闭", 0, "", "", "", false},
+		{"只记录日志", 3, "10m", "30s", "", false},
+		{"通知 Webhook", 3, "", "", "https://alert.example.com/hook", false},
+		{"阈值为负数", -1, "", "", "", true},
+		{"间隔无效", 3, "10", "", "", true},
+		{"检查周期无效", 3, "", "-1m", "", true},
+		{"Webhook 不是 http 地址", 3, "", "", "ftp://alert.example.com", true},
+		{"Webhook 缺少主机", 3, "", "", "https://", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := fmt.Sprintf("app:\n  restart_alert:\n    threshold: %d\n    interval: %q\n    check_interval: %q\n    webhook: %q\n",
+				tt.threshold, tt.interval, tt.checkInterval, tt.webhook)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package errcode
 
+import (
+	"errors"
+	"fmt"
+)
+
 // 错误码枚举
 // 错误码规则:
 //   - 0: 成功
@@ -9,90 +14,77 @@ package errcode
 
 type Code int
 
-const (
+var (
 	// 成功
-	Success Code = 0
+	Success = register(0, "成功")
 
 	// 客户端错误 1xxxx
-	ErrBadRequest   Code = 10001 // 请求参数错误
-	ErrUnauthorized Code = 10002 // 未登录或 Token 无效
-	ErrForbidden    Code = 10003 // 无权限访问
-	ErrNotFound     Code = 10004 // 资源不存在
+	ErrBadRequest     = register(10001, "请求参数错误")
+	ErrUnauthorized   = register(10002, "未登录或 Token 无效")
+	ErrForbidden      = register(10003, "无权限访问")
+	ErrNotFound       = register(10004, "资源不存在")
+	ErrBodyTooLarge   = register(10005, "请求体过大")
+	ErrInProgress     = register(10006, "相同请求正在处理中，请稍后重试")
+	ErrTooManyCreates = register(10007, "同时创建的应用过多，请等待已有创建完成后重试")
 
 	// 用户相关错误 2xxxx
-	ErrUserExists      Code = 20001 // 用户已存在
-	ErrUserNotFound    Code = 20002 // 用户不存在
-	ErrPasswordWrong   Code = 20003 // 密码错误
-	ErrEmailExists     Code = 20004 // 邮箱已被使用
-	ErrUserDisabled    Code = 20005 // 用户已被禁用
-	ErrInvalidUsername Code = 20006 // 用户名格式无效
-	ErrInvalidPassword Code = 20007 // 密码格式无效
-	ErrInvalidEmail    Code = 20008 // 邮箱格式无效
-	ErrLoginFailed     Code = 20009 // 登录失败
-	ErrRegisterFailed  Code = 20010 // 注册失败
-	ErrTokenExpired    Code = 20011 // Token 已过期
-	ErrTokenInvalid    Code = 20012 // Token 无效
+	ErrUserExists      = register(20001, "用户已存在")
+	ErrUserNotFound    = register(20002, "用户不存在")
+	ErrPasswordWrong   = register(20003, "密码错误")
+	ErrEmailExists     = register(20004, "邮箱已被使用")
+	ErrUserDisabled    = register(20005, "用户已被禁用")
+	ErrInvalidUsername = register(20006, "用户名格式无效")
+	ErrInvalidPassword = register(20007, "密码格式无效")
+	ErrInvalidEmail    = register(20008, "邮箱格式无效")
+	ErrLoginFailed     = register(20009, "登录失败")
+	ErrRegisterFailed  = register(20010, "注册失败")
+	ErrTokenExpired    = register(20011, "Token 已过期")
+	ErrTokenInvalid    = register(20012, "Token 无效")
 
 	// 应用相关错误 21xxx
-	ErrAppNotFound     Code = 21001 // 应用不存在
-	ErrAppExists       Code = 21002 // 应用已存在
-	ErrAppCreateFail   Code = 21003 // 创建应用失败
-	ErrAppUpdateFail   Code = 21004 // 更新应用失败
-	ErrAppDeleteFail   Code = 21005 // 删除应用失败
-	ErrAppStartFail    Code = 21006 // 启动应用失败
-	ErrAppStopFail     Code = 21007 // 停止应用失败
-	ErrAppRestartFail  Code = 21008 // 重启应用失败
-	ErrAppCreateFailed Code = 21009 // 创建应用失败（别名）
+	ErrAppNotFound         = register(21001, "应用不存在")
+	ErrAppExists           = register(21002, "应用已存在")
+	ErrAppCreateFail       = register(21003, "创建应用失败")
+	ErrAppUpdateFail       = register(21004, "更新应用失败")
+	ErrAppDeleteFail       = register(21005, "删除应用失败")
+	ErrAppStartFail        = register(21006, "启动应用失败")
+	ErrAppStopFail         = register(21007, "停止应用失败")
+	ErrAppRestartFail      = register(21008, "重启应用失败")
+	ErrAppCreateFailed     = register(21009, "创建应用失败") // 创建 K8s 资源失败，取值已返回给客户端，不与 ErrAppCreateFail 合并
+	ErrImageNotAllowed     = register(21010, "镜像不允许使用")
+	ErrQuotaExceeded       = register(21011, "超出配额限制")
+	ErrAppNotReady         = register(21012, "等待应用就绪超时")
+	ErrRevisionNotFound    = register(21013, "历史版本不存在")
+	ErrRegistryAuthFailed  = register(21014, "镜像仓库认证失败")
+	ErrRegistryUnreachable = register(21015, "镜像仓库无法访问")
+	ErrBuildDisabled       = register(21016, "未启用源码构建")
+
+	// 应用组相关错误 22xxx
+	ErrStackNotFound = register(22001, "应用组不存在")
+	ErrStackExists   = register(22002, "应用组已存在")
 
 	// 系统错误 3xxxx
-	ErrInternal     Code = 30001 // 服务器内部错误
-	ErrDatabase     Code = 30002 // 数据库错误
-	ErrK8s          Code = 30003 // K8s 操作错误
-	ErrK8sConnect   Code = 30004 // K8s 连接失败
-	ErrK8sOperation Code = 30005 // K8s 操作失败
+	ErrInternal     = register(30001, "服务器内部错误")
+	ErrDatabase     = register(30002, "数据库错误")
+	ErrK8s          = register(30003, "K8s 操作错误")
+	ErrK8sConnect   = register(30004, "K8s 连接失败")
+	ErrK8sOperation = register(30005, "K8s 操作失败")
+	ErrK8sForbidden = register(30006, "集群拒绝了该操作")     // K8s 拒绝操作（权限不足或准入策略拒绝）
+	ErrK8sConflict  = register(30007, "集群资源冲突，请稍后重试") // K8s 资源冲突（已存在或被并发修改）
+	ErrK8sInvalid   = register(30008, "集群资源规格无效")     // K8s 资源规格无效
+	ErrK8sQuota     = register(30009, "超出集群资源配额")     // 超出命名空间资源配额
 )
 
-// codeMessages 错误码对应的默认消息
-var codeMessages = map[Code]string{
-	Success: "成功",
-
-	// 客户端错误
-	ErrBadRequest:   "请求参数错误",
-	ErrUnauthorized: "未登录或 Token 无效",
-	ErrForbidden:    "无权限访问",
-	ErrNotFound:     "资源不存在",
-
-	// 用户相关错误
-	ErrUserExists:      "用户已存在",
-	ErrUserNotFound:    "用户不存在",
-	ErrPasswordWrong:   "密码错误",
-	ErrEmailExists:     "邮箱已被使用",
-	ErrUserDisabled:    "用户已被禁用",
-	ErrInvalidUsername: "用户名格式无效",
-	ErrInvalidPassword: "密码格式无效",
-	ErrInvalidEmail:    "邮箱格式无效",
-	ErrLoginFailed:     "登录失败",
-	ErrRegisterFailed:  "注册失败",
-	ErrTokenExpired:    "Token 已过期",
-	ErrTokenInvalid:    "Token 无效",
-
-	// 应用相关错误
-	ErrAppNotFound:     "应用不存在",
-	ErrAppExists:       "应用已存在",
-	ErrAppCreateFail:   "创建应用失败",
-	ErrAppUpdateFail:   "更新应用失败",
-	ErrAppDeleteFail:   "删除应用失败",
-	ErrAppStartFail:    "启动应用失败",
-	ErrAppStopFail:     "停止应用失败",
-	ErrAppRestartFail:  "重启应用失败",
-	ErrAppCreateFailed: "创建应用失败",
-
-	// 系统错误
-	ErrInternal:     "服务器内部错误",
-	ErrDatabase:     "数据库错误",
-	ErrK8s:          "K8s 操作错误",
-	ErrK8sConnect:   "K8s 连接失败",
-	ErrK8sOperation: "K8s 操作失败",
+// codeMessages 错误码对应的默认消息，由 register 填充
+var codeMessages = map[Code]string{}
+
+// register 定义错误码及其默认消息，错误码重复时启动即 panic，保证每个错误码都有唯一的消息
+func register(code Code, message string) Code {
+	if _, exists := codeMessages[code]; exists {
+		panic(fmt.Sprintf("错误码 %d 重复定义", code))
+	}
+	codeMessages[code] = message
+	return code
 }
 
 // Int 返回错误码的整数值
@@ -112,6 +104,8 @@ func (c Code) Message() string {
 type Error struct {
 	Code Code
 	Msg  string
+	// Data 错误详情，随响应的 data 字段返回，便于客户端按原因处理
+	Data interface{}
 }
 
 // Error 实现 error 接口
@@ -132,12 +126,18 @@ func NewWithMsg(code Code, msg string) *Error {
 	return &Error{Code: code, Msg: msg}
 }
 
+// NewWithData 创建带自定义消息与错误详情的错误
+func NewWithData(code Code, msg string, data interface{}) *Error {
+	return &Error{Code: code, Msg: msg, Data: data}
+}
+
 // FromError 从 error 中提取错误码，如果不是 Error 类型则返回 ErrInternal
 func FromError(err error) *Error {
 	if err == nil {
 		return nil
 	}
-	if e, ok := err.(*Error); ok {
+	var e *Error
+	if errors.As(err, &e) {
 		return e
 	}
 	return &Error{Code: ErrInternal, Msg: err.Error()}
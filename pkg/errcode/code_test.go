@@ -0,0 +1,100 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    Code
+		wantMsg string
+	}{
+		{"错误码", New(ErrAppNotFound), ErrAppNotFound, "应用不存在"},
+		{"自定义消息", NewWithMsg(ErrK8sOperation, "扩容失败"), ErrK8sOperation, "扩容失败"},
+		{"包装后的错误码", fmt.Errorf("创建应用: %w", New(ErrAppCreateFailed)), ErrAppCreateFailed, "创建应用失败"},
+		{"普通错误", errors.New("boom"), ErrInternal, "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := FromError(tt.err)
+			if e.Code != tt.want || e.Msg != tt.wantMsg {
+				t.Errorf("FromError() = %d %q, want %d %q", e.Code, e.Msg, tt.want, tt.wantMsg)
+			}
+		})
+	}
+
+	if FromError(nil) != nil {
+		t.Error("FromError(nil) 应返回 nil")
+	}
+}
+
+// TestReferencedCodes 服务与处理器层引用的错误码都要有定义与独立的消息，改名或删除时在这里暴露
+func TestReferencedCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		code Code
+		want int
+	}{
+		{"ErrK8s", ErrK8s, 30003},
+		{"ErrK8sConnect", ErrK8sConnect, 30004},
+		{"ErrK8sOperation", ErrK8sOperation, 30005},
+		{"ErrAppCreateFail", ErrAppCreateFail, 21003},
+		{"ErrAppCreateFailed", ErrAppCreateFailed, 21009},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.code.Int() != tt.want {
+				t.Errorf("%s = %d, want %d", tt.name, tt.code.Int(), tt.want)
+			}
+			if msg := tt.code.Message(); msg == "" || msg == "未知错误" {
+				t.Errorf("%s 缺少消息", tt.name)
+			}
+		})
+	}
+}
+
+func TestCodeMessages(t *testing.T) {
+	if len(codeMessages) == 0 {
+		t.Fatal("没有注册任何错误码")
+	}
+	for code, msg := range codeMessages {
+		if msg == "" {
+			t.Errorf("错误码 %d 的消息为空", code)
+		}
+		if code.Message() != msg {
+			t.Errorf("错误码 %d 的 Message() = %q, want %q", code, code.Message(), msg)
+		}
+	}
+	if got := Code(99999).Message(); got != "未知错误" {
+		t.Errorf("未注册的错误码 Message() = %q, want 未知错误", got)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      Code
+		wantPanic bool
+	}{
+		{"重复的错误码", ErrBadRequest, true},
+		{"新的错误码", Code(99998), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer delete(codeMessages, Code(99998))
+			defer func() {
+				if got := recover() != nil; got != tt.wantPanic {
+					t.Errorf("panic = %v, want %v", got, tt.wantPanic)
+				}
+			}()
+			register(tt.code, "测试")
+		})
+	}
+	if ErrBadRequest.Message() != "请求参数错误" {
+		t.Error("重复注册不应覆盖原有消息")
+	}
+}
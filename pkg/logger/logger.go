@@ -3,6 +3,7 @@ package logger
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cuihe500/astro/pkg/config"
 	"go.uber.org/zap"
@@ -12,49 +13,26 @@ import (
 
 var defaultLogger *zap.Logger
 
-// Init 初始化日志系统
-func Init(cfg *config.LogConfig) error {
+// Init 初始化日志系统，日志时间按 location 输出，为 nil 时使用 UTC
+func Init(cfg *config.LogConfig, location *time.Location) error {
+	if location == nil {
+		location = time.UTC
+	}
+
 	// 解析日志级别
 	level, err := zapcore.ParseLevel(cfg.Level)
 	if err != nil {
 		level = zapcore.InfoLevel
 	}
 
-	// 控制台编码器配置（人类可读格式）
-	consoleEncoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalColorLevelEncoder, // 彩色大写级别
-		EncodeTime:     zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05"),
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-	}
-
-	// JSON 文件编码器配置
-	jsonEncoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.LowercaseLevelEncoder, // 小写级别
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.SecondsDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-	}
+	consoleConfig := consoleEncoderConfig(location)
+	jsonConfig := jsonEncoderConfig(location)
 
 	// 创建输出核心
 	var cores []zapcore.Core
 
-	// 控制台输出（人类可读的优雅模式）
-	consoleEncoder := zapcore.NewConsoleEncoder(consoleEncoderConfig)
+	// 控制台输出：json 格式供日志采集，console 格式人类可读
+	consoleEncoder := newConsoleEncoder(cfg.Format, isTerminal(os.Stdout), consoleConfig, jsonConfig)
 	consoleCore := zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), level)
 	cores = append(cores, consoleCore)
 
@@ -89,7 +67,7 @@ func Init(cfg *config.LogConfig) error {
 			Compress:   cfg.Compress,
 		}
 
-		fileEncoder := zapcore.NewJSONEncoder(jsonEncoderConfig)
+		fileEncoder := zapcore.NewJSONEncoder(jsonConfig)
 		fileCore := zapcore.NewCore(fileEncoder, zapcore.AddSync(writer), level)
 		cores = append(cores, fileCore)
 	}
@@ -103,6 +81,68 @@ func Init(cfg *config.LogConfig) error {
 	return nil
 }
 
+// consoleEncoderConfig 控制台编码器配置（人类可读格式）
+func consoleEncoderConfig(location *time.Location) zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalColorLevelEncoder, // 彩色大写级别
+		EncodeTime:     timeEncoderIn(zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05"), location),
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// jsonEncoderConfig JSON 编码器配置，用于文件输出与 json 格式的控制台输出
+func jsonEncoderConfig(location *time.Location) zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder, // 小写级别
+		EncodeTime:     timeEncoderIn(zapcore.ISO8601TimeEncoder, location),
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// newConsoleEncoder 选择控制台编码器：json 格式供日志采集，其他格式人类可读；
+// color 为 false（如容器 stdout 被采集）时不输出颜色控制符
+func newConsoleEncoder(format string, color bool, consoleConfig, jsonConfig zapcore.EncoderConfig) zapcore.Encoder {
+	if format == "json" {
+		return zapcore.NewJSONEncoder(jsonConfig)
+	}
+	if !color {
+		consoleConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(consoleConfig)
+}
+
+// timeEncoderIn 将日志时间转换到指定时区后再编码，不修改进程的 time.Local
+func timeEncoderIn(encode zapcore.TimeEncoder, location *time.Location) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		encode(t.In(location), enc)
+	}
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // Default 返回默认 Logger
 func Default() *zap.Logger {
 	if defaultLogger == nil {
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// stringArrayEncoder 记录编码后的时间字符串
+type stringArrayEncoder struct {
+	zapcore.PrimitiveArrayEncoder
+	value string
+}
+
+func (e *stringArrayEncoder) AppendString(v string) { e.value = v }
+
+func TestTimeEncoderIn(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+	moment := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	layout := zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		want     string
+	}{
+		{"UTC", time.UTC, "2024-01-02 03:04:05"},
+		{"Asia/Shanghai", shanghai, "2024-01-02 11:04:05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := time.Local
+			enc := &stringArrayEncoder{}
+			timeEncoderIn(layout, tt.location)(moment, enc)
+			if enc.value != tt.want {
+				t.Errorf("编码结果 = %q, want %q", enc.value, tt.want)
+			}
+			if time.Local != local {
+				t.Error("不应修改 time.Local")
+			}
+		})
+	}
+}
+
+func TestNewConsoleEncoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		color     bool
+		wantJSON  bool
+		wantColor bool
+	}{
+		{"默认格式终端输出", "", true, false, true},
+		{"console 终端输出", "console", true, false, true},
+		{"console 非终端输出", "console", false, false, false},
+		{"json 终端输出", "json", true, true, false},
+		{"json 非终端输出", "json", false, true, false},
+	}
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: "hello"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := newConsoleEncoder(tt.format, tt.color, consoleEncoderConfig(time.UTC), jsonEncoderConfig(time.UTC))
+			buf, err := enc.EncodeEntry(entry, nil)
+			if err != nil {
+				t.Fatalf("EncodeEntry() error = %v", err)
+			}
+			out := buf.String()
+			if got := strings.HasPrefix(out, "{"); got != tt.wantJSON {
+				t.Errorf("JSON 输出 = %v, want %v: %q", got, tt.wantJSON, out)
+			}
+			if got := strings.Contains(out, "\x1b["); got != tt.wantColor {
+				t.Errorf("颜色控制符 = %v, want %v: %q", got, tt.wantColor, out)
+			}
+		})
+	}
+}
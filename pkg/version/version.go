@@ -0,0 +1,28 @@
+package version
+
+import "runtime"
+
+// 构建信息，通过 -ldflags "-X github.com/cuihe500/astro/pkg/version.Version=..." 注入
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version" example:"v1.0.0"`
+	GitCommit string `json:"git_commit" example:"e0bbfe3"`
+	BuildDate string `json:"build_date" example:"2025-12-11T00:00:00Z"`
+	GoVersion string `json:"go_version" example:"go1.25.0"`
+}
+
+// Get 返回当前构建信息
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+}